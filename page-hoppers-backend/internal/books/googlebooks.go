@@ -0,0 +1,120 @@
+package books
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultGoogleBooksURL is the public Google Books API
+const DefaultGoogleBooksURL = "https://www.googleapis.com/books/v1"
+
+// GoogleBooksProvider searches the Google Books volumes API
+type GoogleBooksProvider struct {
+	BaseURL string
+	APIKey  string // optional; raises the anonymous quota
+	Client  *http.Client
+}
+
+func NewGoogleBooksProvider(baseURL, apiKey string) *GoogleBooksProvider {
+	if baseURL == "" {
+		baseURL = DefaultGoogleBooksURL
+	}
+	return &GoogleBooksProvider{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *GoogleBooksProvider) Name() string {
+	return "googlebooks"
+}
+
+// googleVolume is the subset of a volumes response item we use
+type googleVolume struct {
+	VolumeInfo struct {
+		Title               string   `json:"title"`
+		Authors             []string `json:"authors"`
+		PublishedDate       string   `json:"publishedDate"`
+		PageCount           int      `json:"pageCount"`
+		IndustryIdentifiers []struct {
+			Type       string `json:"type"`
+			Identifier string `json:"identifier"`
+		} `json:"industryIdentifiers"`
+		ImageLinks struct {
+			Thumbnail string `json:"thumbnail"`
+		} `json:"imageLinks"`
+	} `json:"volumeInfo"`
+}
+
+func (p *GoogleBooksProvider) LookupISBN(ctx context.Context, isbn13 string) (*SearchResult, error) {
+	results, err := p.Search(ctx, "isbn:"+isbn13, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return &results[0], nil
+}
+
+func (p *GoogleBooksProvider) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	// Google caps maxResults at 40
+	if limit > 40 {
+		limit = 40
+	}
+	params.Set("maxResults", strconv.Itoa(limit))
+	if p.APIKey != "" {
+		params.Set("key", p.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/volumes?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: google books returned %d", ErrUpstreamUnavailable, resp.StatusCode)
+	}
+
+	var body struct {
+		Items []googleVolume `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+
+	results := make([]SearchResult, 0, len(body.Items))
+	for _, item := range body.Items {
+		info := item.VolumeInfo
+		result := SearchResult{
+			Title:     info.Title,
+			Authors:   info.Authors,
+			PageCount: info.PageCount,
+			CoverURL:  info.ImageLinks.Thumbnail,
+		}
+		for _, id := range info.IndustryIdentifiers {
+			if id.Type == "ISBN_13" || id.Type == "ISBN_10" {
+				result.ISBNs = append(result.ISBNs, id.Identifier)
+			}
+		}
+		if len(info.PublishedDate) >= 4 {
+			result.FirstPublishYear, _ = strconv.Atoi(info.PublishedDate[:4])
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
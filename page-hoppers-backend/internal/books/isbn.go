@@ -0,0 +1,111 @@
+package books
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidISBN is returned for ISBNs with the wrong length, characters or check digit
+var ErrInvalidISBN = errors.New("invalid ISBN")
+
+// NormalizeISBN strips hyphens and spaces and upper-cases a trailing X check digit
+func NormalizeISBN(isbn string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(isbn) {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsValidISBN10 checks length and the mod-11 check digit (which may be X for 10)
+func IsValidISBN10(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+	if len(isbn) != 10 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var digit int
+		switch {
+		case isbn[i] >= '0' && isbn[i] <= '9':
+			digit = int(isbn[i] - '0')
+		case isbn[i] == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// IsValidISBN13 checks length, the 978/979 prefix and the mod-10 check digit
+func IsValidISBN13(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+	if len(isbn) != 13 || !(strings.HasPrefix(isbn, "978") || strings.HasPrefix(isbn, "979")) {
+		return false
+	}
+	for i := 0; i < 13; i++ {
+		if isbn[i] < '0' || isbn[i] > '9' {
+			return false
+		}
+	}
+	return isbn13CheckDigit(isbn[:12]) == isbn[12]
+}
+
+// ISBN10To13 converts a valid ISBN-10 to its 978-prefixed ISBN-13
+func ISBN10To13(isbn string) (string, error) {
+	isbn = NormalizeISBN(isbn)
+	if !IsValidISBN10(isbn) {
+		return "", ErrInvalidISBN
+	}
+	body := "978" + isbn[:9]
+	return body + string(isbn13CheckDigit(body)), nil
+}
+
+// ISBN13To10 converts a valid 978-prefixed ISBN-13 to ISBN-10.
+// 979-prefixed ISBNs have no ISBN-10 form.
+func ISBN13To10(isbn string) (string, error) {
+	isbn = NormalizeISBN(isbn)
+	if !IsValidISBN13(isbn) || !strings.HasPrefix(isbn, "978") {
+		return "", ErrInvalidISBN
+	}
+	body := isbn[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X", nil
+	}
+	return body + string(rune('0'+check)), nil
+}
+
+// ParseISBN accepts either form, with or without hyphens, and returns the ISBN-13
+func ParseISBN(isbn string) (string, error) {
+	isbn = NormalizeISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		return ISBN10To13(isbn)
+	case 13:
+		if IsValidISBN13(isbn) {
+			return isbn, nil
+		}
+	}
+	return "", ErrInvalidISBN
+}
+
+func isbn13CheckDigit(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(first12[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
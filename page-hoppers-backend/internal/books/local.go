@@ -0,0 +1,71 @@
+package books
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// LocalCatalogProvider searches the admin-curated books in our own database,
+// so search keeps working with no network access
+type LocalCatalogProvider struct {
+	DB *gorm.DB
+}
+
+func NewLocalCatalogProvider(db *gorm.DB) *LocalCatalogProvider {
+	return &LocalCatalogProvider{DB: db}
+}
+
+func (p *LocalCatalogProvider) Name() string {
+	return "local"
+}
+
+func (p *LocalCatalogProvider) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	pattern := repository.ContainsPattern(strings.TrimSpace(query))
+
+	var catalog []models.Book
+	if err := p.DB.WithContext(ctx).
+		Where("curated = ?", true).
+		Where(`LOWER(title) LIKE ? ESCAPE '\' OR LOWER(author) LIKE ? ESCAPE '\' OR isbn = ?`, pattern, pattern, NormalizeISBN(query)).
+		Order("title").
+		Limit(limit).
+		Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(catalog))
+	for _, book := range catalog {
+		results = append(results, bookToResult(book))
+	}
+	return results, nil
+}
+
+func (p *LocalCatalogProvider) LookupISBN(ctx context.Context, isbn13 string) (*SearchResult, error) {
+	var book models.Book
+	if p.DB.WithContext(ctx).Where("curated = ? AND isbn = ?", true, isbn13).Limit(1).Find(&book).RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	result := bookToResult(book)
+	return &result, nil
+}
+
+func bookToResult(book models.Book) SearchResult {
+	result := SearchResult{
+		Title:          book.Title,
+		OpenLibraryKey: book.OpenLibraryKey,
+		CoverID:        book.CoverID,
+		PageCount:      book.PageCount,
+		Series:         book.Series,
+	}
+	if book.Author != "" {
+		result.Authors = []string{book.Author}
+	}
+	if book.ISBN != "" {
+		result.ISBNs = []string{book.ISBN}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package books
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultOpenLibraryURL is the public Open Library API
+const DefaultOpenLibraryURL = "https://openlibrary.org"
+
+// OpenLibraryProvider searches the Open Library search API
+type OpenLibraryProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewOpenLibraryProvider(baseURL string) *OpenLibraryProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenLibraryURL
+	}
+	return &OpenLibraryProvider{
+		BaseURL: baseURL,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *OpenLibraryProvider) Name() string {
+	return "openlibrary"
+}
+
+// openLibraryDoc is the subset of a search.json document we use
+type openLibraryDoc struct {
+	Key              string   `json:"key"`
+	Title            string   `json:"title"`
+	AuthorName       []string `json:"author_name"`
+	CoverI           *int     `json:"cover_i"`
+	ISBN             []string `json:"isbn"`
+	FirstPublishYear int      `json:"first_publish_year"`
+	NumberOfPages    int      `json:"number_of_pages_median"`
+}
+
+func (p *OpenLibraryProvider) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	return p.search(ctx, params, limit)
+}
+
+func (p *OpenLibraryProvider) LookupISBN(ctx context.Context, isbn13 string) (*SearchResult, error) {
+	params := url.Values{}
+	params.Set("isbn", isbn13)
+	results, err := p.search(ctx, params, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return &results[0], nil
+}
+
+func (p *OpenLibraryProvider) search(ctx context.Context, params url.Values, limit int) ([]SearchResult, error) {
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("fields", "key,title,author_name,cover_i,isbn,first_publish_year,number_of_pages_median")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/search.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: open library returned %d", ErrUpstreamUnavailable, resp.StatusCode)
+	}
+
+	var body struct {
+		Docs []openLibraryDoc `json:"docs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+
+	results := make([]SearchResult, 0, len(body.Docs))
+	for _, doc := range body.Docs {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, SearchResult{
+			Title:            doc.Title,
+			Authors:          doc.AuthorName,
+			OpenLibraryKey:   doc.Key,
+			CoverID:          doc.CoverI,
+			ISBNs:            doc.ISBN,
+			FirstPublishYear: doc.FirstPublishYear,
+			PageCount:        doc.NumberOfPages,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,52 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUpstreamUnavailable is returned when a provider can't be reached or answers with an error
+var ErrUpstreamUnavailable = errors.New("book provider unavailable")
+
+// ErrNotFound is returned by LookupISBN when the provider has no such book
+var ErrNotFound = errors.New("book not found")
+
+// PartialError is returned by Registry.Search together with the results that did come back when some
+// providers failed. The results are worth showing, but they aren't the full answer, so they shouldn't be cached.
+type PartialError struct {
+	Failed []string // names of the providers that failed
+	Err    error    // the last of their errors
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("book providers %s failed: %v", strings.Join(e.Failed, ", "), e.Err)
+}
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// SearchResult is one book as returned by a metadata provider
+type SearchResult struct {
+	Title            string   `json:"title"`
+	Authors          []string `json:"authors,omitempty"`
+	OpenLibraryKey   string   `json:"open_library_key,omitempty"`
+	CoverID          *int     `json:"cover_id,omitempty"`
+	ISBNs            []string `json:"isbns,omitempty"`
+	FirstPublishYear int      `json:"first_publish_year,omitempty"`
+	PageCount        int      `json:"page_count,omitempty"`
+	Series           string   `json:"series,omitempty"`    // from the curated catalog; outside sources don't say reliably
+	CoverURL         string   `json:"cover_url,omitempty"` // for providers without Open Library cover IDs
+
+	// Sources names the provider each field came from, e.g. {"title": "local", "cover_id": "openlibrary"}
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+// BookProvider looks up book metadata from an outside source such as Open Library
+type BookProvider interface {
+	// Name identifies the provider in cache rows and logs
+	Name() string
+	Search(ctx context.Context, query string, limit int) ([]SearchResult, error)
+	// LookupISBN returns the single book with the given ISBN-13, or ErrNotFound
+	LookupISBN(ctx context.Context, isbn13 string) (*SearchResult, error)
+}
@@ -0,0 +1,239 @@
+package books
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Registry queries several providers in priority order and merges their results.
+// It is itself a BookProvider, so the search cache can sit in front of it.
+type Registry struct {
+	providers []BookProvider
+}
+
+func NewRegistry(providers ...BookProvider) *Registry {
+	return &Registry{providers: providers}
+}
+
+// ProviderConfig holds the settings the built-in providers need
+type ProviderConfig struct {
+	OpenLibraryURL    string
+	GoogleBooksURL    string
+	GoogleBooksAPIKey string
+}
+
+// NewRegistryFromNames builds a registry from provider names ("local", "openlibrary", "googlebooks")
+// listed highest priority first
+func NewRegistryFromNames(db *gorm.DB, names []string, cfg ProviderConfig) (*Registry, error) {
+	var providers []BookProvider
+	for _, name := range names {
+		switch strings.TrimSpace(strings.ToLower(name)) {
+		case "":
+			continue
+		case "local":
+			providers = append(providers, NewLocalCatalogProvider(db))
+		case "openlibrary":
+			providers = append(providers, NewOpenLibraryProvider(cfg.OpenLibraryURL))
+		case "googlebooks":
+			providers = append(providers, NewGoogleBooksProvider(cfg.GoogleBooksURL, cfg.GoogleBooksAPIKey))
+		default:
+			return nil, fmt.Errorf("unknown book provider %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no book providers configured")
+	}
+	return NewRegistry(providers...), nil
+}
+
+func (r *Registry) Name() string {
+	names := make([]string, 0, len(r.providers))
+	for _, p := range r.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, "+")
+}
+
+// Search asks every provider and merges the answers. The search fails when none of them answer;
+// when only some fail, the others' results come back with a *PartialError naming the ones that failed.
+func (r *Registry) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	merger := newResultMerger()
+	answered := 0
+	var failed []string
+	var lastErr error
+
+	for _, provider := range r.providers {
+		results, err := provider.Search(ctx, query, limit)
+		if err != nil {
+			log.Printf("Book provider %s failed for %q: %v", provider.Name(), query, err)
+			failed = append(failed, provider.Name())
+			lastErr = err
+			continue
+		}
+		answered++
+		for _, result := range results {
+			merger.add(provider.Name(), result)
+		}
+	}
+
+	if answered == 0 {
+		return nil, lastErr
+	}
+
+	results := merger.results()
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	if len(failed) > 0 {
+		return results, &PartialError{Failed: failed, Err: lastErr}
+	}
+	return results, nil
+}
+
+// LookupISBN asks every provider for the ISBN and merges whatever they know about it
+func (r *Registry) LookupISBN(ctx context.Context, isbn13 string) (*SearchResult, error) {
+	merger := newResultMerger()
+	answered := 0
+	var lastErr error
+
+	for _, provider := range r.providers {
+		result, err := provider.LookupISBN(ctx, isbn13)
+		if err == ErrNotFound {
+			answered++
+			continue
+		}
+		if err != nil {
+			log.Printf("Book provider %s failed for ISBN %s: %v", provider.Name(), isbn13, err)
+			lastErr = err
+			continue
+		}
+		answered++
+		// Providers don't always echo the ISBN back; make sure every answer merges on it
+		result.ISBNs = append([]string{isbn13}, result.ISBNs...)
+		merger.add(provider.Name(), *result)
+	}
+
+	if answered == 0 {
+		return nil, lastErr
+	}
+
+	results := merger.results()
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return &results[0], nil
+}
+
+// resultMerger folds results that share an ISBN (or Open Library key) into one entry.
+// Providers are added in priority order, so the first provider to supply a field wins it.
+type resultMerger struct {
+	merged []*SearchResult
+	byKey  map[string]*SearchResult
+}
+
+func newResultMerger() *resultMerger {
+	return &resultMerger{byKey: map[string]*SearchResult{}}
+}
+
+func mergeKeys(result SearchResult) []string {
+	var keys []string
+	for _, isbn := range result.ISBNs {
+		// Key on the ISBN-13 form so an ISBN-10 from one provider meets the ISBN-13 from another
+		if isbn13, err := ParseISBN(isbn); err == nil {
+			keys = append(keys, "isbn:"+isbn13)
+		} else if normalized := NormalizeISBN(isbn); normalized != "" {
+			keys = append(keys, "isbn:"+normalized)
+		}
+	}
+	if result.OpenLibraryKey != "" {
+		keys = append(keys, "ol:"+result.OpenLibraryKey)
+	}
+	return keys
+}
+
+func (m *resultMerger) add(provider string, result SearchResult) {
+	keys := mergeKeys(result)
+
+	var target *SearchResult
+	for _, key := range keys {
+		if existing, ok := m.byKey[key]; ok {
+			target = existing
+			break
+		}
+	}
+
+	if target == nil {
+		target = &SearchResult{Sources: map[string]string{}}
+		m.merged = append(m.merged, target)
+	}
+	mergeInto(target, provider, result)
+
+	for _, key := range mergeKeys(*target) {
+		m.byKey[key] = target
+	}
+}
+
+func (m *resultMerger) results() []SearchResult {
+	results := make([]SearchResult, 0, len(m.merged))
+	for _, result := range m.merged {
+		results = append(results, *result)
+	}
+	return results
+}
+
+// mergeInto fills the fields target is missing from result and records where each came from
+func mergeInto(target *SearchResult, provider string, result SearchResult) {
+	if target.Title == "" && result.Title != "" {
+		target.Title = result.Title
+		target.Sources["title"] = provider
+	}
+	if len(target.Authors) == 0 && len(result.Authors) > 0 {
+		target.Authors = result.Authors
+		target.Sources["authors"] = provider
+	}
+	if target.OpenLibraryKey == "" && result.OpenLibraryKey != "" {
+		target.OpenLibraryKey = result.OpenLibraryKey
+		target.Sources["open_library_key"] = provider
+	}
+	if target.CoverID == nil && result.CoverID != nil {
+		target.CoverID = result.CoverID
+		target.Sources["cover_id"] = provider
+	}
+	if target.CoverURL == "" && result.CoverURL != "" {
+		target.CoverURL = result.CoverURL
+		target.Sources["cover_url"] = provider
+	}
+	if target.FirstPublishYear == 0 && result.FirstPublishYear != 0 {
+		target.FirstPublishYear = result.FirstPublishYear
+		target.Sources["first_publish_year"] = provider
+	}
+	if target.PageCount == 0 && result.PageCount != 0 {
+		target.PageCount = result.PageCount
+		target.Sources["page_count"] = provider
+	}
+	if target.Series == "" && result.Series != "" {
+		target.Series = result.Series
+		target.Sources["series"] = provider
+	}
+
+	// ISBNs are unioned rather than won; the first provider to add any is recorded
+	seen := map[string]bool{}
+	for _, isbn := range target.ISBNs {
+		seen[NormalizeISBN(isbn)] = true
+	}
+	for _, isbn := range result.ISBNs {
+		normalized := NormalizeISBN(isbn)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		target.ISBNs = append(target.ISBNs, normalized)
+		if _, ok := target.Sources["isbns"]; !ok {
+			target.Sources["isbns"] = provider
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package books
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// DefaultCacheTTL is how long a search stays fresh before the provider is asked again
+const DefaultCacheTTL = 24 * time.Hour
+
+// searchFetchLimit is how many results are fetched and cached per query; callers get a prefix of these
+const searchFetchLimit = 20
+
+// Where a search response came from
+const (
+	SourceUpstream   = "upstream"
+	SourceCache      = "cache"
+	SourceStaleCache = "stale_cache"
+	SourcePartial    = "partial" // some providers failed; served but not cached
+)
+
+// SearchResponse is what the search endpoint returns
+type SearchResponse struct {
+	Results   []SearchResult `json:"results"`
+	Source    string         `json:"source"`
+	FetchedAt time.Time      `json:"fetched_at"`
+}
+
+// SearchService answers book searches from the database cache, falling back to the provider
+type SearchService struct {
+	DB       *gorm.DB
+	Provider BookProvider
+	TTL      time.Duration
+	Now      func() time.Time
+}
+
+func NewSearchService(db *gorm.DB, provider BookProvider, ttl time.Duration) *SearchService {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &SearchService{
+		DB:       db,
+		Provider: provider,
+		TTL:      ttl,
+		Now:      time.Now,
+	}
+}
+
+// NormalizeQuery lowercases and collapses whitespace so equivalent searches share a cache row
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Search returns up to limit results for query. Fresh cache rows are served directly; otherwise the
+// provider is asked and the answer cached. If the provider fails, an expired cache row is served instead.
+// So is one in place of a partial answer, which is otherwise served as SourcePartial without caching it,
+// so an outage can't leave a cut-down answer in the cache for a whole TTL.
+func (s *SearchService) Search(ctx context.Context, query string, limit int) (*SearchResponse, error) {
+	query = NormalizeQuery(query)
+	if limit <= 0 || limit > searchFetchLimit {
+		limit = searchFetchLimit
+	}
+
+	var cached models.BookSearchCache
+	hasCache := s.DB.Where("provider = ? AND query = ?", s.Provider.Name(), query).Limit(1).Find(&cached).RowsAffected > 0
+
+	if hasCache && s.Now().Before(cached.ExpiresAt) {
+		return s.cachedResponse(&cached, SourceCache, limit)
+	}
+
+	results, err := s.Provider.Search(ctx, query, searchFetchLimit)
+	var partial *PartialError
+	if errors.As(err, &partial) {
+		log.Printf("Book search %q via %s was partial: %v", query, s.Provider.Name(), err)
+		if hasCache {
+			log.Printf("Serving stale book search cache for %q from %s", query, cached.FetchedAt.Format(time.RFC3339))
+			return s.cachedResponse(&cached, SourceStaleCache, limit)
+		}
+		return &SearchResponse{Results: truncate(results, limit), Source: SourcePartial, FetchedAt: s.Now()}, nil
+	}
+	if err != nil {
+		log.Printf("Book search %q via %s failed: %v", query, s.Provider.Name(), err)
+		if hasCache {
+			log.Printf("Serving stale book search cache for %q from %s", query, cached.FetchedAt.Format(time.RFC3339))
+			return s.cachedResponse(&cached, SourceStaleCache, limit)
+		}
+		return nil, err
+	}
+
+	fetchedAt := s.Now()
+	if err := s.store(query, results, fetchedAt); err != nil {
+		// A cache write failure shouldn't fail the search itself
+		log.Printf("Failed to cache book search %q: %v", query, err)
+	}
+
+	return &SearchResponse{Results: truncate(results, limit), Source: SourceUpstream, FetchedAt: fetchedAt}, nil
+}
+
+func (s *SearchService) cachedResponse(cached *models.BookSearchCache, source string, limit int) (*SearchResponse, error) {
+	var results []SearchResult
+	if err := json.Unmarshal([]byte(cached.Results), &results); err != nil {
+		return nil, errors.New("corrupt book search cache entry")
+	}
+	return &SearchResponse{Results: truncate(results, limit), Source: source, FetchedAt: cached.FetchedAt}, nil
+}
+
+// store saves the results for query and records each result's cover metadata in the book catalog
+func (s *SearchService) store(query string, results []SearchResult, fetchedAt time.Time) error {
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		entry := models.BookSearchCache{
+			Provider:  s.Provider.Name(),
+			Query:     query,
+			Results:   string(encoded),
+			FetchedAt: fetchedAt,
+			ExpiresAt: fetchedAt.Add(s.TTL),
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "query"}},
+			DoUpdates: clause.AssignmentColumns([]string{"results", "fetched_at", "expires_at", "updated_at"}),
+		}).Create(&entry).Error; err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if result.OpenLibraryKey == "" {
+				continue
+			}
+			if _, err := repository.FindOrCreateBook(tx, models.Book{
+				Title:          result.Title,
+				Author:         strings.Join(result.Authors, ", "),
+				OpenLibraryKey: result.OpenLibraryKey,
+				CoverID:        result.CoverID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func truncate(results []SearchResult, limit int) []SearchResult {
+	if results == nil {
+		return []SearchResult{}
+	}
+	if len(results) > limit {
+		return results[:limit]
+	}
+	return results
+}
+
+// ResolveISBN returns the catalog book for an ISBN in either form. Books already in the catalog
+// are returned without asking the providers; otherwise the providers' answer is saved to the catalog.
+func (s *SearchService) ResolveISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn13, err := ParseISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	var book models.Book
+	if s.DB.Where("isbn = ?", isbn13).Limit(1).Find(&book).RowsAffected > 0 {
+		return &book, nil
+	}
+
+	result, err := s.Provider.LookupISBN(ctx, isbn13)
+	if err != nil {
+		log.Printf("ISBN lookup %s via %s failed: %v", isbn13, s.Provider.Name(), err)
+		return nil, err
+	}
+
+	return repository.FindOrCreateBook(s.DB, models.Book{
+		Title:          result.Title,
+		Author:         strings.Join(result.Authors, ", "),
+		OpenLibraryKey: result.OpenLibraryKey,
+		ISBN:           isbn13,
+		CoverID:        result.CoverID,
+		PageCount:      result.PageCount,
+		Series:         result.Series,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// ---------------------------
+// List every badge that can currently be earned
+func (h *ReadingLogHandler) GetAchievementRules(c *gin.Context) {
+	var rules []models.AchievementRule
+	if err := h.DB.Where("disabled = ?", false).Order("id ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// ---------------------------
+// List the badges a child has earned (the child or their parent)
+func (h *ReadingLogHandler) GetChildAchievements(c *gin.Context) {
+	_, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return
+	}
+
+	if !canViewChild(c, h.DB, childID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
+	}
+
+	earned, err := repository.GetChildAchievements(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, earned)
+}
+
+// awardAchievements evaluates the badge rules after a change to the child's reading has been saved.
+// A failure is logged rather than failing the request that triggered it; the next change retries.
+func (h *ReadingLogHandler) awardAchievements(childID uint) []models.ChildAchievement {
+	awarded, err := repository.EvaluateAchievements(h.DB, childID, time.Now())
+	if err != nil {
+		log.Printf("Failed to evaluate achievements for child %d: %v", childID, err)
+		return nil
+	}
+	return awarded
+}
@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// adminAuditLogSize is how many of the most recent entries GetAdminAuditLog returns
+const adminAuditLogSize = 200
+
+// AdminUser is an account as the support tools show it, deactivated and deleted ones included
+type AdminUser struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email,omitempty"`
+	Role            string     `json:"role"`
+	ParentID        *uint      `json:"parent_id,omitempty"`
+	EmailIsVerified bool       `json:"email_verified"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// FamilyMember is a child in a family tree with the adults who can see them
+type FamilyMember struct {
+	AdminUser
+	Guardians []repository.Guardian `json:"guardians,omitempty"` // empty for deleted children
+}
+
+// FamilyTree is a parent and every child they own
+type FamilyTree struct {
+	Parent   AdminUser      `json:"parent"`
+	Children []FamilyMember `json:"children"`
+}
+
+type AdminResetCredentialsRequest struct {
+	PIN string `json:"pin,omitempty"` // children only; adults are emailed a password reset link instead
+}
+
+type MergeAccountsRequest struct {
+	IntoUserID uint `json:"into_user_id"` // the account the family keeps
+}
+
+// ImpersonationResponse is a read-only access token for another user's account
+type ImpersonationResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ReadOnly  bool      `json:"read_only"`
+}
+
+// ---------------------------
+// Search the organization's accounts by name or email, ?q=...&role=... (admin)
+func (h *AuthHandler) AdminSearchUsers(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionSearchUsers, 0, c.Request.URL.RawQuery) {
+		return
+	}
+
+	users, err := repository.SearchUsers(h.DB, principal.OrganizationID, c.Query("q"), c.Query("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search users"})
+		return
+	}
+
+	results := make([]AdminUser, 0, len(users))
+	for i := range users {
+		results = append(results, adminUser(&users[i]))
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// ---------------------------
+// Show the family an account belongs to: the parent and all their children, deleted ones included (admin)
+func (h *AuthHandler) AdminGetFamily(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, true)
+	if !ok {
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionViewFamily, target.ID, "") {
+		return
+	}
+
+	root, err := repository.FamilyTree(h.DB, principal.OrganizationID, target.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch family"})
+		return
+	}
+
+	tree := FamilyTree{Parent: adminUser(root), Children: []FamilyMember{}}
+	for i := range root.Children {
+		member := FamilyMember{AdminUser: adminUser(&root.Children[i])}
+		if !root.Children[i].DeletedAt.Valid {
+			if member.Guardians, err = repository.ListGuardians(h.DB, root.Children[i].ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch family"})
+				return
+			}
+		}
+		tree.Children = append(tree.Children, member)
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// ---------------------------
+// Reset an account's credentials (admin). A child gets the PIN in the request and is signed out everywhere;
+// an adult is emailed a password reset link, since nobody else should know their password.
+func (h *AuthHandler) AdminResetCredentials(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, false)
+	if !ok {
+		return
+	}
+
+	var req AdminResetCredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if target.Role == policy.RoleChild && req.PIN == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "PIN is required"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionResetCredentials, target.ID, "") {
+		return
+	}
+
+	if target.Role != policy.RoleChild {
+		if err := h.sendPasswordResetEmail(c.Request.Context(), target); err != nil {
+			log.Printf("Failed to send password reset email to user %d: %v", target.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send password reset email"})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": "Password reset email sent"})
+		return
+	}
+
+	hashedPIN, err := bcrypt.GenerateFromPassword([]byte(req.PIN), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash PIN"})
+		return
+	}
+	if err := h.DB.Model(target).Update("pin", string(hashedPIN)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset PIN"})
+		return
+	}
+	if err := repository.ClearLoginThrottle(h.DB, repository.ChildThrottleKey(target.ID)); err != nil {
+		log.Printf("Failed to clear PIN failures for child %d: %v", target.ID, err)
+	}
+	if err := repository.RevokeUserAuthSessions(h.DB, target.ID, time.Now()); err != nil {
+		log.Printf("Failed to sign child %d out after a PIN reset: %v", target.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "PIN reset"})
+}
+
+// ---------------------------
+// Deactivate an account: it's signed out everywhere and can't sign in until reactivated (admin)
+func (h *AuthHandler) AdminDeactivateUser(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, false)
+	if !ok {
+		return
+	}
+	if target.ID == principal.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't deactivate your own account"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionDeactivate, target.ID, "") {
+		return
+	}
+
+	if err := repository.DeactivateUser(h.DB, target.ID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not deactivate account"})
+		return
+	}
+
+	h.respondWithAdminUser(c, target.ID)
+}
+
+// ---------------------------
+// Let a deactivated account sign in again (admin)
+func (h *AuthHandler) AdminReactivateUser(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, false)
+	if !ok {
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionReactivate, target.ID, "") {
+		return
+	}
+
+	if err := repository.ReactivateUser(h.DB, target.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reactivate account"})
+		return
+	}
+
+	h.respondWithAdminUser(c, target.ID)
+}
+
+// ---------------------------
+// Bring back a deleted child with their reading history (admin)
+func (h *AuthHandler) AdminRestoreChild(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, true)
+	if !ok {
+		return
+	}
+	if target.Role != policy.RoleChild || !target.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only a deleted child can be restored"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionRestoreChild, target.ID, "") {
+		return
+	}
+
+	if err := repository.RestoreChild(h.DB, principal.OrganizationID, target.ID); err != nil {
+		if errors.Is(err, repository.ErrChildNotDeleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only a deleted child can be restored"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not restore child"})
+		return
+	}
+
+	h.respondWithAdminUser(c, target.ID)
+}
+
+// ---------------------------
+// Merge a duplicate parent account into the one the family keeps, then deactivate the duplicate (admin)
+func (h *AuthHandler) AdminMergeUsers(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, false)
+	if !ok {
+		return
+	}
+
+	var req MergeAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.IntoUserID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "into_user_id is required"})
+		return
+	}
+	if orgID, err := repository.UserOrganizationID(h.DB, req.IntoUserID); err != nil || orgID != principal.OrganizationID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if target.Role != policy.RoleParent || target.ID == req.IntoUserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only one parent account can be merged into another"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionMergeAccounts, target.ID, fmt.Sprintf("into user %d", req.IntoUserID)) {
+		return
+	}
+
+	if err := repository.MergeParents(h.DB, target.ID, req.IntoUserID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrMergeNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only one parent account can be merged into another"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not merge accounts"})
+		return
+	}
+
+	h.respondWithAdminUser(c, req.IntoUserID)
+}
+
+// ---------------------------
+// Get a short-lived, read-only token for someone's account, to see what they see (admin).
+// It shows up in their signed-in devices, and any request that would change something is refused.
+func (h *AuthHandler) AdminImpersonateUser(c *gin.Context) {
+	principal, target, ok := h.loadAdminTarget(c, false)
+	if !ok {
+		return
+	}
+	if target.Role == policy.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin accounts can't be impersonated"})
+		return
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionImpersonate, target.ID, "") {
+		return
+	}
+
+	userAgent := fmt.Sprintf("Support (read-only), admin %d", principal.UserID)
+	session, _, err := repository.CreateAuthSession(h.DB, target.ID, userAgent, c.ClientIP(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	expiresAt := time.Now().Add(impersonationTokenTTL)
+	claims := accessTokenClaims(target, session.ID)
+	claims["impersonated_by"] = principal.UserID
+	claims["exp"] = expiresAt.Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(h.Secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ImpersonationResponse{Token: token, ExpiresAt: expiresAt.UTC(), ReadOnly: true})
+}
+
+// ---------------------------
+// The organization's admin audit log, newest first (admin)
+func (h *AuthHandler) GetAdminAuditLog(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entries, err := repository.AdminAuditLog(h.DB, principal.OrganizationID, adminAuditLogSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// loadAdminTarget fetches the account named by :id in the admin's organization, deleted ones too when unscoped is set.
+// It writes a 404 for anyone outside the organization.
+func (h *AuthHandler) loadAdminTarget(c *gin.Context, unscoped bool) (policy.Principal, *models.User, bool) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return principal, nil, false
+	}
+
+	userID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return principal, nil, false
+	}
+
+	query := h.DB
+	if unscoped {
+		query = query.Unscoped()
+	}
+	var user models.User
+	if err := query.Scopes(repository.InOrganization(principal.OrganizationID)).First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return principal, nil, false
+	}
+	return principal, &user, true
+}
+
+// recordAdminAction writes the audit entry for an admin request before it does anything.
+// Nothing happens without its entry, so a failure to record it is a 500.
+func (h *AuthHandler) recordAdminAction(c *gin.Context, principal policy.Principal, action string, targetUserID uint, detail string) bool {
+	if err := repository.RecordAdminAction(h.DB, principal.OrganizationID, principal.UserID, action, targetUserID, detail); err != nil {
+		log.Printf("Failed to audit %s by admin %d: %v", action, principal.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record admin action"})
+		return false
+	}
+	return true
+}
+
+// respondWithAdminUser writes the account as it now is
+func (h *AuthHandler) respondWithAdminUser(c *gin.Context, userID uint) {
+	var user models.User
+	if err := h.DB.Unscoped().First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch user"})
+		return
+	}
+	c.JSON(http.StatusOK, adminUser(&user))
+}
+
+func adminUser(user *models.User) AdminUser {
+	resp := AdminUser{
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		Role:            user.Role,
+		ParentID:        user.ParentID,
+		EmailIsVerified: user.EmailVerifiedAt != nil,
+		CreatedAt:       user.CreatedAt,
+		DeactivatedAt:   user.DeactivatedAt,
+	}
+	if user.DeletedAt.Valid {
+		resp.DeletedAt = &user.DeletedAt.Time
+	}
+	return resp
+}
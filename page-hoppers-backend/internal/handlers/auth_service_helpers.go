@@ -2,19 +2,30 @@ package handlers
 
 import (
 	"net/http"
-	"time"
+	"strconv"
+	"sync"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"page-hoppers-backend/internal/mail"
 	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
 )
 
 type AuthHandler struct {
 	DB     *gorm.DB
 	Secret []byte
+	Mailer mail.Mailer // sends verification and password reset emails; nil prints them to the log
+	AppURL string      // the frontend emailed links point to; DefaultAppURL if empty
+
+	// Deprecated: LegacyChildIDLogin lets children log in by database ID (childId) instead of from a paired
+	// family device, for clients not yet moved over. It's off unless LEGACY_CHILD_ID_LOGIN=true and will be removed.
+	LegacyChildIDLogin bool
+
+	background sync.WaitGroup // emails still being sent after their response went out
 }
 
 func NewAuthHandler(db *gorm.DB, secret []byte) *AuthHandler {
@@ -30,19 +41,31 @@ type ParentLoginRequest struct {
 	Password string `json:"password"`
 }
 
+// ChildLoginRequest names the child from a paired family device, by device_token and the child_key
+// from the family's login list. childId is only accepted while LegacyChildIDLogin is on.
 type ChildLoginRequest struct {
-	ChildID uint   `json:"childId"`
-	PIN     string `json:"pin"`
+	ChildID     uint   `json:"childId,omitempty"`
+	DeviceToken string `json:"device_token,omitempty"`
+	ChildKey    string `json:"child_key,omitempty"`
+	PIN         string `json:"pin"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"` // exchange at /api/auth/refresh for a new pair; each works once
 }
 
 type CreateChildRequest struct {
-	Name string `json:"name"`
-	Age  int    `json:"age"`
-	PIN  string `json:"pin"`
+	Name   string `json:"name"`
+	Age    int    `json:"age"`
+	PIN    string `json:"pin"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// GuardedChild is a child in a parent's list, with the parent's guardian role for them
+type GuardedChild struct {
+	models.User
+	GuardianRole string `json:"guardian_role"` // models.GuardianOwner, GuardianCoParent or GuardianViewer
 }
 
 type ChildResponse struct {
@@ -54,41 +77,78 @@ type ParentRegisterRequest struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	TimeZone string `json:"time_zone,omitempty"` // IANA name, e.g. "America/Chicago"; defaults to UTC
+
+	// Slug of the school or organization to join; defaults to the default organization
+	Organization string `json:"organization,omitempty"`
+	// The organization's join code for the role, from its admins; not needed for the default organization
+	JoinCode string `json:"join_code,omitempty"`
+}
+
+// ParentSettings are the family-wide settings a parent can change
+type ParentSettings struct {
+	TimeZone        string `json:"time_zone"`
+	StreakGraceDays int    `json:"streak_grace_days"`
+}
+
+type UpdateParentSettingsRequest struct {
+	TimeZone        *string `json:"time_zone,omitempty"`
+	StreakGraceDays *int    `json:"streak_grace_days,omitempty"`
+}
+
+// ChildSettings are the per-child settings a parent can change
+type ChildSettings struct {
+	RequiresLogApproval bool   `json:"requires_log_approval"`
+	Avatar              string `json:"avatar"`
+}
+
+type UpdateChildSettingsRequest struct {
+	RequiresLogApproval *bool   `json:"requires_log_approval,omitempty"`
+	Avatar              *string `json:"avatar,omitempty"`
 }
 
 // ---------------------------
 // Parent login
 func (h *AuthHandler) ParentLogin(c *gin.Context) {
+	h.passwordLogin(c, policy.RoleParent)
+}
+
+// ---------------------------
+// Teacher login
+func (h *AuthHandler) TeacherLogin(c *gin.Context) {
+	h.passwordLogin(c, policy.RoleTeacher)
+}
+
+// passwordLogin signs in an adult with role by email and password
+func (h *AuthHandler) passwordLogin(c *gin.Context, role string) {
 	var req ParentLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	var parent models.User
-	if err := h.DB.Where("email = ? AND role = ?", req.Email, "parent").First(&parent).Error; err != nil {
+	var user models.User
+	if err := h.DB.Where("email = ? AND role = ?", req.Email, role).First(&user).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(parent.Password), []byte(req.Password)); err != nil {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
+	if user.DeactivatedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": errDeactivated})
+		return
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": parent.ID,
-		"role":    "parent",
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
-
-	tokenString, err := token.SignedString(h.Secret)
+	resp, err := h.signIn(c, &user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, LoginResponse{Token: tokenString})
+	c.JSON(http.StatusOK, resp)
 }
 
 // ---------------------------
@@ -99,36 +159,54 @@ func (h *AuthHandler) ChildLogin(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
+	if req.DeviceToken == "" && req.ChildKey == "" && !h.LegacyChildIDLogin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Log in from a paired family device"})
+		return
+	}
 
-	var child models.User
-	if err := h.DB.Where("id = ? AND role = ?", req.ChildID, "child").First(&child).Error; err != nil {
+	child, err := h.loginChild(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"})
+		return
+	}
+
+	// Wrong PINs slow down, then lock, both this child and this address. The attempt is counted before
+	// the PIN is checked and handed back if it was right.
+	lockedNow, ok := h.reservePINAttempt(c, child)
+	if !ok {
+		return
+	}
+
+	// An unknown child and a wrong PIN look the same, so IDs can't be discovered by trying them
+	if child == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(child.PIN), []byte(req.PIN)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid PIN"})
+		if lockedNow {
+			h.notifyChildLockedOut(child)
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+	h.releasePINAttempt(c, child)
+	if child.DeactivatedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": errDeactivated})
 		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":   child.ID,
-		"parent_id": child.ParentID,
-		"role":      "child",
-		"exp":       time.Now().Add(12 * time.Hour).Unix(),
-	})
-
-	tokenString, err := token.SignedString(h.Secret)
+	resp, err := h.signIn(c, child)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, LoginResponse{Token: tokenString})
+	c.JSON(http.StatusOK, resp)
 }
 
 // ---------------------------
-// Get children for a parent
+// Get children for a parent: their own and any they were invited to guard
 func (h *AuthHandler) GetChildren(c *gin.Context) {
 	parentIDValue, exists := c.Get("user_id")
 	if !exists {
@@ -138,12 +216,31 @@ func (h *AuthHandler) GetChildren(c *gin.Context) {
 	parentID := parentIDValue.(uint)
 
 	var children []models.User
-	if err := h.DB.Where("parent_id = ? AND role = ?", parentID, "child").Find(&children).Error; err != nil {
+	if err := h.DB.Scopes(repository.GuardedChildren(parentID)).Order("id ASC").Find(&children).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch children"})
 		return
 	}
 
-	c.JSON(http.StatusOK, children)
+	var invited []models.Guardianship
+	if err := h.DB.Where("guardian_id = ?", parentID).Find(&invited).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch children"})
+		return
+	}
+	roles := map[uint]string{}
+	for _, guardianship := range invited {
+		roles[guardianship.ChildID] = guardianship.Role
+	}
+
+	resp := make([]GuardedChild, 0, len(children))
+	for _, child := range children {
+		role := models.GuardianOwner
+		if child.ParentID == nil || *child.ParentID != parentID {
+			role = roles[child.ID]
+		}
+		resp = append(resp, GuardedChild{User: child, GuardianRole: role})
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // ---------------------------
@@ -173,12 +270,23 @@ func (h *AuthHandler) CreateChild(c *gin.Context) {
 		return
 	}
 
+	loginKey, err := repository.NewChildLoginKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create child"})
+		return
+	}
+
+	// Children join the organization of the parent who adds them
+	principal, _ := policy.FromContext(c)
 	child := models.User{
-		Name:     req.Name,
-		Age:      req.Age,
-		PIN:      string(hashedPIN),
-		Role:     "child",
-		ParentID: &parentID,
+		OrganizationID: principal.OrganizationID,
+		Name:           req.Name,
+		Age:            req.Age,
+		PIN:            string(hashedPIN),
+		Role:           "child",
+		ParentID:       &parentID,
+		Avatar:         req.Avatar,
+		LoginKey:       loginKey,
 	}
 
 	if err := h.DB.Create(&child).Error; err != nil {
@@ -192,40 +300,192 @@ func (h *AuthHandler) CreateChild(c *gin.Context) {
 // ---------------------------
 // Parent registration
 func (h *AuthHandler) ParentRegister(c *gin.Context) {
+	if _, ok := h.register(c, policy.RoleParent); ok {
+		c.JSON(http.StatusCreated, gin.H{"message": "Parent registered successfully"})
+	}
+}
+
+// ---------------------------
+// Teacher registration. Teachers only see the classrooms they create and the children parents add to them.
+func (h *AuthHandler) TeacherRegister(c *gin.Context) {
+	if _, ok := h.register(c, policy.RoleTeacher); ok {
+		c.JSON(http.StatusCreated, gin.H{"message": "Teacher registered successfully"})
+	}
+}
+
+// register creates an adult account with role and sends its verification email
+func (h *AuthHandler) register(c *gin.Context, role string) (*models.User, bool) {
 	var req ParentRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
-		return
+		return nil, false
 	}
 
 	if req.Name == "" || req.Email == "" || req.Password == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Name, email, and password are required"})
-		return
+		return nil, false
+	}
+
+	if _, err := repository.LoadTimeZone(req.TimeZone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown time zone"})
+		return nil, false
+	}
+
+	org, err := repository.FindOrganization(h.DB, req.Organization)
+	if err == repository.ErrUnknownOrganization {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown organization"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create " + role})
+		return nil, false
+	}
+	if err := repository.CheckOrganizationJoinCode(org, role, req.JoinCode); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Joining this organization needs a join code from its admins"})
+		return nil, false
 	}
 
 	var existing models.User
 	if err := h.DB.Where("email = ?", req.Email).First(&existing).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
-		return
+		return nil, false
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return nil, false
+	}
+
+	user := models.User{
+		OrganizationID: org.ID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       string(hashedPassword),
+		Role:           role,
+		TimeZone:       req.TimeZone,
+	}
+
+	if err := h.DB.Create(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create " + role})
+		return nil, false
+	}
+	h.sendVerificationEmail(c.Request.Context(), &user)
+	return &user, true
+}
+
+// ---------------------------
+// Get the family settings for the logged-in parent
+func (h *AuthHandler) GetParentSettings(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
 		return
 	}
 
-	parent := models.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
-		Role:     "parent",
+	c.JSON(http.StatusOK, parentSettings(parent))
+}
+
+// ---------------------------
+// Update the family settings. The time zone applies to the parent and all their children.
+func (h *AuthHandler) UpdateParentSettings(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateParentSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
 	}
 
-	if err := h.DB.Create(&parent).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create parent"})
+	if req.TimeZone != nil {
+		if _, err := repository.LoadTimeZone(*req.TimeZone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown time zone"})
+			return
+		}
+		parent.TimeZone = *req.TimeZone
+	}
+	if req.StreakGraceDays != nil {
+		if *req.StreakGraceDays < 0 || *req.StreakGraceDays > repository.MaxStreakGraceDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Streak grace days must be between 0 and " + strconv.Itoa(repository.MaxStreakGraceDays)})
+			return
+		}
+		parent.StreakGraceDays = *req.StreakGraceDays
+	}
+
+	if err := h.DB.Model(parent).Select("time_zone", "streak_grace_days").Updates(parent).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, parentSettings(parent))
+}
+
+// ---------------------------
+// Update one child's settings (parent). Turning approval off leaves logs already waiting for a decision.
+func (h *AuthHandler) UpdateChildSettings(c *gin.Context) {
+	_, ok := h.loadParent(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Parent registered successfully"})
-}
\ No newline at end of file
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return
+	}
+
+	if !childAccess(c, h.DB, childID, true) {
+		return
+	}
+	var child models.User
+	if err := h.DB.Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
+	}
+
+	var req UpdateChildSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if req.RequiresLogApproval != nil {
+		child.RequiresLogApproval = *req.RequiresLogApproval
+	}
+	if req.Avatar != nil {
+		child.Avatar = *req.Avatar
+	}
+
+	if err := h.DB.Model(&child).Select("requires_log_approval", "avatar").Updates(&child).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ChildSettings{RequiresLogApproval: child.RequiresLogApproval, Avatar: child.Avatar})
+}
+
+// loadParent fetches the authenticated parent, writing the error response if there isn't one
+func (h *AuthHandler) loadParent(c *gin.Context) (*models.User, bool) {
+	userID, role, ok := currentUser(c)
+	if !ok || role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can do this"})
+		return nil, false
+	}
+
+	var parent models.User
+	if err := h.DB.Where("id = ? AND role = ?", userID, "parent").First(&parent).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Parent not found"})
+		return nil, false
+	}
+	return &parent, true
+}
+
+func parentSettings(parent *models.User) ParentSettings {
+	timeZone := parent.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	return ParentSettings{TimeZone: timeZone, StreakGraceDays: parent.StreakGraceDays}
+}
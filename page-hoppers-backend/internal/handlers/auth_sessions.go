@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// How long an access token lasts. Devices stay signed in longer by refreshing.
+const (
+	parentAccessTokenTTL = 24 * time.Hour
+	childAccessTokenTTL  = 12 * time.Hour
+
+	// An admin's read-only look at someone else's account, which can't be refreshed
+	impersonationTokenTTL = time.Hour
+)
+
+// errDeactivated is the 403 message for signing in to an account an admin has deactivated
+const errDeactivated = "This account has been deactivated. Please contact your organization"
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SessionResponse is one signed-in device in the sessions list
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	Role       string    `json:"role"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // the session making this request
+}
+
+// ---------------------------
+// Exchange a refresh token for a new access and refresh token. The old refresh token stops working.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
+	session, refreshToken, err := repository.RotateRefreshToken(h.DB, req.RefreshToken, time.Now())
+	if err != nil {
+		switch err {
+		case repository.ErrInvalidRefreshToken, repository.ErrRefreshTokenReused:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not refresh session"})
+		}
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, session.UserID).Error; err != nil || user.DeactivatedAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	token, err := h.accessToken(&user, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, LoginResponse{Token: token, RefreshToken: refreshToken})
+}
+
+// ---------------------------
+// Sign out the device making the request
+func (h *AuthHandler) Logout(c *gin.Context) {
+	session, ok := h.loadSession(c, currentSessionID(c))
+	if !ok {
+		return
+	}
+
+	if err := repository.RevokeAuthSession(h.DB, session, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not sign out"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ---------------------------
+// List signed-in devices. Parents see their own and those of children they manage; children see their own.
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userIDs := []uint{userID}
+	if role == policy.RoleParent {
+		var childIDs []uint
+		if err := h.DB.Model(&models.User{}).Scopes(repository.GuardedChildren(userID, repository.ManagingGuardianRoles...)).
+			Pluck("users.id", &childIDs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch sessions"})
+			return
+		}
+		userIDs = append(userIDs, childIDs...)
+	}
+
+	sessions, err := repository.ListActiveAuthSessions(h.DB, userIDs, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch sessions"})
+		return
+	}
+
+	currentID := currentSessionID(c)
+	responses := []SessionResponse{}
+	for _, session := range sessions {
+		resp := SessionResponse{
+			ID:         session.ID,
+			UserID:     session.UserID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		}
+		if session.User != nil {
+			resp.UserName = session.User.Name
+			resp.Role = session.User.Role
+		}
+		responses = append(responses, resp)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// ---------------------------
+// Sign out one device (a parent can sign out their children's too)
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	sessionID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, ok := h.loadSession(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := repository.RevokeAuthSession(h.DB, session, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke session"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// signIn starts a session for a user who has just proved who they are and returns their tokens
+func (h *AuthHandler) signIn(c *gin.Context, user *models.User) (LoginResponse, error) {
+	session, refreshToken, err := repository.CreateAuthSession(h.DB, user.ID, c.Request.UserAgent(), c.ClientIP(), time.Now())
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	token, err := h.accessToken(user, session.ID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	return LoginResponse{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// accessToken signs a JWT for the user tied to their session ("sid"), so revoking the session revokes the token.
+// "org_id" is the user's organization, which every request is confined to.
+func (h *AuthHandler) accessToken(user *models.User, sessionID uint) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims(user, sessionID)).SignedString(h.Secret)
+}
+
+// accessTokenClaims are the claims of the user's access token for the session
+func accessTokenClaims(user *models.User, sessionID uint) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"role":    user.Role,
+		"org_id":  user.OrganizationID,
+		"sid":     sessionID,
+		"exp":     time.Now().Add(parentAccessTokenTTL).Unix(),
+	}
+	if user.Role == policy.RoleChild {
+		claims["parent_id"] = user.ParentID
+		claims["exp"] = time.Now().Add(childAccessTokenTTL).Unix()
+	}
+	return claims
+}
+
+// loadSession fetches an active session the caller may sign out: their own, or that of a child they manage
+func (h *AuthHandler) loadSession(c *gin.Context, sessionID uint) (*models.AuthSession, bool) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	var session models.AuthSession
+	if err := h.DB.Preload("User").Where("id = ? AND revoked_at IS NULL", sessionID).First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return nil, false
+	}
+
+	owned := session.UserID == userID
+	if !owned && role == policy.RoleParent && session.User != nil && session.User.Role == policy.RoleChild {
+		owned = canManageChild(c, h.DB, session.UserID)
+	}
+	if !owned {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return nil, false
+	}
+	return &session, true
+}
+
+// currentSessionID returns the session the auth middleware found in the access token, or 0
+func currentSessionID(c *gin.Context) uint {
+	value, _ := c.Get("session_id")
+	sessionID, _ := value.(uint)
+	return sessionID
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/books"
+)
+
+type BookHandler struct {
+	DB     *gorm.DB
+	Search *books.SearchService
+}
+
+func NewBookHandler(db *gorm.DB, search *books.SearchService) *BookHandler {
+	return &BookHandler{
+		DB:     db,
+		Search: search,
+	}
+}
+
+// ---------------------------
+// Search books through the backend cache (GET /api/books/search?q=&limit=)
+func (h *BookHandler) SearchBooks(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	resp, err := h.Search.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Book search is unavailable right now"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
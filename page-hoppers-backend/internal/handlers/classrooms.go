@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+type CreateClassroomRequest struct {
+	Name string `json:"name"`
+}
+
+type JoinClassroomRequest struct {
+	JoinCode string `json:"join_code"`
+}
+
+// ClassroomStudent is a child in a teacher's class list
+type ClassroomStudent struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// StudentSummary is one child's reading in a class summary
+type StudentSummary struct {
+	ChildID             uint                      `json:"child_id"`
+	Name                string                    `json:"name"`
+	CurrentBook         gin.H                     `json:"currentBook"`
+	TotalCompletedBooks int                       `json:"totalCompletedBooks"`
+	CurrentStreak       int                       `json:"current_streak"`
+	Range               SummaryRange              `json:"range"`
+	Periods             []repository.PeriodTotals `json:"periods"`
+}
+
+// ClassroomTotal is what the whole class read over the summary's range
+type ClassroomTotal struct {
+	Students       int                       `json:"students"`
+	BooksCompleted int                       `json:"books_completed"`
+	PagesRead      int                       `json:"pages_read"`
+	MinutesRead    int                       `json:"minutes_read"`
+	Periods        []repository.PeriodTotals `json:"periods"`
+}
+
+// ---------------------------
+// List the teacher's classrooms with their join codes
+func (h *ReadingLogHandler) GetClassrooms(c *gin.Context) {
+	teacherID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	classrooms, err := repository.TeacherClassrooms(h.DB, teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch classrooms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, classrooms)
+}
+
+// ---------------------------
+// Start a classroom (teacher). The response has the join code to give parents.
+func (h *ReadingLogHandler) CreateClassroom(c *gin.Context) {
+	teacherID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateClassroomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+
+	classroom, err := repository.CreateClassroom(h.DB, teacherID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create classroom"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, classroom)
+}
+
+// ---------------------------
+// Replace a classroom's join code, e.g. after it was shared too widely (teacher)
+func (h *ReadingLogHandler) RotateClassroomJoinCode(c *gin.Context) {
+	classroom, ok := h.loadClassroom(c)
+	if !ok {
+		return
+	}
+
+	if err := repository.RotateJoinCode(h.DB, classroom); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change join code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, classroom)
+}
+
+// ---------------------------
+// List the children in a classroom (teacher)
+func (h *ReadingLogHandler) GetClassroomStudents(c *gin.Context) {
+	classroom, ok := h.loadClassroom(c)
+	if !ok {
+		return
+	}
+
+	students, ok := h.classroomStudents(c, classroom.ID)
+	if !ok {
+		return
+	}
+
+	list := make([]ClassroomStudent, 0, len(students))
+	for _, student := range students {
+		list = append(list, ClassroomStudent{ID: student.ID, Name: student.Name, Avatar: student.Avatar})
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// ---------------------------
+// Class reading dashboard (teacher): each student's reading over a range, and the class total.
+// Takes the same from/to, period and group_by parameters as a child's summary, read in the teacher's time zone.
+func (h *ReadingLogHandler) GetClassroomSummary(c *gin.Context) {
+	classroom, ok := h.loadClassroom(c)
+	if !ok {
+		return
+	}
+
+	var teacher models.User
+	if err := h.DB.Select("id", "time_zone").First(&teacher, classroom.TeacherID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch classroom"})
+		return
+	}
+	loc, err := repository.LoadTimeZone(teacher.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	readingRange, periods, ok := h.parseSummaryRange(c, time.Now().In(loc))
+	if !ok {
+		return
+	}
+
+	students, ok := h.classroomStudents(c, classroom.ID)
+	if !ok {
+		return
+	}
+
+	total := ClassroomTotal{Students: len(students), Periods: make([]repository.PeriodTotals, len(periods))}
+	for i, period := range periods {
+		total.Periods[i].Period = period
+	}
+	summaries := make([]StudentSummary, 0, len(students))
+	for _, student := range students {
+		summary, ok := h.studentSummary(c, student, *readingRange, periods)
+		if !ok {
+			return
+		}
+		summaries = append(summaries, *summary)
+
+		total.BooksCompleted += summary.Range.BooksCompleted
+		total.PagesRead += summary.Range.PagesRead
+		total.MinutesRead += summary.Range.MinutesRead
+		for i, totals := range summary.Periods {
+			total.Periods[i].BooksCompleted += totals.BooksCompleted
+			total.Periods[i].PagesRead += totals.PagesRead
+			total.Periods[i].MinutesRead += totals.MinutesRead
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"classroom": classroom,
+		"range":     readingRange,
+		"students":  summaries,
+		"total":     total,
+	})
+}
+
+// ---------------------------
+// List the classrooms a child is in (any of the child's guardians)
+func (h *ReadingLogHandler) GetChildClassrooms(c *gin.Context) {
+	childID, _, ok := h.classroomChild(c, false)
+	if !ok {
+		return
+	}
+
+	classrooms, err := repository.ChildClassrooms(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch classrooms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, classrooms)
+}
+
+// ---------------------------
+// Add a child to a classroom by its join code. This is the guardian's consent for the teacher
+// to see the child's reading, so only the owner or a co-parent can give it.
+func (h *ReadingLogHandler) JoinClassroom(c *gin.Context) {
+	childID, parentID, ok := h.classroomChild(c, true)
+	if !ok {
+		return
+	}
+
+	var req JoinClassroomRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.JoinCode) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Join code is required"})
+		return
+	}
+
+	classroom, err := repository.JoinClassroom(h.DB, req.JoinCode, childID, parentID, time.Now())
+	if err == repository.ErrInvalidJoinCode {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No classroom has that join code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join classroom"})
+		return
+	}
+
+	var teacher models.User
+	h.DB.Select("id", "name").First(&teacher, classroom.TeacherID)
+	c.JSON(http.StatusCreated, gin.H{"id": classroom.ID, "name": classroom.Name, "teacher_name": teacher.Name})
+}
+
+// ---------------------------
+// Take a child out of a classroom, withdrawing consent; the teacher stops seeing them at once
+func (h *ReadingLogHandler) LeaveClassroom(c *gin.Context) {
+	childID, _, ok := h.classroomChild(c, true)
+	if !ok {
+		return
+	}
+
+	classroomID, ok := parseUintParam(c, "classroomId")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid classroom ID"})
+		return
+	}
+
+	left, err := repository.LeaveClassroom(h.DB, classroomID, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave classroom"})
+		return
+	}
+	if !left {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Classroom not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// loadClassroom fetches the classroom named by :id if the caller teaches it; other classrooms are 404
+func (h *ReadingLogHandler) loadClassroom(c *gin.Context) (*models.Classroom, bool) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	classroomID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid classroom ID"})
+		return nil, false
+	}
+
+	var classroom models.Classroom
+	if !policy.CanTeachClassroom(h.DB, principal, classroomID) || h.DB.First(&classroom, classroomID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Classroom not found"})
+		return nil, false
+	}
+	return &classroom, true
+}
+
+// classroomChild reads :id and checks the calling parent may see the child's classrooms, or change them
+// when write is set. It returns the child's and the parent's IDs.
+func (h *ReadingLogHandler) classroomChild(c *gin.Context, write bool) (uint, uint, bool) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, 0, false
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return 0, 0, false
+	}
+
+	if !childAccess(c, h.DB, childID, write) {
+		return 0, 0, false
+	}
+	return childID, userID, true
+}
+
+// classroomStudents fetches the classroom's children, writing a 500 response on failure
+func (h *ReadingLogHandler) classroomStudents(c *gin.Context, classroomID uint) ([]models.User, bool) {
+	students, err := repository.ClassroomStudents(h.DB, classroomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch students"})
+		return nil, false
+	}
+	return students, true
+}
+
+// studentSummary sums up one student's reading for a class summary. Totals use the class's periods;
+// the current book and streak are read in the child's own family time zone.
+func (h *ReadingLogHandler) studentSummary(c *gin.Context, student models.User, readingRange SummaryRange, periods []repository.Period) (*StudentSummary, bool) {
+	loc, ok := familyLocation(c, h.DB, student.ID)
+	if !ok {
+		return nil, false
+	}
+	now := time.Now().In(loc)
+
+	summary, err := repository.GetReadingSummary(h.DB, student.ID, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+		return nil, false
+	}
+
+	streaks, ok := h.childStreaks(c, student.ID, now)
+	if !ok {
+		return nil, false
+	}
+
+	periodTotals, err := repository.GetPeriodTotals(h.DB, student.ID, periods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+		return nil, false
+	}
+	for _, totals := range periodTotals {
+		readingRange.BooksCompleted += totals.BooksCompleted
+		readingRange.PagesRead += totals.PagesRead
+		readingRange.MinutesRead += totals.MinutesRead
+	}
+
+	return &StudentSummary{
+		ChildID:             student.ID,
+		Name:                student.Name,
+		CurrentBook:         summaryBookData(summary.CurrentBook),
+		TotalCompletedBooks: summary.TotalCompletedBooks,
+		CurrentStreak:       streaks.CurrentStreak,
+		Range:               readingRange,
+		Periods:             periodTotals,
+	}, true
+}
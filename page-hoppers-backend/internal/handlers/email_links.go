@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"page-hoppers-backend/internal/mail"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// DefaultAppURL is where emailed links point when AuthHandler.AppURL isn't set: the local frontend
+const DefaultAppURL = "http://localhost:3000"
+
+// EmailRequest asks for a verification or password reset email
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// emailTokenClaims is the signed token in an emailed link. Its ID names the EmailToken row that makes it single-use.
+type emailTokenClaims struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// emailAccountRoles are the accounts that sign in with an email address and password
+var emailAccountRoles = []string{policy.RoleParent, policy.RoleTeacher, policy.RoleAdmin}
+
+// ---------------------------
+// Send a new verification email. Always 202, so the response doesn't reveal who has an account.
+func (h *AuthHandler) RequestEmailVerification(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
+
+	// Looked up and sent after answering, as for a password reset
+	h.inBackground(func(ctx context.Context) {
+		var parent models.User
+		if err := h.DB.Where("email = ? AND role IN ?", req.Email, emailAccountRoles).First(&parent).Error; err == nil && parent.EmailVerifiedAt == nil {
+			h.sendVerificationEmail(ctx, &parent)
+		}
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If that address needs verifying, we've sent an email"})
+}
+
+// ---------------------------
+// Confirm an email address from the link in a verification email
+func (h *AuthHandler) ConfirmEmailVerification(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+
+	user, token, ok := h.consumeEmailToken(c, req.Token, models.EmailTokenVerifyEmail)
+	if !ok {
+		return
+	}
+	// A link sent before the address changed doesn't verify the new one
+	if token.Email != user.Email {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+		return
+	}
+
+	if user.EmailVerifiedAt == nil {
+		if err := h.DB.Model(user).Update("email_verified_at", time.Now().UTC()).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify email"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ---------------------------
+// Send a password reset email. Always 202, so the response doesn't reveal who has an account.
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
+
+	// Looked up and sent after answering, so how long the answer takes doesn't tell who has an account
+	h.inBackground(func(ctx context.Context) {
+		var parent models.User
+		if err := h.DB.Where("email = ? AND role IN ?", req.Email, emailAccountRoles).First(&parent).Error; err != nil {
+			return
+		}
+		if err := h.sendPasswordResetEmail(ctx, &parent); err != nil {
+			log.Printf("Failed to send password reset email to user %d: %v", parent.ID, err)
+		}
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If that address has an account, we've sent an email"})
+}
+
+// ---------------------------
+// Set a new password from the link in a password reset email. Every device is signed out.
+func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" || req.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token and password are required"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	user, token, ok := h.consumeEmailToken(c, req.Token, models.EmailTokenResetPassword)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{"password": string(hashedPassword)}
+	// Following the link proved they get mail at this address
+	if user.EmailVerifiedAt == nil && token.Email == user.Email {
+		updates["email_verified_at"] = now
+	}
+	if err := h.DB.Model(user).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset password"})
+		return
+	}
+	if err := repository.RevokeUserAuthSessions(h.DB, user.ID, now); err != nil {
+		log.Printf("Failed to sign user %d out after a password reset: %v", user.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset. Please log in with your new password"})
+}
+
+// sendVerificationEmail emails a parent a link to verify their address, logging any failure
+func (h *AuthHandler) sendVerificationEmail(ctx context.Context, parent *models.User) {
+	link, err := h.emailLink(parent, models.EmailTokenVerifyEmail, models.VerifyEmailTokenTTL, "/verify-email")
+	if err == nil {
+		err = h.mailer().Send(ctx, mail.Message{
+			To:      parent.Email,
+			Subject: "Verify your Page Hoppers email",
+			Body: fmt.Sprintf("Hi %s,\n\nPlease confirm this is your email address by opening this link within two days:\n\n%s\n\nIf you didn't sign up for Page Hoppers, ignore this email.\n",
+				parent.Name, link),
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", parent.ID, err)
+	}
+}
+
+// sendPasswordResetEmail emails the user a link to choose a new password
+func (h *AuthHandler) sendPasswordResetEmail(ctx context.Context, user *models.User) error {
+	link, err := h.emailLink(user, models.EmailTokenResetPassword, models.ResetPasswordTokenTTL, "/reset-password")
+	if err != nil {
+		return err
+	}
+	return h.mailer().Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Page Hoppers password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset your Page Hoppers password. To choose a new one, open this link within an hour:\n\n%s\n\nIf it wasn't you, ignore this email and your password stays the same.\n",
+			user.Name, link),
+	})
+}
+
+// emailLink issues a one-time token and returns the frontend link, at path, that carries it
+func (h *AuthHandler) emailLink(user *models.User, purpose string, ttl time.Duration, path string) (string, error) {
+	now := time.Now()
+	record, tokenID, err := repository.IssueEmailToken(h.DB, user.ID, purpose, user.Email, ttl, now)
+	if err != nil {
+		return "", err
+	}
+
+	claims := emailTokenClaims{
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(record.ExpiresAt),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(h.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	return h.appLink(path, signed), nil
+}
+
+// consumeEmailToken checks an emailed token's signature and purpose, uses it up, and returns its user.
+// It writes a 400 for any link that isn't valid any more.
+func (h *AuthHandler) consumeEmailToken(c *gin.Context, signed, purpose string) (*models.User, *models.EmailToken, bool) {
+	var claims emailTokenClaims
+	_, err := jwt.ParseWithClaims(signed, &claims, func(t *jwt.Token) (interface{}, error) {
+		return h.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims.Purpose != purpose || claims.ID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+		return nil, nil, false
+	}
+
+	token, err := repository.ConsumeEmailToken(h.DB, claims.ID, purpose, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidEmailToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+			return nil, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check link"})
+		return nil, nil, false
+	}
+	if strconv.FormatUint(uint64(token.UserID), 10) != claims.Subject {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+		return nil, nil, false
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, token.UserID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired link"})
+		return nil, nil, false
+	}
+	return &user, token, true
+}
+
+// appLink returns a frontend link at path carrying token
+func (h *AuthHandler) appLink(path, token string) string {
+	appURL := h.AppURL
+	if appURL == "" {
+		appURL = DefaultAppURL
+	}
+	return strings.TrimRight(appURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// inBackground runs work after the response has gone out
+func (h *AuthHandler) inBackground(work func(ctx context.Context)) {
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+		work(context.Background())
+	}()
+}
+
+// Wait blocks until the emails being sent in the background are done
+func (h *AuthHandler) Wait() {
+	h.background.Wait()
+}
+
+// mailer returns the configured mailer, printing to the log if there isn't one
+func (h *AuthHandler) mailer() mail.Mailer {
+	if h.Mailer == nil {
+		return mail.NewLogMailer()
+	}
+	return h.Mailer
+}
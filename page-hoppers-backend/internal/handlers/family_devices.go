@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// PairDeviceRequest names a device being paired, e.g. {"name": "Kitchen tablet"}
+type PairDeviceRequest struct {
+	Name string `json:"name"`
+}
+
+// PairDeviceResponse is returned once, when a device is paired. The device keeps device_token;
+// it can't be fetched again.
+type PairDeviceResponse struct {
+	Device      models.FamilyDevice `json:"device"`
+	DeviceToken string              `json:"device_token"`
+}
+
+// FamilyChildrenRequest is sent by a paired device to list who can log in on it
+type FamilyChildrenRequest struct {
+	DeviceToken string `json:"device_token"`
+}
+
+// FamilyLoginChild is one child on a family login screen. Key goes back as child_key with the PIN.
+type FamilyLoginChild struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// ---------------------------
+// Pair a shared device for child login (parent)
+func (h *AuthHandler) PairDevice(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	var req PairDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+
+	device, token, err := repository.CreateFamilyDevice(h.DB, parent.ID, req.Name, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not pair device"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, PairDeviceResponse{Device: *device, DeviceToken: token})
+}
+
+// ---------------------------
+// List the family's paired devices (parent)
+func (h *AuthHandler) GetFamilyDevices(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	devices, err := repository.ListFamilyDevices(h.DB, parent.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch devices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// ---------------------------
+// Unpair a device; it can no longer list the children or log them in (parent)
+func (h *AuthHandler) UnpairDevice(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	deviceID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	found, err := repository.RevokeFamilyDevice(h.DB, parent.ID, deviceID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unpair device"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ---------------------------
+// List the children who can log in on a paired device: names and avatars, no database IDs
+func (h *AuthHandler) GetFamilyLoginChildren(c *gin.Context) {
+	var req FamilyChildrenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	device, err := repository.FindFamilyDevice(h.DB, req.DeviceToken, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidDeviceToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid device token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch children"})
+		return
+	}
+
+	children, err := repository.FamilyLoginChildren(h.DB, device.ParentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch children"})
+		return
+	}
+
+	resp := make([]FamilyLoginChild, 0, len(children))
+	for _, child := range children {
+		resp = append(resp, FamilyLoginChild{Key: child.LoginKey, Name: child.Name, Avatar: child.Avatar})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// loginChild finds the child a login request names, or nil if there is no such child
+// (including when the device token is wrong)
+func (h *AuthHandler) loginChild(req ChildLoginRequest) (*models.User, error) {
+	var child *models.User
+	var err error
+	if req.DeviceToken != "" || req.ChildKey != "" {
+		var device *models.FamilyDevice
+		if device, err = repository.FindFamilyDevice(h.DB, req.DeviceToken, time.Now()); err == nil {
+			child, err = repository.FindChildByLoginKey(h.DB, device.ParentID, req.ChildKey)
+		}
+	} else {
+		// Only with LegacyChildIDLogin on; ChildLogin turns these away otherwise
+		child = &models.User{}
+		err = h.DB.Where("id = ? AND role = ?", req.ChildID, "child").First(child).Error
+	}
+
+	if errors.Is(err, repository.ErrInvalidDeviceToken) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return child, nil
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// GoalRequest sets a goal, e.g. {"metric": "books", "period": "month", "target": 4}.
+// Every field is required to create a goal; updates change only the fields sent.
+type GoalRequest struct {
+	Metric *string `json:"metric,omitempty"` // "books", "minutes" or "pages"
+	Period *string `json:"period,omitempty"` // "week", "month" or "term"
+	Target *int    `json:"target,omitempty"`
+}
+
+// apply copies the request onto goal and returns an error message if the result is invalid
+func (req GoalRequest) apply(goal *models.Goal) string {
+	if req.Metric != nil {
+		goal.Metric = *req.Metric
+	}
+	if req.Period != nil {
+		goal.Period = *req.Period
+	}
+	if req.Target != nil {
+		goal.Target = *req.Target
+	}
+
+	if !models.IsValidGoalMetric(goal.Metric) {
+		return "Metric must be 'books', 'minutes' or 'pages'"
+	}
+	if !models.IsValidGoalPeriod(goal.Period) {
+		return "Period must be 'week', 'month' or 'term'"
+	}
+	if goal.Target <= 0 {
+		return "Target must be positive"
+	}
+	return ""
+}
+
+// ---------------------------
+// List a child's goals with progress for the current period (the child or their parent)
+func (h *ReadingLogHandler) GetGoals(c *gin.Context) {
+	childID, ok := h.goalChild(c, false)
+	if !ok {
+		return
+	}
+
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
+	}
+
+	progress, ok := h.goalProgress(c, childID, time.Now().In(loc))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// ---------------------------
+// Set a new goal for a child (parent)
+func (h *ReadingLogHandler) CreateGoal(c *gin.Context) {
+	childID, ok := h.goalChild(c, true)
+	if !ok {
+		return
+	}
+	parentID, _, _ := currentUser(c)
+
+	var req GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.Metric == nil || req.Period == nil || req.Target == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Metric, period, and target are required"})
+		return
+	}
+
+	goal := models.Goal{ChildID: childID, SetByID: parentID}
+	if msg := req.apply(&goal); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := h.DB.Create(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// ---------------------------
+// Change a goal's metric, period or target (parent)
+func (h *ReadingLogHandler) UpdateGoal(c *gin.Context) {
+	goal, ok := h.loadGoal(c)
+	if !ok {
+		return
+	}
+
+	var req GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if msg := req.apply(goal); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := h.DB.Save(goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// ---------------------------
+// Remove a goal (parent)
+func (h *ReadingLogHandler) DeleteGoal(c *gin.Context) {
+	goal, ok := h.loadGoal(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.Delete(goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete goal"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// goalChild reads :id and checks the caller may see the child's goals, or change them when write is set.
+// Only parents change goals; children can see their own.
+func (h *ReadingLogHandler) goalChild(c *gin.Context, write bool) (uint, bool) {
+	_, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return 0, false
+	}
+
+	if !childAccess(c, h.DB, childID, write) {
+		return 0, false
+	}
+	if write && role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can set goals"})
+		return 0, false
+	}
+	return childID, true
+}
+
+// loadGoal fetches the goal named by :goalId for a child the calling parent manages
+func (h *ReadingLogHandler) loadGoal(c *gin.Context) (*models.Goal, bool) {
+	childID, ok := h.goalChild(c, true)
+	if !ok {
+		return nil, false
+	}
+
+	goalID, ok := parseUintParam(c, "goalId")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return nil, false
+	}
+
+	var goal models.Goal
+	if err := h.DB.Where("id = ? AND child_id = ?", goalID, childID).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return nil, false
+	}
+	return &goal, true
+}
+
+// goalProgress measures the child's goals as of now, writing a 500 response on failure
+func (h *ReadingLogHandler) goalProgress(c *gin.Context, childID uint, now time.Time) ([]repository.GoalProgress, bool) {
+	goals, err := repository.GetChildGoals(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return nil, false
+	}
+
+	progress, err := repository.GetGoalProgress(h.DB, goals, now, h.schoolCalendar())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute goal progress"})
+		return nil, false
+	}
+	return progress, true
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/mail"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// GuardiansResponse lists who can see a child. Pending invites are shown to the owner only.
+type GuardiansResponse struct {
+	Guardians []repository.Guardian   `json:"guardians"`
+	Invites   []models.GuardianInvite `json:"invites,omitempty"`
+}
+
+// InviteGuardianRequest invites another adult, e.g. {"email": "grandma@example.com", "role": "viewer"}
+type InviteGuardianRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"` // "co_parent" or "viewer"
+}
+
+type UpdateGuardianRequest struct {
+	Role string `json:"role"`
+}
+
+type AcceptGuardianInviteRequest struct {
+	Token string `json:"token"`
+}
+
+// ---------------------------
+// List the adults who can see a child (any guardian)
+func (h *AuthHandler) GetGuardians(c *gin.Context) {
+	_, childID, role, ok := h.guardianChild(c)
+	if !ok {
+		return
+	}
+
+	guardians, err := repository.ListGuardians(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch guardians"})
+		return
+	}
+	resp := GuardiansResponse{Guardians: guardians}
+
+	if role == models.GuardianOwner {
+		if resp.Invites, err = repository.ListPendingGuardianInvites(h.DB, childID, time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch invites"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ---------------------------
+// Invite another adult by email to co-parent or view a child (owner)
+func (h *AuthHandler) InviteGuardian(c *gin.Context) {
+	parent, childID, ok := h.ownedChild(c)
+	if !ok {
+		return
+	}
+
+	var req InviteGuardianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" || !strings.Contains(req.Email, "@") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A valid email is required"})
+		return
+	}
+	if !models.IsInvitableGuardianRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be 'co_parent' or 'viewer'"})
+		return
+	}
+
+	guardians, err := repository.ListGuardians(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not invite guardian"})
+		return
+	}
+	for _, guardian := range guardians {
+		if strings.EqualFold(guardian.Email, req.Email) {
+			c.JSON(http.StatusConflict, gin.H{"error": "They can already see this child; change their role instead"})
+			return
+		}
+	}
+
+	var child models.User
+	if err := h.DB.First(&child, childID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
+	}
+
+	invite, token, err := repository.CreateGuardianInvite(h.DB, childID, parent.ID, req.Email, req.Role, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not invite guardian"})
+		return
+	}
+
+	access := "help manage"
+	if req.Role == models.GuardianViewer {
+		access = "follow"
+	}
+	if err := h.mailer().Send(c.Request.Context(), mail.Message{
+		To:      invite.Email,
+		Subject: fmt.Sprintf("%s invited you to Page Hoppers", parent.Name),
+		Body: fmt.Sprintf("Hi,\n\n%s has invited you to %s %s's reading on Page Hoppers. Log in or sign up with this email address, then open this link within a week:\n\n%s\n",
+			parent.Name, access, child.Name, h.appLink("/accept-invite", token)),
+	}); err != nil {
+		log.Printf("Failed to send guardian invite %d: %v", invite.ID, err)
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// ---------------------------
+// Accept a guardian invite with the token from its email. The caller's email must be the invited one.
+func (h *AuthHandler) AcceptGuardianInvite(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	var req AcceptGuardianInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+
+	invite, err := repository.AcceptGuardianInvite(h.DB, req.Token, parent, time.Now())
+	switch {
+	case errors.Is(err, repository.ErrInvalidGuardianInvite):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invite"})
+		return
+	case errors.Is(err, repository.ErrGuardianInviteEmail):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invite was sent to a different email address"})
+		return
+	case errors.Is(err, repository.ErrGuardianInviteOrganization):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invite is for a child in another organization"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not accept invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invite)
+}
+
+// ---------------------------
+// Cancel an invite that hasn't been accepted (owner)
+func (h *AuthHandler) RevokeGuardianInvite(c *gin.Context) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	inviteID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite ID"})
+		return
+	}
+
+	var invite models.GuardianInvite
+	if err := h.DB.First(&invite, inviteID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+		return
+	}
+	if !policy.CanManageGuardians(h.DB, policy.Principal{UserID: parent.ID, Role: parent.Role, OrganizationID: parent.OrganizationID}, invite.ChildID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+		return
+	}
+
+	revoked, err := repository.RevokeGuardianInvite(h.DB, &invite, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not cancel invite"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusConflict, gin.H{"error": "This invite has already been used or cancelled"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ---------------------------
+// Change an invited guardian's role (owner)
+func (h *AuthHandler) UpdateGuardian(c *gin.Context) {
+	_, childID, ok := h.ownedChild(c)
+	if !ok {
+		return
+	}
+
+	guardianID, ok := parseUintParam(c, "userId")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+
+	var req UpdateGuardianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if !models.IsInvitableGuardianRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be 'co_parent' or 'viewer'"})
+		return
+	}
+
+	found, err := repository.SetGuardianRole(h.DB, childID, guardianID, req.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update guardian"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Guardian not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": guardianID, "role": req.Role})
+}
+
+// ---------------------------
+// Take away a guardian's access (owner), or give up your own (any invited guardian)
+func (h *AuthHandler) RemoveGuardian(c *gin.Context) {
+	parent, childID, role, ok := h.guardianChild(c)
+	if !ok {
+		return
+	}
+
+	guardianID, ok := parseUintParam(c, "userId")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	if role != models.GuardianOwner && guardianID != parent.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the child's owner can remove other guardians"})
+		return
+	}
+
+	removed, err := repository.RemoveGuardian(h.DB, childID, guardianID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove guardian"})
+		return
+	}
+	if !removed {
+		// The owner isn't a Guardianship row, so lands here too
+		c.JSON(http.StatusNotFound, gin.H{"error": "Guardian not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// guardianChild reads :id and returns the calling parent with their guardian role for the child.
+// Children get a 403 and adults who aren't the child's guardian a 404.
+func (h *AuthHandler) guardianChild(c *gin.Context) (*models.User, uint, string, bool) {
+	parent, ok := h.loadParent(c)
+	if !ok {
+		return nil, 0, "", false
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return nil, 0, "", false
+	}
+
+	if !childAccess(c, h.DB, childID, false) {
+		return nil, 0, "", false
+	}
+	role, err := repository.GuardianRole(h.DB, parent.ID, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check access"})
+		return nil, 0, "", false
+	}
+	return parent, childID, role, true
+}
+
+// ownedChild is guardianChild for requests only the child's owner may make
+func (h *AuthHandler) ownedChild(c *gin.Context) (*models.User, uint, bool) {
+	parent, childID, _, ok := h.guardianChild(c)
+	if !ok {
+		return nil, 0, false
+	}
+	if !policy.CanManageGuardians(h.DB, policy.Principal{UserID: parent.ID, Role: parent.Role, OrganizationID: parent.OrganizationID}, childID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the child's owner can manage guardians"})
+		return nil, 0, false
+	}
+	return parent, childID, true
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// ---------------------------
+// Unlock a child's PIN login after a lockout (parent)
+func (h *AuthHandler) UnlockChild(c *gin.Context) {
+	_, ok := h.loadParent(c)
+	if !ok {
+		return
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return
+	}
+
+	if !childAccess(c, h.DB, childID, true) {
+		return
+	}
+
+	if err := repository.ClearLoginThrottle(h.DB, repository.ChildThrottleKey(childID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unlock child"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// reservePINAttempt counts a PIN attempt against the caller's address and, if known, the child before the PIN
+// is checked, so parallel guesses can't all get through on the same count. It writes a 429 with Retry-After and
+// returns false if either must wait first. lockedNow is true when this attempt locks the child out.
+func (h *AuthHandler) reservePINAttempt(c *gin.Context, child *models.User) (lockedNow bool, ok bool) {
+	type reservation struct {
+		key    string
+		policy repository.ThrottlePolicy
+	}
+	reservations := []reservation{{repository.IPThrottleKey(c.ClientIP()), repository.LoginIPPolicy}}
+	if child != nil {
+		reservations = append(reservations, reservation{repository.ChildThrottleKey(child.ID), repository.ChildPINPolicy})
+	}
+
+	now := time.Now()
+	for i, reservation := range reservations {
+		attempt, err := repository.ReserveLoginAttempt(h.DB, reservation.key, reservation.policy, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"})
+			return false, false
+		}
+		if attempt.Wait <= 0 {
+			lockedNow = attempt.LockedNow
+			continue
+		}
+
+		// Attempts already reserved for this request are handed back, since it never gets to try the PIN
+		for _, reserved := range reservations[:i] {
+			if err := repository.ReleaseLoginAttempt(h.DB, reserved.key); err != nil {
+				log.Printf("Failed to release PIN attempt for %s: %v", reserved.key, err)
+			}
+		}
+		seconds := int(math.Ceil(attempt.Wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		msg := fmt.Sprintf("Too many attempts. Try again in %d seconds", seconds)
+		if attempt.Locked {
+			msg = "Too many wrong PINs. Login is locked for now; a parent can unlock it"
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": msg, "retry_after": seconds, "locked": attempt.Locked})
+		return false, false
+	}
+	return lockedNow, true
+}
+
+// releasePINAttempt undoes reservePINAttempt after the right PIN: the address gets its attempt back and the
+// child's failures are forgotten
+func (h *AuthHandler) releasePINAttempt(c *gin.Context, child *models.User) {
+	if err := repository.ReleaseLoginAttempt(h.DB, repository.IPThrottleKey(c.ClientIP())); err != nil {
+		log.Printf("Failed to release PIN attempt for %s: %v", c.ClientIP(), err)
+	}
+	if err := repository.ClearLoginThrottle(h.DB, repository.ChildThrottleKey(child.ID)); err != nil {
+		log.Printf("Failed to clear PIN failures for child %d: %v", child.ID, err)
+	}
+}
+
+// notifyChildLockedOut tells the adults managing the child that wrong PINs locked their login
+func (h *AuthHandler) notifyChildLockedOut(child *models.User) {
+	guardianIDs, err := repository.ChildGuardianIDs(h.DB, child.ID, repository.ManagingGuardianRoles...)
+	if err != nil {
+		log.Printf("Failed to find who to notify of child %d's lockout: %v", child.ID, err)
+		return
+	}
+	msg := fmt.Sprintf("%s's login was locked after too many wrong PINs. It unlocks by itself in %d minutes, or you can unlock it now.",
+		child.Name, int(repository.ChildPINPolicy.LockoutFor.Minutes()))
+	for _, guardianID := range guardianIDs {
+		if _, err := repository.Notify(h.DB, guardianID, models.NotificationChildLockedOut, msg); err != nil {
+			log.Printf("Failed to notify user %d of child %d's lockout: %v", guardianID, child.ID, err)
+		}
+	}
+}
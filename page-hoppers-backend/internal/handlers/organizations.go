@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateOrganizationAdminRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"` // a starting password; they can change it with a password reset
+}
+
+// OrganizationJoinCodes are the codes the organization's admins hand out so people can register into it
+type OrganizationJoinCodes struct {
+	Parent  string `json:"parent,omitempty"`
+	Teacher string `json:"teacher,omitempty"`
+}
+
+// OrganizationMember is an account in an organization as its admins see it
+type OrganizationMember struct {
+	ID              uint   `json:"id"`
+	Name            string `json:"name"`
+	Email           string `json:"email,omitempty"`
+	Role            string `json:"role"`
+	ParentID        *uint  `json:"parent_id,omitempty"`
+	EmailIsVerified bool   `json:"email_verified"`
+}
+
+// ---------------------------
+// The caller's organization (anyone signed in)
+func (h *AuthHandler) GetOrganization(c *gin.Context) {
+	org, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// ---------------------------
+// Rename the organization (admin). The slug people register with doesn't change.
+func (h *AuthHandler) UpdateOrganization(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	org, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+
+	if !h.recordAdminAction(c, principal, models.AdminActionRename, 0, fmt.Sprintf("%q to %q", org.Name, req.Name)) {
+		return
+	}
+	if err := h.DB.Model(org).Update("name", req.Name).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update organization"})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// ---------------------------
+// List the organization's accounts, optionally ?role=parent|child|teacher|admin (admin)
+func (h *AuthHandler) GetOrganizationMembers(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	users, err := repository.OrganizationMembers(h.DB, principal.OrganizationID, c.Query("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch members"})
+		return
+	}
+
+	members := make([]OrganizationMember, 0, len(users))
+	for _, user := range users {
+		members = append(members, OrganizationMember{
+			ID:              user.ID,
+			Name:            user.Name,
+			Email:           user.Email,
+			Role:            user.Role,
+			ParentID:        user.ParentID,
+			EmailIsVerified: user.EmailVerifiedAt != nil,
+		})
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// ---------------------------
+// List every classroom in the organization with its teacher and class size (admin)
+func (h *AuthHandler) GetOrganizationClassrooms(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	classrooms, err := repository.OrganizationClassrooms(h.DB, principal.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch classrooms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, classrooms)
+}
+
+// ---------------------------
+// Add another admin to the organization (admin)
+func (h *AuthHandler) CreateOrganizationAdmin(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateOrganizationAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.Name == "" || req.Email == "" || req.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name, email, and password are required"})
+		return
+	}
+
+	var existing models.User
+	if err := h.DB.Where("email = ?", req.Email).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	admin := models.User{
+		OrganizationID: principal.OrganizationID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       string(hashedPassword),
+		Role:           policy.RoleAdmin,
+	}
+	if !h.recordAdminAction(c, principal, models.AdminActionCreateAdmin, 0, req.Email) {
+		return
+	}
+	if err := h.DB.Create(&admin).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create admin"})
+		return
+	}
+	h.sendVerificationEmail(c.Request.Context(), &admin)
+
+	c.JSON(http.StatusCreated, OrganizationMember{ID: admin.ID, Name: admin.Name, Email: admin.Email, Role: admin.Role})
+}
+
+// ---------------------------
+// The codes families and teachers need to register into the organization (admin)
+func (h *AuthHandler) GetOrganizationJoinCodes(c *gin.Context) {
+	org, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationJoinCodes{Parent: org.ParentJoinCode, Teacher: org.TeacherJoinCode})
+}
+
+// ---------------------------
+// Issue a new join code for :role, parent or teacher; the old one stops working (admin)
+func (h *AuthHandler) RotateOrganizationJoinCode(c *gin.Context) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	role := c.Param("role")
+	if role != policy.RoleParent && role != policy.RoleTeacher {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Join codes are for parents or teachers"})
+		return
+	}
+	org, ok := h.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	if !h.recordAdminAction(c, principal, models.AdminActionRotateJoinCode, 0, role) {
+		return
+	}
+	if _, err := repository.RotateOrganizationJoinCode(h.DB, org, role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue join code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationJoinCodes{Parent: org.ParentJoinCode, Teacher: org.TeacherJoinCode})
+}
+
+// ---------------------------
+// Admin login
+func (h *AuthHandler) AdminLogin(c *gin.Context) {
+	h.passwordLogin(c, policy.RoleAdmin)
+}
+
+// loadOrganization fetches the organization from the caller's token
+func (h *AuthHandler) loadOrganization(c *gin.Context) (*models.Organization, bool) {
+	principal, ok := policy.FromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	var org models.Organization
+	if err := h.DB.First(&org, principal.OrganizationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+		return nil, false
+	}
+	return &org, true
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// currentUser returns the authenticated user_id and role set by the auth middleware.
+func currentUser(c *gin.Context) (uint, string, bool) {
+	principal, ok := policy.FromContext(c)
+	return principal.UserID, principal.Role, ok
+}
+
+// errViewOnly is the 403 message for a guardian who can see a child but not change anything
+const errViewOnly = "You can view this child's reading but not change it"
+
+// canManageChild reports whether the caller may read and edit the child's data (see policy.CanWriteChild)
+func canManageChild(c *gin.Context, db *gorm.DB, childID uint) bool {
+	principal, ok := policy.FromContext(c)
+	return ok && policy.CanWriteChild(db, principal, childID)
+}
+
+// canViewChild reports whether the caller may read the child's data (see policy.CanReadChild)
+func canViewChild(c *gin.Context, db *gorm.DB, childID uint) bool {
+	principal, ok := policy.FromContext(c)
+	return ok && policy.CanReadChild(db, principal, childID)
+}
+
+// childAccess checks the caller may see the child, or change the child's data when write is set.
+// It writes a 404 for a child the caller can't see and a 403 for a viewer trying to change something.
+func childAccess(c *gin.Context, db *gorm.DB, childID uint, write bool) bool {
+	if !canViewChild(c, db, childID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return false
+	}
+	if write && !canManageChild(c, db, childID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errViewOnly})
+		return false
+	}
+	return true
+}
+
+// parseUintParam reads a numeric path parameter such as :id.
+func parseUintParam(c *gin.Context, name string) (uint, bool) {
+	value, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(value), true
+}
+
+// loadOwnedReadingLog fetches the reading log named by :id and checks the caller may manage it.
+// It writes the error response itself and returns false when the request should stop.
+// Pass unscoped to include soft-deleted logs.
+func (h *ReadingLogHandler) loadOwnedReadingLog(c *gin.Context, unscoped bool) (*models.ReadingLog, bool) {
+	return h.loadReadingLog(c, unscoped, true)
+}
+
+// loadVisibleReadingLog is loadOwnedReadingLog for requests that only read the log, which viewers may do too
+func (h *ReadingLogHandler) loadVisibleReadingLog(c *gin.Context) (*models.ReadingLog, bool) {
+	return h.loadReadingLog(c, false, false)
+}
+
+func (h *ReadingLogHandler) loadReadingLog(c *gin.Context, unscoped, write bool) (*models.ReadingLog, bool) {
+	_, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	logID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading log ID"})
+		return nil, false
+	}
+
+	query := h.DB
+	if unscoped {
+		query = query.Unscoped()
+	}
+
+	var readingLog models.ReadingLog
+	if err := query.First(&readingLog, logID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading log not found"})
+		return nil, false
+	}
+
+	// Report someone else's log as missing so IDs can't be probed.
+	if !canViewChild(c, h.DB, readingLog.ChildID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading log not found"})
+		return nil, false
+	}
+	if write && !canManageChild(c, h.DB, readingLog.ChildID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errViewOnly})
+		return nil, false
+	}
+
+	return &readingLog, true
+}
+
+// familyLocation returns the time zone the child's family reads dates in.
+// It writes a 500 response and returns false if the lookup fails.
+func familyLocation(c *gin.Context, db *gorm.DB, childID uint) (*time.Location, bool) {
+	loc, err := repository.FamilyLocation(db, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load time zone"})
+		return nil, false
+	}
+	return loc, true
+}
+
+// parseFamilyDate reads a YYYY-MM-DD date as midnight in the family's time zone.
+// The result is in UTC so stored times compare correctly whatever zone they came from.
+func parseFamilyDate(value string, loc *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.UTC(), nil
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+const (
+	defaultPointsLedgerSize = 50
+	maxPointsLedgerSize     = 200
+)
+
+// PointRuleRequest is one rule in a PUT /point-rules body, e.g. {"event": "minutes", "points": 1, "per": 10}
+type PointRuleRequest struct {
+	Event  string `json:"event"`
+	Points int    `json:"points"`
+	Per    int    `json:"per,omitempty"`
+}
+
+// AdjustPointsRequest adds points, or takes them away when negative
+type AdjustPointsRequest struct {
+	Points int    `json:"points"`
+	Note   string `json:"note"`
+}
+
+// PointsResponse is a child's balance and their most recent ledger entries
+type PointsResponse struct {
+	repository.PointsBalance
+	Entries []models.PointsLedgerEntry `json:"entries"`
+}
+
+// ---------------------------
+// Show how the family earns points (a guardian or the child). Adults in more than one family choose it with ?child_id=.
+func (h *RewardsHandler) GetPointRules(c *gin.Context) {
+	parentID, ok := h.familyParentID(c, false)
+	if !ok {
+		return
+	}
+
+	rules, err := repository.GetPointRules(h.DB, parentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch point rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// ---------------------------
+// Replace the family's point rules (parent or co-parent). An empty list goes back to the defaults.
+func (h *RewardsHandler) SetPointRules(c *gin.Context) {
+	parentID, ok := h.familyParentID(c, true)
+	if !ok {
+		return
+	}
+
+	var req []PointRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rules := make([]models.PointRule, 0, len(req))
+	for _, r := range req {
+		if !models.IsValidPointEvent(r.Event) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Event must be 'book_completed', 'session', 'minutes' or 'pages'"})
+			return
+		}
+		if r.Points < 0 || r.Per < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Points and per can't be negative"})
+			return
+		}
+		rules = append(rules, models.PointRule{Event: r.Event, Points: r.Points, Per: r.Per})
+	}
+
+	if err := repository.SetPointRules(h.DB, parentID, rules); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save point rules"})
+		return
+	}
+
+	saved, err := repository.GetPointRules(h.DB, parentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch point rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// ---------------------------
+// Show a child's points balance and recent ledger entries (the child or their parent)
+func (h *RewardsHandler) GetChildPoints(c *gin.Context) {
+	childID, ok := h.pointsChild(c, false)
+	if !ok {
+		return
+	}
+
+	limit := defaultPointsLedgerSize
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be a positive number"})
+			return
+		}
+		limit = min(n, maxPointsLedgerSize)
+	}
+
+	balance, err := repository.GetPointsBalance(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch points"})
+		return
+	}
+	entries, err := repository.GetPointsLedger(h.DB, childID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch points"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PointsResponse{PointsBalance: balance, Entries: entries})
+}
+
+// ---------------------------
+// Add or take away a child's points by hand (parent)
+func (h *RewardsHandler) AdjustChildPoints(c *gin.Context) {
+	childID, ok := h.pointsChild(c, true)
+	if !ok {
+		return
+	}
+	parentID, _, _ := currentUser(c)
+
+	var req AdjustPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.Points == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Points must not be zero"})
+		return
+	}
+
+	entry, err := repository.AdjustPoints(h.DB, childID, req.Points, req.Note, parentID)
+	if err != nil {
+		if err == repository.ErrInsufficientPoints {
+			c.JSON(http.StatusConflict, gin.H{"error": "The child doesn't have that many points"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to adjust points"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// pointsChild reads :id and checks the caller may see the child's points, or change them when write is set
+func (h *RewardsHandler) pointsChild(c *gin.Context, write bool) (uint, bool) {
+	_, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return 0, false
+	}
+
+	if !childAccess(c, h.DB, childID, write) {
+		return 0, false
+	}
+	if write && role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can adjust points"})
+		return 0, false
+	}
+	return childID, true
+}
+
+// awardCompletionPoints credits a log that is now completed and returns the points it earned.
+// Like awardAchievements, a failure is logged rather than failing the request.
+func (h *ReadingLogHandler) awardCompletionPoints(readingLog *models.ReadingLog) int {
+	entry, err := repository.AwardCompletionPoints(h.DB, readingLog)
+	if err != nil {
+		log.Printf("Failed to award points for reading log %d: %v", readingLog.ID, err)
+		return 0
+	}
+	if entry == nil {
+		return 0
+	}
+	return entry.Points
+}
+
+// awardSessionPoints credits a newly logged reading session
+func (h *ReadingLogHandler) awardSessionPoints(session *models.ReadingSession) {
+	if _, err := repository.AwardSessionPoints(h.DB, session); err != nil {
+		log.Printf("Failed to award points for reading session %d: %v", session.ID, err)
+	}
+}
+
+// repriceSessionPoints brings what an edited or deleted session earned in line with it, logging any failure
+func (h *ReadingLogHandler) repriceSessionPoints(session *models.ReadingSession) {
+	if err := repository.RepriceSessionPoints(h.DB, session); err != nil {
+		log.Printf("Failed to reprice points for reading session %d: %v", session.ID, err)
+	}
+}
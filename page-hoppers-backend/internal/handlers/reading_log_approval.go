@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+// ReviewReadingLogRequest carries the parent's optional comment on an approval or rejection
+type ReviewReadingLogRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ---------------------------
+// List reading logs waiting for approval, oldest first (parent; ?child_id= for one child)
+func (h *ReadingLogHandler) GetPendingReadingLogs(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can review reading logs"})
+		return
+	}
+
+	query := h.DB.Where("approval_status = ?", models.ApprovalPending)
+	if value := c.Query("child_id"); value != "" {
+		childID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+			return
+		}
+		if !childAccess(c, h.DB, uint(childID), true) {
+			return
+		}
+		query = query.Where("child_id = ?", childID)
+	} else {
+		query = query.Where("child_id IN (?)", repository.GuardedChildIDs(h.DB, userID, repository.ManagingGuardianRoles...))
+	}
+
+	var logs []models.ReadingLog
+	if err := query.Order("date ASC, id ASC").Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+		return
+	}
+
+	responses := []ReadingLogResponse{}
+	for _, log := range logs {
+		responses = append(responses, newReadingLogResponse(log))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// ---------------------------
+// Approve a child's reading log so it counts toward summaries, goals and points (parent)
+func (h *ReadingLogHandler) ApproveReadingLog(c *gin.Context) {
+	h.reviewReadingLog(c, true)
+}
+
+// ---------------------------
+// Reject a child's reading log, with an optional comment saying why (parent)
+func (h *ReadingLogHandler) RejectReadingLog(c *gin.Context) {
+	h.reviewReadingLog(c, false)
+}
+
+func (h *ReadingLogHandler) reviewReadingLog(c *gin.Context, approve bool) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return
+	}
+	userID, role, _ := currentUser(c)
+	if role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can review reading logs"})
+		return
+	}
+
+	// The comment is optional, so an empty body is fine
+	var req ReviewReadingLogRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if err := repository.ReviewReadingLog(h.DB, readingLog, approve, userID, req.Comment, time.Now()); err != nil {
+		if err == repository.ErrNotPendingApproval {
+			c.JSON(http.StatusConflict, gin.H{"error": "This reading log isn't waiting for approval"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review reading log"})
+		return
+	}
+
+	resp := newReadingLogResponse(*readingLog)
+	if approve {
+		// Points and badges held back while the log waited are earned now, and sessions edited
+		// while it waited are paid at what they say now
+		resp.PointsEarned = h.awardCompletionPoints(readingLog)
+		var sessions []models.ReadingSession
+		h.DB.Where("reading_log_id = ?", readingLog.ID).Find(&sessions)
+		for i := range sessions {
+			h.repriceSessionPoints(&sessions[i])
+		}
+		resp.NewAchievements = h.awardAchievements(readingLog.ChildID)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// holdChildChange puts a log back to waiting for the parent when the child changed it, or a session on it,
+// themselves and their logs need approval, even if it was already approved. A parent's changes don't need it.
+// It writes a 500 and returns false if the log couldn't be held.
+func (h *ReadingLogHandler) holdChildChange(c *gin.Context, readingLog *models.ReadingLog) bool {
+	if err := holdChildChangeIn(h.DB, c, readingLog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+		return false
+	}
+	return true
+}
+
+// holdChildChangeIn is holdChildChange within db, e.g. the transaction that made the change
+func holdChildChangeIn(db *gorm.DB, c *gin.Context, readingLog *models.ReadingLog) error {
+	if _, role, _ := currentUser(c); role != policy.RoleChild || readingLog.ApprovalStatus == models.ApprovalPending {
+		return nil
+	}
+	requiresApproval, err := repository.ChildRequiresLogApproval(db, readingLog.ChildID)
+	if err != nil || !requiresApproval {
+		return err
+	}
+	return repository.HoldForApproval(db, readingLog)
+}
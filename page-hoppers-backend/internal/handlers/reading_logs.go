@@ -1,23 +1,32 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"page-hoppers-backend/internal/books"
 	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
 )
 
 type ReadingLogHandler struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Books *books.SearchService // resolves ISBNs; may be nil, which disables by-ISBN logging
+
+	SchoolCalendar *repository.SchoolCalendar // terms for summary grouping; nil uses the default
 }
 
-func NewReadingLogHandler(db *gorm.DB) *ReadingLogHandler {
+func NewReadingLogHandler(db *gorm.DB, bookSearch *books.SearchService) *ReadingLogHandler {
 	return &ReadingLogHandler{
-		DB: db,
+		DB:    db,
+		Books: bookSearch,
 	}
 }
 
@@ -26,21 +35,137 @@ func NewReadingLogHandler(db *gorm.DB) *ReadingLogHandler {
 type CreateReadingLogRequest struct {
 	Title          string `json:"title"`
 	Author         string `json:"author,omitempty"`
-	Status         string `json:"status"` // "started" or "completed"
+	Status         string `json:"status"` // initial status: "want_to_read", "reading" or "completed"
 	Date           string `json:"date"`   // ISO date string
 	OpenLibraryKey string `json:"open_library_key,omitempty"`
 	CoverID        *int   `json:"cover_id,omitempty"`
+	ISBN10         string `json:"isbn10,omitempty"`
+	ISBN13         string `json:"isbn13,omitempty"`
+	TotalPages     *int   `json:"total_pages,omitempty"`
+}
+
+// CreateReadingLogByISBNRequest logs a book from just its ISBN; the rest is looked up
+type CreateReadingLogByISBNRequest struct {
+	ISBN       string `json:"isbn"`   // ISBN-10 or ISBN-13, hyphens allowed
+	Status     string `json:"status"` // initial status: "want_to_read", "reading" or "completed"
+	Date       string `json:"date"`   // ISO date string
+	TotalPages *int   `json:"total_pages,omitempty"`
 }
 
 type ReadingLogResponse struct {
-	ID             uint      `json:"id"`
-	Title          string    `json:"title"`
-	Author         string    `json:"author,omitempty"`
-	Status         string    `json:"status"`
-	Date           time.Time `json:"date"`
-	OpenLibraryKey string    `json:"open_library_key,omitempty"`
-	CoverID        *int      `json:"cover_id,omitempty"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uint       `json:"id"`
+	Title          string     `json:"title"`
+	Author         string     `json:"author,omitempty"`
+	Status         string     `json:"status"`
+	Date           time.Time  `json:"date"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	OpenLibraryKey string     `json:"open_library_key,omitempty"`
+	CoverID        *int       `json:"cover_id,omitempty"`
+	ISBN10         string     `json:"isbn10,omitempty"`
+	ISBN13         string     `json:"isbn13,omitempty"`
+	BookID         *uint      `json:"book_id,omitempty"`
+	TotalPages     *int       `json:"total_pages,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+
+	Progress        *repository.ReadingProgress `json:"progress,omitempty"`
+	NewAchievements []models.ChildAchievement   `json:"new_achievements,omitempty"` // badges this change earned
+	PointsEarned    int                         `json:"points_earned,omitempty"`
+
+	ApprovalStatus string     `json:"approval_status,omitempty"` // "pending", "approved" or "rejected" when the parent reviews logs
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	ReviewComment  string     `json:"review_comment,omitempty"`
+}
+
+// UpdateReadingLogRequest holds the fields a PATCH may change; nil fields are left alone.
+type UpdateReadingLogRequest struct {
+	Title          *string `json:"title,omitempty"`
+	Author         *string `json:"author,omitempty"`
+	Status         *string `json:"status,omitempty"`
+	Date           *string `json:"date,omitempty"`
+	OpenLibraryKey *string `json:"open_library_key,omitempty"`
+	CoverID        *int    `json:"cover_id,omitempty"`
+	TotalPages     *int    `json:"total_pages,omitempty"`
+}
+
+type TransitionReadingLogRequest struct {
+	Status string `json:"status"`
+	Date   string `json:"date,omitempty"` // ISO date string, defaults to today
+}
+
+func newReadingLogResponse(log models.ReadingLog) ReadingLogResponse {
+	resp := ReadingLogResponse{
+		ID:             log.ID,
+		Title:          log.Title,
+		Author:         log.Author,
+		Status:         log.Status,
+		Date:           log.Date,
+		StartedAt:      log.StartedAt,
+		CompletedAt:    log.CompletedAt,
+		OpenLibraryKey: log.OpenLibraryKey,
+		CoverID:        log.CoverID,
+		ISBN13:         log.ISBN,
+		BookID:         log.BookID,
+		TotalPages:     log.TotalPages,
+		CreatedAt:      log.CreatedAt,
+		ApprovalStatus: log.ApprovalStatus,
+		ReviewedAt:     log.ReviewedAt,
+		ReviewComment:  log.ReviewComment,
+	}
+	if log.ISBN != "" {
+		resp.ISBN10, _ = books.ISBN13To10(log.ISBN)
+	}
+	if log.DeletedAt.Valid {
+		deletedAt := log.DeletedAt.Time
+		resp.DeletedAt = &deletedAt
+	}
+	return resp
+}
+
+// requestISBN validates the optional ISBN-10/ISBN-13 pair and returns the ISBN-13 ("" if neither was sent)
+func requestISBN(isbn10, isbn13 string) (string, error) {
+	var from10, from13 string
+	var err error
+	if isbn10 != "" {
+		if !books.IsValidISBN10(isbn10) {
+			return "", errors.New("Invalid ISBN-10")
+		}
+		from10, err = books.ISBN10To13(isbn10)
+		if err != nil {
+			return "", err
+		}
+	}
+	if isbn13 != "" {
+		if !books.IsValidISBN13(isbn13) {
+			return "", errors.New("Invalid ISBN-13")
+		}
+		from13 = books.NormalizeISBN(isbn13)
+	}
+	if from10 != "" && from13 != "" && from10 != from13 {
+		return "", errors.New("ISBN-10 and ISBN-13 are different books")
+	}
+	if from13 != "" {
+		return from13, nil
+	}
+	return from10, nil
+}
+
+// readingLogResponses builds list responses with each log's session progress attached
+func (h *ReadingLogHandler) readingLogResponses(logs []models.ReadingLog) ([]ReadingLogResponse, error) {
+	progress, err := repository.GetReadingProgress(h.DB, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ReadingLogResponse, 0, len(logs))
+	for _, log := range logs {
+		resp := newReadingLogResponse(log)
+		p := progress[log.ID]
+		resp.Progress = &p
+		responses = append(responses, resp)
+	}
+	return responses, nil
 }
 
 // ---------------------------
@@ -64,14 +189,19 @@ func (h *ReadingLogHandler) CreateReadingLog(c *gin.Context) {
 		return
 	}
 
-	if req.Status != "started" && req.Status != "completed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be 'started' or 'completed'"})
+	isbn13, err := requestISBN(req.ISBN10, req.ISBN13)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
+	}
+
+	date, ok := validateNewLog(c, req.Status, req.Date, req.TotalPages, loc)
+	if !ok {
 		return
 	}
 
@@ -81,36 +211,24 @@ func (h *ReadingLogHandler) CreateReadingLog(c *gin.Context) {
 		return
 	}
 
-	readingLog := models.ReadingLog{
+	book, err := repository.FindOrCreateBook(h.DB, models.Book{
 		Title:          req.Title,
 		Author:         req.Author,
-		Status:         req.Status,
-		Date:           date,
-		ChildID:        childID,
 		OpenLibraryKey: req.OpenLibraryKey,
+		ISBN:           isbn13,
 		CoverID:        req.CoverID,
-	}
-
-	if err := h.DB.Create(&readingLog).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reading log"})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save book"})
 		return
 	}
 
-	c.JSON(http.StatusOK, ReadingLogResponse{
-		ID:             readingLog.ID,
-		Title:          readingLog.Title,
-		Author:         readingLog.Author,
-		Status:         readingLog.Status,
-		Date:           readingLog.Date,
-		OpenLibraryKey: readingLog.OpenLibraryKey,
-		CoverID:        readingLog.CoverID,
-		CreatedAt:      readingLog.CreatedAt,
-	})
+	h.logBook(c, &child, book, req.Status, date, req.TotalPages)
 }
 
 // ---------------------------
-// Get all reading logs for a child
-func (h *ReadingLogHandler) GetReadingLogs(c *gin.Context) {
+// Create a reading log from an ISBN, looking up title, author and cover through the book providers
+func (h *ReadingLogHandler) CreateReadingLogByISBN(c *gin.Context) {
 	childIDValue, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -118,44 +236,165 @@ func (h *ReadingLogHandler) GetReadingLogs(c *gin.Context) {
 	}
 	childID := childIDValue.(uint)
 
+	var req CreateReadingLogByISBNRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if req.ISBN == "" || req.Status == "" || req.Date == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ISBN, status, and date are required"})
+		return
+	}
+
+	if _, err := books.ParseISBN(req.ISBN); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ISBN"})
+		return
+	}
+
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
+	}
+
+	date, ok := validateNewLog(c, req.Status, req.Date, req.TotalPages, loc)
+	if !ok {
+		return
+	}
+
 	var child models.User
 	if err := h.DB.Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
 		return
 	}
 
-	var logs []models.ReadingLog
-	if err := h.DB.Where("child_id = ?", childID).Order("date DESC, created_at DESC").Find(&logs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+	if h.Books == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Book lookup is not configured"})
 		return
 	}
 
-	var responses []ReadingLogResponse
-	for _, log := range logs {
-		responses = append(responses, ReadingLogResponse{
-			ID:             log.ID,
-			Title:          log.Title,
-			Author:         log.Author,
-			Status:         log.Status,
-			Date:           log.Date,
-			OpenLibraryKey: log.OpenLibraryKey,
-			CoverID:        log.CoverID,
-			CreatedAt:      log.CreatedAt,
-		})
+	book, err := h.Books.ResolveISBN(c.Request.Context(), req.ISBN)
+	if err != nil {
+		if errors.Is(err, books.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No book found for that ISBN"})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Book lookup is unavailable right now"})
+		return
 	}
 
-	c.JSON(http.StatusOK, responses)
+	totalPages := req.TotalPages
+	if totalPages == nil && book.PageCount > 0 {
+		pageCount := book.PageCount
+		totalPages = &pageCount
+	}
+
+	h.logBook(c, &child, book, req.Status, date, totalPages)
+}
+
+// validateNewLog checks the status, date and page count shared by both create endpoints
+// Dates are read as midnight in the family's time zone.
+func validateNewLog(c *gin.Context, status, dateStr string, totalPages *int, loc *time.Location) (time.Time, bool) {
+	if totalPages != nil && *totalPages <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Total pages must be positive"})
+		return time.Time{}, false
+	}
+
+	if !models.IsInitialReadingStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be 'want_to_read', 'reading' or 'completed'"})
+		return time.Time{}, false
+	}
+
+	date, err := parseFamilyDate(dateStr, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// logBook records that the child is reading book, reusing their existing log for it if there is one.
+// For a child whose logs need approval, the new or moved log waits for their parent.
+func (h *ReadingLogHandler) logBook(c *gin.Context, child *models.User, book *models.Book, status string, date time.Time, totalPages *int) {
+	childID := child.ID
+	// One log per book: logging a book the child already has moves that log along instead,
+	// so older clients that post "started" and later "completed" still end up with a single row.
+	var existing models.ReadingLog
+	if err := h.DB.Where("child_id = ? AND book_id = ?", childID, book.ID).
+		Order("date DESC").First(&existing).Error; err == nil {
+		if err := repository.TransitionReadingLog(h.DB, &existing, status, date, childID); err != nil {
+			if err == repository.ErrInvalidTransition {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":          "This book is already logged as '" + existing.Status + "'",
+					"reading_log_id": existing.ID,
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+			return
+		}
+		if child.RequiresLogApproval {
+			if err := repository.HoldForApproval(h.DB, &existing); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+				return
+			}
+		}
+		resp := newReadingLogResponse(existing)
+		resp.PointsEarned = h.awardCompletionPoints(&existing)
+		resp.NewAchievements = h.awardAchievements(childID)
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	readingLog := models.ReadingLog{
+		Status:     status,
+		Date:       date,
+		ChildID:    childID,
+		TotalPages: totalPages,
+	}
+	if child.RequiresLogApproval {
+		readingLog.ApprovalStatus = models.ApprovalPending
+	}
+	repository.LinkReadingLogToBook(&readingLog, book)
+
+	if err := repository.StartReadingLog(h.DB, &readingLog, childID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reading log"})
+		return
+	}
+
+	resp := newReadingLogResponse(readingLog)
+	resp.PointsEarned = h.awardCompletionPoints(&readingLog)
+	resp.NewAchievements = h.awardAchievements(childID)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ---------------------------
+// Get all reading logs for a child
+func (h *ReadingLogHandler) GetReadingLogs(c *gin.Context) {
+	childIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	childID := childIDValue.(uint)
+
+	var child models.User
+	if err := h.DB.Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
+	}
+
+	h.listReadingLogs(c, childID)
 }
 
 // ---------------------------
 // Get reading logs for a specific child (parent access)
 func (h *ReadingLogHandler) GetChildReadingLogs(c *gin.Context) {
-	parentIDValue, exists := c.Get("user_id")
+	_, role, exists := currentUser(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
-	parentID := parentIDValue.(uint)
 
 	childIDStr := c.Query("child_id")
 	if childIDStr == "" {
@@ -170,31 +409,396 @@ func (h *ReadingLogHandler) GetChildReadingLogs(c *gin.Context) {
 	}
 	childID := uint(childIDUint)
 
-	var child models.User
-	if err := h.DB.Where("id = ? AND parent_id = ? AND role = ?", childID, parentID, "child").First(&child).Error; err != nil {
+	// Any guardian may read the logs, viewers included
+	if role != policy.RoleParent || !canViewChild(c, h.DB, childID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found or unauthorized"})
 		return
 	}
 
-	var logs []models.ReadingLog
-	if err := h.DB.Where("child_id = ?", childID).Order("date DESC, created_at DESC").Find(&logs).Error; err != nil {
+	h.listReadingLogs(c, childID)
+}
+
+// ReadingLogListResponse is one page of reading logs. Pass NextCursor back as ?cursor= for the next page.
+type ReadingLogListResponse struct {
+	Items      []ReadingLogResponse `json:"items"`
+	Total      int64                `json:"total"` // logs matching the filters, across all pages
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// parseReadingLogQuery reads the list filters:
+// ?status=reading,completed&from=YYYY-MM-DD&to=YYYY-MM-DD&author=&title=&sort=date|title|created_at&order=asc|desc&limit=&cursor=
+func parseReadingLogQuery(c *gin.Context, loc *time.Location) (repository.ReadingLogQuery, bool) {
+	query := repository.ReadingLogQuery{
+		Author: strings.TrimSpace(c.Query("author")),
+		Title:  strings.TrimSpace(c.Query("title")),
+		SortBy: c.DefaultQuery("sort", repository.SortByDate),
+		Cursor: c.Query("cursor"),
+	}
+
+	if statuses := c.Query("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			status = strings.TrimSpace(status)
+			if !models.IsValidReadingStatus(status) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status filter: " + status})
+				return query, false
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		if value := c.Query(bound.param); value != "" {
+			date, err := time.ParseInLocation("2006-01-02", value, loc)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid '" + bound.param + "' date. Use YYYY-MM-DD"})
+				return query, false
+			}
+			*bound.target = &date
+		}
+	}
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+		return query, false
+	}
+
+	switch query.SortBy {
+	case repository.SortByDate, repository.SortByCreatedAt:
+		query.Desc = true // newest first unless asked otherwise
+	case repository.SortByTitle:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sort must be 'date', 'title' or 'created_at'"})
+		return query, false
+	}
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		query.Desc = false
+	case "desc":
+		query.Desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order must be 'asc' or 'desc'"})
+		return query, false
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be a positive number"})
+			return query, false
+		}
+		query.Limit = limit
+	}
+
+	return query, true
+}
+
+// listReadingLogs responds with one filtered page of a child's logs
+func (h *ReadingLogHandler) listReadingLogs(c *gin.Context, childID uint) {
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
+	}
+
+	query, ok := parseReadingLogQuery(c, loc)
+	if !ok {
+		return
+	}
+
+	page, err := repository.ListReadingLogs(h.DB, childID, query)
+	if err != nil {
+		if err == repository.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
 		return
 	}
 
-	var responses []ReadingLogResponse
-	for _, log := range logs {
-		responses = append(responses, ReadingLogResponse{
-			ID:             log.ID,
-			Title:          log.Title,
-			Author:         log.Author,
-			Status:         log.Status,
-			Date:           log.Date,
-			OpenLibraryKey: log.OpenLibraryKey,
-			CoverID:        log.CoverID,
-			CreatedAt:      log.CreatedAt,
+	responses, err := h.readingLogResponses(page.Logs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReadingLogListResponse{
+		Items:      responses,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// ---------------------------
+// Update a reading log (child owner or their parent)
+func (h *ReadingLogHandler) UpdateReadingLog(c *gin.Context) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return
+	}
+
+	var req UpdateReadingLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	renamed := (req.Title != nil && *req.Title != readingLog.Title) || (req.Author != nil && *req.Author != readingLog.Author)
+	bookChanged := renamed || (req.OpenLibraryKey != nil && *req.OpenLibraryKey != readingLog.OpenLibraryKey)
+	if req.Title != nil {
+		if *req.Title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+			return
+		}
+		readingLog.Title = *req.Title
+	}
+	if req.Author != nil {
+		readingLog.Author = *req.Author
+	}
+	if req.Status != nil && models.NormalizeReadingStatus(*req.Status) != readingLog.Status {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use POST /api/reading-logs/:id/transitions to change status"})
+		return
+	}
+	if req.Date != nil {
+		loc, ok := familyLocation(c, h.DB, readingLog.ChildID)
+		if !ok {
+			return
+		}
+		date, err := parseFamilyDate(*req.Date, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		readingLog.Date = date
+	}
+	// The old key, ISBN and cover belong to the old book, so a new title or author without a new key
+	// is matched by title and author instead of straight back to the book it was corrected from
+	if renamed && req.OpenLibraryKey == nil {
+		readingLog.OpenLibraryKey = ""
+		readingLog.ISBN = ""
+		readingLog.CoverID = nil
+	}
+	if req.OpenLibraryKey != nil {
+		readingLog.OpenLibraryKey = *req.OpenLibraryKey
+	}
+	if req.CoverID != nil {
+		readingLog.CoverID = req.CoverID
+	}
+
+	// A corrected title or author may be a different book; relink rather than renaming the shared entry.
+	// Only the log's own copy of the cover changes otherwise, and a cover sent with the correction wins over the book's.
+	if bookChanged {
+		book, err := repository.FindOrCreateBook(h.DB, models.Book{
+			Title:          readingLog.Title,
+			Author:         readingLog.Author,
+			OpenLibraryKey: readingLog.OpenLibraryKey,
+			CoverID:        readingLog.CoverID,
 		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save book"})
+			return
+		}
+		repository.LinkReadingLogToBook(readingLog, book)
+		if !h.refuseDuplicateLog(c, readingLog) {
+			return
+		}
+		if req.CoverID != nil {
+			readingLog.CoverID = req.CoverID
+		}
+	}
+	if req.TotalPages != nil {
+		if *req.TotalPages <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Total pages must be positive"})
+			return
+		}
+		readingLog.TotalPages = req.TotalPages
+	}
+
+	if !h.holdChildChange(c, readingLog) {
+		return
+	}
+	if err := h.DB.Omit("Book").Save(readingLog).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+		return
+	}
+
+	resp := newReadingLogResponse(*readingLog)
+	resp.NewAchievements = h.awardAchievements(readingLog.ChildID)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ---------------------------
+// Delete a reading log (soft delete, restorable from the trash)
+func (h *ReadingLogHandler) DeleteReadingLog(c *gin.Context) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.Delete(readingLog).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reading log"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ---------------------------
+// List soft-deleted reading logs. Children see their own; parents pass ?child_id=.
+func (h *ReadingLogHandler) GetDeletedReadingLogs(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	childID := userID
+	if role == policy.RoleParent {
+		childIDUint, err := strconv.ParseUint(c.Query("child_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Valid child ID is required"})
+			return
+		}
+		childID = uint(childIDUint)
+	}
+
+	if !canViewChild(c, h.DB, childID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found or unauthorized"})
+		return
+	}
+
+	var logs []models.ReadingLog
+	if err := h.DB.Unscoped().
+		Where("child_id = ? AND deleted_at IS NOT NULL", childID).
+		Order("deleted_at DESC").
+		Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deleted reading logs"})
+		return
+	}
+
+	responses := []ReadingLogResponse{}
+	for _, log := range logs {
+		responses = append(responses, newReadingLogResponse(log))
 	}
 
 	c.JSON(http.StatusOK, responses)
-}
\ No newline at end of file
+}
+
+// ---------------------------
+// Restore a soft-deleted reading log from the trash
+func (h *ReadingLogHandler) RestoreReadingLog(c *gin.Context) {
+	readingLog, ok := h.loadOwnedReadingLog(c, true)
+	if !ok {
+		return
+	}
+
+	if !readingLog.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Reading log is not deleted"})
+		return
+	}
+
+	if !h.refuseDuplicateLog(c, readingLog) {
+		return
+	}
+
+	if err := h.DB.Unscoped().Model(readingLog).Update("deleted_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore reading log"})
+		return
+	}
+	readingLog.DeletedAt = gorm.DeletedAt{}
+
+	resp := newReadingLogResponse(*readingLog)
+	resp.NewAchievements = h.awardAchievements(readingLog.ChildID)
+	c.JSON(http.StatusOK, resp)
+}
+
+// refuseDuplicateLog writes a 409 and returns false if the child already has another log for readingLog's book
+func (h *ReadingLogHandler) refuseDuplicateLog(c *gin.Context, readingLog *models.ReadingLog) bool {
+	other, err := repository.OtherReadingLogForBook(h.DB, readingLog)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+		return false
+	}
+	if other != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "This book is already logged as '" + other.Status + "'",
+			"reading_log_id": other.ID,
+		})
+		return false
+	}
+	return true
+}
+
+// ---------------------------
+// Move a reading log to a new status (want_to_read -> reading -> completed, etc.)
+func (h *ReadingLogHandler) TransitionReadingLog(c *gin.Context) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return
+	}
+	userID, _, _ := currentUser(c)
+
+	var req TransitionReadingLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if !models.IsValidReadingStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status"})
+		return
+	}
+
+	at := time.Now()
+	if req.Date != "" {
+		loc, ok := familyLocation(c, h.DB, readingLog.ChildID)
+		if !ok {
+			return
+		}
+		date, err := parseFamilyDate(req.Date, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		at = date
+	}
+
+	// The move and any hold for approval land together, so a move never counts without its review
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repository.TransitionReadingLog(tx, readingLog, req.Status, at, userID); err != nil {
+			return err
+		}
+		return holdChildChangeIn(tx, c, readingLog)
+	})
+	if err == repository.ErrInvalidTransition {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot move from '" + readingLog.Status + "' to '" + req.Status + "'"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading log"})
+		return
+	}
+
+	resp := newReadingLogResponse(*readingLog)
+	resp.PointsEarned = h.awardCompletionPoints(readingLog)
+	resp.NewAchievements = h.awardAchievements(readingLog.ChildID)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ---------------------------
+// Status history of a reading log
+func (h *ReadingLogHandler) GetReadingLogTransitions(c *gin.Context) {
+	readingLog, ok := h.loadVisibleReadingLog(c)
+	if !ok {
+		return
+	}
+
+	transitions, err := repository.GetReadingLogTransitions(h.DB, readingLog.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading log history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// ---------------------------
+// Request/Response structs
+type ReadingSessionRequest struct {
+	StartPage *int   `json:"start_page,omitempty"`
+	EndPage   *int   `json:"end_page,omitempty"`
+	Minutes   *int   `json:"minutes,omitempty"`
+	ReadAt    string `json:"read_at,omitempty"` // RFC 3339 timestamp or YYYY-MM-DD, defaults to now
+}
+
+type ReadingSessionsResponse struct {
+	Sessions []models.ReadingSession    `json:"sessions"`
+	Progress repository.ReadingProgress `json:"progress"`
+}
+
+// parseReadAt accepts a full timestamp or a plain date, read as midnight in loc
+func parseReadAt(value string, loc *time.Location) (time.Time, bool) {
+	if value == "" {
+		return time.Now(), true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := parseFamilyDate(value, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// sessionClockSkew is how far ahead of the server's clock a session's read_at may be, for devices running fast
+const sessionClockSkew = 5 * time.Minute
+
+// validateSession checks page numbers against each other and the book length,
+// and keeps minutes, pages and the date within what one real sitting could be
+func validateSession(session *models.ReadingSession, log *models.ReadingLog) string {
+	if session.Minutes < 0 {
+		return "Minutes cannot be negative"
+	}
+	if session.Minutes > models.MaxSessionMinutes {
+		return fmt.Sprintf("A session can't be longer than %d minutes", models.MaxSessionMinutes)
+	}
+	if session.ReadAt.After(time.Now().Add(sessionClockSkew)) {
+		return "Sessions can't be in the future"
+	}
+	if session.StartPage != nil && *session.StartPage < 0 || session.EndPage != nil && *session.EndPage < 0 {
+		return "Pages cannot be negative"
+	}
+	if session.StartPage != nil && session.EndPage != nil && *session.EndPage < *session.StartPage {
+		return "End page must not be before start page"
+	}
+	if session.EndPage != nil && log.TotalPages != nil && *session.EndPage > *log.TotalPages {
+		return "End page is past the end of the book"
+	}
+	if models.SessionPagesRead(session) > models.MaxSessionPages {
+		return fmt.Sprintf("A session can't cover more than %d pages", models.MaxSessionPages)
+	}
+	if session.Minutes == 0 && session.EndPage == nil {
+		return "Minutes or end page is required"
+	}
+	return ""
+}
+
+// ---------------------------
+// List the sessions of a reading log with its progress
+func (h *ReadingLogHandler) GetReadingSessions(c *gin.Context) {
+	readingLog, ok := h.loadVisibleReadingLog(c)
+	if !ok {
+		return
+	}
+
+	var sessions []models.ReadingSession
+	if err := h.DB.Where("reading_log_id = ?", readingLog.ID).Order("read_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading sessions"})
+		return
+	}
+
+	progress, err := repository.GetReadingProgress(h.DB, []models.ReadingLog{*readingLog})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReadingSessionsResponse{Sessions: sessions, Progress: progress[readingLog.ID]})
+}
+
+// ---------------------------
+// Record a reading session against a log
+func (h *ReadingLogHandler) CreateReadingSession(c *gin.Context) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return
+	}
+
+	var req ReadingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	loc, ok := familyLocation(c, h.DB, readingLog.ChildID)
+	if !ok {
+		return
+	}
+
+	readAt, ok := parseReadAt(req.ReadAt, loc)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid read_at. Use RFC 3339 or YYYY-MM-DD"})
+		return
+	}
+
+	session := models.ReadingSession{
+		ReadingLogID: readingLog.ID,
+		ChildID:      readingLog.ChildID,
+		StartPage:    req.StartPage,
+		EndPage:      req.EndPage,
+		ReadAt:       readAt,
+	}
+	if req.Minutes != nil {
+		session.Minutes = *req.Minutes
+	}
+
+	if msg := validateSession(&session, readingLog); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if !h.holdChildChange(c, readingLog) {
+		return
+	}
+	if err := h.DB.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reading session"})
+		return
+	}
+
+	h.awardSessionPoints(&session)
+	h.awardAchievements(readingLog.ChildID)
+	c.JSON(http.StatusCreated, session)
+}
+
+// ---------------------------
+// Edit a reading session
+func (h *ReadingLogHandler) UpdateReadingSession(c *gin.Context) {
+	readingLog, session, ok := h.loadOwnedReadingSession(c)
+	if !ok {
+		return
+	}
+
+	var req ReadingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if req.StartPage != nil {
+		session.StartPage = req.StartPage
+	}
+	if req.EndPage != nil {
+		session.EndPage = req.EndPage
+	}
+	if req.Minutes != nil {
+		session.Minutes = *req.Minutes
+	}
+	if req.ReadAt != "" {
+		loc, ok := familyLocation(c, h.DB, readingLog.ChildID)
+		if !ok {
+			return
+		}
+		readAt, ok := parseReadAt(req.ReadAt, loc)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid read_at. Use RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		session.ReadAt = readAt
+	}
+
+	if msg := validateSession(session, readingLog); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if !h.holdChildChange(c, readingLog) {
+		return
+	}
+	if err := h.DB.Save(session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading session"})
+		return
+	}
+
+	h.repriceSessionPoints(session)
+	h.awardAchievements(readingLog.ChildID)
+	c.JSON(http.StatusOK, session)
+}
+
+// ---------------------------
+// Delete a reading session
+func (h *ReadingLogHandler) DeleteReadingSession(c *gin.Context) {
+	_, session, ok := h.loadOwnedReadingSession(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.Delete(session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reading session"})
+		return
+	}
+	// Otherwise logging and deleting the same session over and over would keep earning
+	h.repriceSessionPoints(session)
+
+	c.Status(http.StatusNoContent)
+}
+
+// loadOwnedReadingSession resolves :id and :sessionId, checking the session belongs to the log
+func (h *ReadingLogHandler) loadOwnedReadingSession(c *gin.Context) (*models.ReadingLog, *models.ReadingSession, bool) {
+	readingLog, ok := h.loadOwnedReadingLog(c, false)
+	if !ok {
+		return nil, nil, false
+	}
+
+	sessionID, ok := parseUintParam(c, "sessionId")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return nil, nil, false
+	}
+
+	var session models.ReadingSession
+	if err := h.DB.Where("id = ? AND reading_log_id = ?", sessionID, readingLog.ID).First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading session not found"})
+		return nil, nil, false
+	}
+
+	return readingLog, &session, true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"page-hoppers-backend/internal/repository"
+)
+
+// ---------------------------
+// Get a child's reading streaks (the child themselves or their parent)
+func (h *ReadingLogHandler) GetReadingStreaks(c *gin.Context) {
+	_, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+		return
+	}
+
+	if !canViewChild(c, h.DB, childID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
+	}
+
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
+	}
+
+	streaks, ok := h.childStreaks(c, childID, time.Now().In(loc))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, streaks)
+}
+
+// childStreaks computes streaks with the family's grace days, writing a 500 response on failure
+func (h *ReadingLogHandler) childStreaks(c *gin.Context, childID uint, now time.Time) (*repository.ReadingStreaks, bool) {
+	graceDays, err := repository.FamilyStreakGraceDays(h.DB, childID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load family settings"})
+		return nil, false
+	}
+
+	streaks, err := repository.GetReadingStreaks(h.DB, childID, now, graceDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute reading streaks"})
+		return nil, false
+	}
+	return streaks, true
+}
@@ -2,123 +2,191 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Add this method to your ReadingLogHandler
+// ---------------------------
+// Reading summary for one child (the child themselves or one of their guardians)
 func (h *ReadingLogHandler) GetReadingSummary(c *gin.Context) {
-	// Get the child ID from the URL
-	childIDStr := c.Param("id")
-	childIDUint, err := strconv.ParseUint(childIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+	_, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
-	childID := uint(childIDUint)
 
-	// Make sure the child exists
-	var child models.User
-	if err := h.DB.Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+	childID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
 		return
 	}
 
-	// Fetch reading logs
-	var logs []models.ReadingLog
-	if err := h.DB.Where("child_id = ?", childID).Find(&logs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+	// A parent sees the children they are a guardian of and a child only themselves;
+	// anyone else's child is reported as missing
+	if !childAccess(c, h.DB, childID, false) {
 		return
 	}
 
-	// Current Book
-	var currentBook *models.ReadingLog
-	for _, log := range logs {
-		if log.Status == "started" {
-			if currentBook == nil || log.Date.After(currentBook.Date) {
-				currentBook = &log
-			}
-		}
+	var child models.User
+	if err := h.DB.Where("id = ? AND role = ?", childID, policy.RoleChild).First(&child).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+		return
 	}
 
-	var currentBookData gin.H
-	if currentBook != nil {
-		currentBookData = gin.H{
-			"title":    currentBook.Title,
-			"author":   currentBook.Author,
-			"cover_id": currentBook.CoverID,
-		}
-	} else {
-		currentBookData = nil
+	// Periods and "this month" are read in the family's time zone
+	loc, ok := familyLocation(c, h.DB, childID)
+	if !ok {
+		return
 	}
+	now := time.Now().In(loc)
 
-	// Last Completed Book
-	var lastCompletedBook *models.ReadingLog
-	for _, log := range logs {
-		if log.Status == "completed" {
-			if lastCompletedBook == nil || log.Date.After(lastCompletedBook.Date) {
-				lastCompletedBook = &log
-			}
-		}
+	readingRange, periods, ok := h.parseSummaryRange(c, now)
+	if !ok {
+		return
 	}
 
-	var lastCompletedBookData gin.H
-	if lastCompletedBook != nil {
-		lastCompletedBookData = gin.H{
-			"title":    lastCompletedBook.Title,
-			"author":   lastCompletedBook.Author,
-			"cover_id": lastCompletedBook.CoverID,
-		}
-	} else {
-		lastCompletedBookData = nil
+	// Counts come from each log's current status (one log per book)
+	summary, err := repository.GetReadingSummary(h.DB, childID, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+		return
 	}
 
-	now := time.Now()
-	currentYear := now.Year()
-	currentMonth := now.Month()
-
-	totalBooksReadThisMonth := 0
-	for _, log := range logs {
-		if log.Status == "completed" &&
-			log.Date.Year() == currentYear &&
-			log.Date.Month() == currentMonth {
-			totalBooksReadThisMonth++
-		}
+	streaks, ok := h.childStreaks(c, childID, now)
+	if !ok {
+		return
 	}
 
-	totalBooksReadThisYear := 0
-	for _, log := range logs {
-		if log.Status == "completed" &&
-			log.Date.Year() == currentYear {
-			totalBooksReadThisYear++
-		}
-
+	goals, ok := h.goalProgress(c, childID, now)
+	if !ok {
+		return
 	}
 
-	// Compute summary
-	started := 0
-	completed := 0
-	for _, log := range logs {
-		if log.Status == "started" {
-			started++
-		} else if log.Status == "completed" {
-			completed++
-		}
+	periodTotals, err := repository.GetPeriodTotals(h.DB, childID, periods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading logs"})
+		return
+	}
+	for _, totals := range periodTotals {
+		readingRange.BooksCompleted += totals.BooksCompleted
+		readingRange.PagesRead += totals.PagesRead
+		readingRange.MinutesRead += totals.MinutesRead
 	}
 
 	// Return JSON summary
 	c.JSON(http.StatusOK, gin.H{
 		"child_id":                childID,
 		"name":                    child.Name,
-		"currentBook":             currentBookData,
-		"lastCompletedBook":       lastCompletedBookData,
-		"totalBooksReadThisMonth": totalBooksReadThisMonth,
-		"totalBooksReadThisYear":  totalBooksReadThisYear,
-		"totalUncompletedBooks":   started,
-		"totalCompletedBooks":     completed,
+		"currentBook":             summaryBookData(summary.CurrentBook),
+		"lastCompletedBook":       summaryBookData(summary.LastCompletedBook),
+		"totalBooksReadThisMonth": summary.TotalBooksReadThisMonth,
+		"totalBooksReadThisYear":  summary.TotalBooksReadThisYear,
+		"totalUncompletedBooks":   summary.TotalUncompletedBooks,
+		"totalCompletedBooks":     summary.TotalCompletedBooks,
+		"totalsByStatus":          summary.TotalsByStatus,
+		"pendingApproval":         summary.PendingApproval,
+		"streaks":                 streaks,
+		"goals":                   goals,
+		"range":                   readingRange,
+		"periods":                 periodTotals,
 	})
 }
+
+// SummaryRange describes the range a summary's "periods" cover, with totals over the whole range
+type SummaryRange struct {
+	From           string `json:"from"` // inclusive, YYYY-MM-DD
+	To             string `json:"to"`   // inclusive, YYYY-MM-DD
+	Period         string `json:"period,omitempty"`
+	GroupBy        string `json:"group_by"`
+	BooksCompleted int    `json:"books_completed"`
+	PagesRead      int    `json:"pages_read"`
+	MinutesRead    int    `json:"minutes_read"`
+}
+
+// parseSummaryRange reads ?from=&to= (inclusive dates) or ?period= (e.g. last_month, last_school_year),
+// and ?group_by=day|week|month|term. Without either it covers this year by month.
+func (h *ReadingLogHandler) parseSummaryRange(c *gin.Context, now time.Time) (*SummaryRange, []repository.Period, bool) {
+	cal := h.schoolCalendar()
+
+	fromStr, toStr, periodName := c.Query("from"), c.Query("to"), c.Query("period")
+	var period repository.Period
+	switch {
+	case periodName != "" && (fromStr != "" || toStr != ""):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use either period or from/to, not both"})
+		return nil, nil, false
+	case fromStr != "" || toStr != "":
+		if fromStr == "" || toStr == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Both from and to are required"})
+			return nil, nil, false
+		}
+		from, err := time.ParseInLocation("2006-01-02", fromStr, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' date. Use YYYY-MM-DD"})
+			return nil, nil, false
+		}
+		to, err := time.ParseInLocation("2006-01-02", toStr, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' date. Use YYYY-MM-DD"})
+			return nil, nil, false
+		}
+		if to.Before(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+			return nil, nil, false
+		}
+		period = repository.Period{Start: from, End: to.AddDate(0, 0, 1)}
+	default:
+		if periodName == "" {
+			periodName = "this_year"
+		}
+		var err error
+		period, err = repository.NamedPeriod(periodName, now, cal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown period. Use this_week, last_week, this_month, last_month, this_term, last_term, this_year, last_year, this_school_year or last_school_year"})
+			return nil, nil, false
+		}
+	}
+
+	groupBy := c.DefaultQuery("group_by", repository.GroupByMonth)
+	periods, err := repository.SplitPeriods(period.Start, period.End, groupBy, cal)
+	if err == repository.ErrTooManyPeriods {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Range is too long for that grouping"})
+		return nil, nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be 'day', 'week', 'month' or 'term'"})
+		return nil, nil, false
+	}
+
+	return &SummaryRange{
+		From:    period.Start.Format("2006-01-02"),
+		To:      period.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		Period:  periodName,
+		GroupBy: groupBy,
+	}, periods, true
+}
+
+// summaryBookData trims a log down to what the summary card shows
+func summaryBookData(log *models.ReadingLog) gin.H {
+	if log == nil {
+		return nil
+	}
+	return gin.H{
+		"book_id":  log.BookID,
+		"title":    log.Title,
+		"author":   log.Author,
+		"cover_id": log.CoverID,
+	}
+}
+
+// schoolCalendar returns the configured terms, or the default ones
+func (h *ReadingLogHandler) schoolCalendar() repository.SchoolCalendar {
+	if h.SchoolCalendar != nil {
+		return *h.SchoolCalendar
+	}
+	return repository.DefaultSchoolCalendar
+}
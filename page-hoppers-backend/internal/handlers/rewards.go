@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+)
+
+type RewardsHandler struct {
+	DB *gorm.DB
+}
+
+func NewRewardsHandler(db *gorm.DB) *RewardsHandler {
+	return &RewardsHandler{
+		DB: db,
+	}
+}
+
+// RewardRequest defines a reward, e.g. {"name": "30 min screen time", "cost": 50}.
+// Name and cost are required to create one; updates change only the fields sent.
+type RewardRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Cost        *int    `json:"cost,omitempty"`
+	Disabled    *bool   `json:"disabled,omitempty"`
+}
+
+// apply copies the request onto reward and returns an error message if the result is invalid
+func (req RewardRequest) apply(reward *models.Reward) string {
+	if req.Name != nil {
+		reward.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		reward.Description = *req.Description
+	}
+	if req.Cost != nil {
+		reward.Cost = *req.Cost
+	}
+	if req.Disabled != nil {
+		reward.Disabled = *req.Disabled
+	}
+
+	if reward.Name == "" {
+		return "Name is required"
+	}
+	if reward.Cost <= 0 {
+		return "Cost must be positive"
+	}
+	return ""
+}
+
+// DecideRedemptionRequest carries the parent's optional comment on an approval or rejection
+type DecideRedemptionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ---------------------------
+// List the family's rewards. Children see the ones they can redeem; adults see every family they guard,
+// disabled rewards included.
+func (h *RewardsHandler) GetRewards(c *gin.Context) {
+	parentIDs, ok := h.familyParentIDs(c, false)
+	if !ok {
+		return
+	}
+	_, role, _ := currentUser(c)
+
+	query := h.DB.Where("parent_id IN ?", parentIDs)
+	if role != policy.RoleParent {
+		query = query.Where("disabled = ?", false)
+	}
+
+	var rewards []models.Reward
+	if err := query.Order("cost ASC, id ASC").Find(&rewards).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rewards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rewards)
+}
+
+// ---------------------------
+// Offer a new reward (parent or co-parent). Adults in more than one family choose it with ?child_id=.
+func (h *RewardsHandler) CreateReward(c *gin.Context) {
+	parentID, ok := h.familyParentID(c, true)
+	if !ok {
+		return
+	}
+
+	var req RewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	reward := models.Reward{ParentID: parentID}
+	if msg := req.apply(&reward); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := h.DB.Create(&reward).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reward"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reward)
+}
+
+// ---------------------------
+// Change a reward (parent). Requests already made keep the name and cost they were made at.
+func (h *RewardsHandler) UpdateReward(c *gin.Context) {
+	reward, ok := h.loadReward(c, true)
+	if !ok {
+		return
+	}
+
+	var req RewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if msg := req.apply(reward); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := h.DB.Save(reward).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reward"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reward)
+}
+
+// ---------------------------
+// Remove a reward (parent)
+func (h *RewardsHandler) DeleteReward(c *gin.Context) {
+	reward, ok := h.loadReward(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.Delete(reward).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reward"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ---------------------------
+// Ask to spend points on a reward (child). The points are held until a parent decides.
+func (h *RewardsHandler) RedeemReward(c *gin.Context) {
+	childID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if role != policy.RoleChild {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only children can redeem rewards"})
+		return
+	}
+
+	reward, ok := h.loadReward(c, false)
+	if !ok {
+		return
+	}
+	if reward.Disabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "This reward isn't available"})
+		return
+	}
+
+	redemption, err := repository.RequestRedemption(h.DB, childID, reward)
+	if err != nil {
+		if err == repository.ErrInsufficientPoints {
+			c.JSON(http.StatusConflict, gin.H{"error": "Not enough points for this reward"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request reward"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, redemption)
+}
+
+// ---------------------------
+// List reward requests, newest first. Parents see their children's (optionally ?child_id=);
+// children see their own. Filter with ?status=requested, approved or rejected.
+func (h *RewardsHandler) GetRedemptions(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := h.DB.Model(&models.RewardRedemption{})
+	switch role {
+	case policy.RoleParent:
+		query = query.Where("child_id IN (?)", repository.GuardedChildIDs(h.DB, userID))
+		if value := c.Query("child_id"); value != "" {
+			query = query.Where("child_id = ?", value)
+		}
+	case policy.RoleChild:
+		query = query.Where("child_id = ?", userID)
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var redemptions []models.RewardRedemption
+	if err := query.Order("created_at DESC, id DESC").Find(&redemptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reward requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redemptions)
+}
+
+// ---------------------------
+// Approve a reward request, spending the child's points (parent)
+func (h *RewardsHandler) ApproveRedemption(c *gin.Context) {
+	h.decideRedemption(c, true)
+}
+
+// ---------------------------
+// Reject a reward request, releasing the held points (parent)
+func (h *RewardsHandler) RejectRedemption(c *gin.Context) {
+	h.decideRedemption(c, false)
+}
+
+func (h *RewardsHandler) decideRedemption(c *gin.Context, approve bool) {
+	parentID, ok := h.requireParent(c)
+	if !ok {
+		return
+	}
+
+	redemptionID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var redemption models.RewardRedemption
+	if err := h.DB.Where("id = ? AND child_id IN (?)", redemptionID, repository.GuardedChildIDs(h.DB, parentID)).
+		First(&redemption).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reward request not found"})
+		return
+	}
+	if !canManageChild(c, h.DB, redemption.ChildID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errViewOnly})
+		return
+	}
+
+	// The comment is optional, so an empty body is fine
+	var req DecideRedemptionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if err := repository.DecideRedemption(h.DB, &redemption, approve, parentID, req.Comment, time.Now()); err != nil {
+		switch err {
+		case repository.ErrRedemptionDecided:
+			c.JSON(http.StatusConflict, gin.H{"error": "This request has already been decided"})
+		case repository.ErrInsufficientPoints:
+			c.JSON(http.StatusConflict, gin.H{"error": "The child no longer has enough points"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reward request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, redemption)
+}
+
+// requireParent returns the calling parent's ID, or writes a 403 for anyone else
+func (h *RewardsHandler) requireParent(c *gin.Context) (uint, bool) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+	if role != policy.RoleParent {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can do this"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// familyParentIDs returns the parents whose rewards and rules the caller may see, or with write set change:
+// a child's own parent, or every family an adult guards (only as owner or co-parent for write)
+func (h *RewardsHandler) familyParentIDs(c *gin.Context, write bool) ([]uint, bool) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	if role != policy.RoleParent {
+		if write {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only parents can do this"})
+			return nil, false
+		}
+		parentID, err := repository.FamilyParentID(h.DB, userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return nil, false
+		}
+		return []uint{parentID}, true
+	}
+
+	var roles []string
+	if write {
+		roles = repository.ManagingGuardianRoles
+	}
+	parentIDs, err := repository.GuardedFamilyIDs(h.DB, userID, roles...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find your family"})
+		return nil, false
+	}
+	return parentIDs, true
+}
+
+// familyParentID picks the one family a request is about: the family of the child named by ?child_id=,
+// otherwise the caller's own family, or the only family they guard. It writes a 400 when an adult
+// guards several families and didn't say which.
+func (h *RewardsHandler) familyParentID(c *gin.Context, write bool) (uint, bool) {
+	parentIDs, ok := h.familyParentIDs(c, write)
+	if !ok {
+		return 0, false
+	}
+
+	if value := c.Query("child_id"); value != "" {
+		childID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid child ID"})
+			return 0, false
+		}
+		if !childAccess(c, h.DB, uint(childID), write) {
+			return 0, false
+		}
+		parentID, err := repository.FamilyParentID(h.DB, uint(childID))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Child not found"})
+			return 0, false
+		}
+		return parentID, true
+	}
+
+	userID, _, _ := currentUser(c)
+	if slices.Contains(parentIDs, userID) {
+		return userID, true
+	}
+	if len(parentIDs) > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You help run more than one family; pass child_id to choose one"})
+		return 0, false
+	}
+	return parentIDs[0], true
+}
+
+// loadReward fetches the reward named by :id from one of the caller's families.
+// With write set only the family's owner or a co-parent may have it.
+func (h *RewardsHandler) loadReward(c *gin.Context, write bool) (*models.Reward, bool) {
+	parentIDs, ok := h.familyParentIDs(c, write)
+	if !ok {
+		return nil, false
+	}
+
+	rewardID, ok := parseUintParam(c, "id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reward ID"})
+		return nil, false
+	}
+
+	var reward models.Reward
+	if err := h.DB.Where("id = ? AND parent_id IN ?", rewardID, parentIDs).First(&reward).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reward not found"})
+		return nil, false
+	}
+	return &reward, true
+}
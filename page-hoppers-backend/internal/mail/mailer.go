@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultFrom is the sender for local mailers when none is configured
+const DefaultFrom = "Page Hoppers <no-reply@localhost>"
+
+// Message is one plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email, e.g. verification and password reset links
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config holds the settings the built-in mailers need
+type Config struct {
+	From         string // sender address, e.g. "Page Hoppers <no-reply@example.com>"
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	Dir          string // where the file mailer writes messages
+}
+
+// NewMailer builds a mailer by backend name: "log" (the default) prints messages, "file" writes them
+// to cfg.Dir, and "smtp" sends them through cfg.SMTPHost
+func NewMailer(backend string, cfg Config) (Mailer, error) {
+	switch strings.TrimSpace(strings.ToLower(backend)) {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		return NewFileMailer(cfg.Dir, cfg.From), nil
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			return nil, fmt.Errorf("smtp mailer needs a host and a from address")
+		}
+		return NewSMTPMailer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown mail backend %q", backend)
+	}
+}
+
+// CheckBackend refuses the log backend, which prints every emailed link and its token to the server
+// log, in any environment but development. An unset environment counts as development.
+func CheckBackend(backend, environment string) error {
+	switch strings.TrimSpace(strings.ToLower(environment)) {
+	case "", "development":
+		return nil
+	}
+	switch strings.TrimSpace(strings.ToLower(backend)) {
+	case "", "log":
+		return fmt.Errorf("the log mailer prints sign-in links; choose the file or smtp backend in %s", environment)
+	}
+	return nil
+}
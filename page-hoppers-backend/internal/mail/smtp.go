@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends email through an SMTP server, authenticating when a username is set.
+// net/smtp upgrades to TLS when the server offers STARTTLS.
+type SMTPMailer struct {
+	addr string
+	host string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(cfg Config) *SMTPMailer {
+	port := cfg.SMTPPort
+	if port == "" {
+		port = "587"
+	}
+	m := &SMTPMailer{addr: net.JoinHostPort(cfg.SMTPHost, port), host: cfg.SMTPHost, from: cfg.From}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	// net/smtp takes no context, so honour cancellation only before starting
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	envelopeFrom := m.from
+	if start, end := strings.Index(m.from, "<"), strings.Index(m.from, ">"); start >= 0 && end > start {
+		envelopeFrom = m.from[start+1 : end]
+	}
+	if err := smtp.SendMail(m.addr, m.auth, envelopeFrom, []string{msg.To}, format(m.from, msg)); err != nil {
+		return fmt.Errorf("sending mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// format renders msg as an RFC 5322 message
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,29 @@
+package models
+
+// What an achievement rule measures. Each is counted over the rule's window, or all time.
+const (
+	AchievementMetricBooksCompleted  = "books_completed"
+	AchievementMetricDistinctAuthors = "distinct_authors" // authors of completed books
+	AchievementMetricStreakDays      = "streak_days"      // longest reading streak, with the family's grace days
+	AchievementMetricSeriesCompleted = "series_completed" // series with every catalog book completed
+	AchievementMetricMinutesRead     = "minutes_read"
+	AchievementMetricPagesRead       = "pages_read"
+)
+
+func IsValidAchievementMetric(metric string) bool {
+	switch metric {
+	case AchievementMetricBooksCompleted, AchievementMetricDistinctAuthors, AchievementMetricStreakDays,
+		AchievementMetricSeriesCompleted, AchievementMetricMinutesRead, AchievementMetricPagesRead:
+		return true
+	}
+	return false
+}
+
+// DefaultAchievementRules are seeded on migration. Changing one here doesn't touch a rule already in the database.
+var DefaultAchievementRules = []AchievementRule{
+	{Key: "first_book", Name: "First Book", Description: "Finished your first book", Icon: "📖", Metric: AchievementMetricBooksCompleted, Threshold: 1},
+	{Key: "ten_books", Name: "Bookworm", Description: "Finished 10 books", Icon: "🐛", Metric: AchievementMetricBooksCompleted, Threshold: 10},
+	{Key: "five_authors", Name: "Explorer", Description: "Read books by 5 different authors", Icon: "🧭", Metric: AchievementMetricDistinctAuthors, Threshold: 5},
+	{Key: "seven_day_streak", Name: "On a Roll", Description: "Read 7 days in a row", Icon: "🔥", Metric: AchievementMetricStreakDays, Threshold: 7},
+	{Key: "finished_series", Name: "Series Finisher", Description: "Finished every book in a series", Icon: "📚", Metric: AchievementMetricSeriesCompleted, Threshold: 1},
+}
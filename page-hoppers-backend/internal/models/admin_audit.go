@@ -0,0 +1,17 @@
+package models
+
+// What an admin did, as recorded in AdminAuditEntry.Action
+const (
+	AdminActionSearchUsers      = "search_users"
+	AdminActionViewFamily       = "view_family"
+	AdminActionResetCredentials = "reset_credentials"
+	AdminActionDeactivate       = "deactivate"
+	AdminActionReactivate       = "reactivate"
+	AdminActionImpersonate      = "impersonate"
+	AdminActionRestoreChild     = "restore_child"
+	AdminActionMergeAccounts    = "merge_accounts"
+	AdminActionRotateJoinCode   = "rotate_join_code"
+	AdminActionRename           = "rename_organization"
+	AdminActionCreateAdmin      = "create_admin"
+	AdminActionViewAsUser       = "view_as_user" // a request made with an impersonation token
+)
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Purposes of an EmailToken, and how long each link works
+const (
+	EmailTokenVerifyEmail   = "verify_email"
+	EmailTokenResetPassword = "reset_password"
+
+	VerifyEmailTokenTTL   = 48 * time.Hour
+	ResetPasswordTokenTTL = time.Hour
+)
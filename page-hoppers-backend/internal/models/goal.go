@@ -0,0 +1,30 @@
+package models
+
+// What a goal counts
+const (
+	GoalMetricBooks   = "books"   // books completed
+	GoalMetricMinutes = "minutes" // minutes from reading sessions
+	GoalMetricPages   = "pages"   // pages from reading sessions
+)
+
+// The period a goal resets over, in the family's time zone
+const (
+	GoalPeriodWeek  = "week"
+	GoalPeriodMonth = "month"
+	GoalPeriodTerm  = "term"
+)
+
+// Goal progress statuses
+const (
+	GoalStatusAchieved = "achieved" // target already reached this period
+	GoalStatusOnTrack  = "on_track" // at or ahead of the pace needed to reach it
+	GoalStatusBehind   = "behind"
+)
+
+func IsValidGoalMetric(metric string) bool {
+	return metric == GoalMetricBooks || metric == GoalMetricMinutes || metric == GoalMetricPages
+}
+
+func IsValidGoalPeriod(period string) bool {
+	return period == GoalPeriodWeek || period == GoalPeriodMonth || period == GoalPeriodTerm
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Guardian roles. The owner is the parent who added the child (User.ParentID); other adults are
+// Guardianship rows with one of the invited roles.
+const (
+	GuardianOwner    = "owner"     // manages the child and decides who else can
+	GuardianCoParent = "co_parent" // manages the child like the owner
+	GuardianViewer   = "viewer"    // sees the child's reading but changes nothing, e.g. a grandparent
+)
+
+// GuardianInviteTTL is how long an emailed guardian invite can be accepted
+const GuardianInviteTTL = 7 * 24 * time.Hour
+
+// IsInvitableGuardianRole reports whether role can be given by invite; there is only one owner
+func IsInvitableGuardianRole(role string) bool {
+	return role == GuardianCoParent || role == GuardianViewer
+}
@@ -1,35 +1,321 @@
 package models
 
 import (
-	"time"
 	"gorm.io/gorm"
+	"time"
 )
 
-// User model - represents both parent and child users
+// Organization model - a tenant, such as a school, sharing one deployment.
+// Every user belongs to exactly one; only the book catalog and achievement rules are shared between them.
+type Organization struct {
+	gorm.Model
+	Name string `json:"name"`
+	Slug string `json:"slug" gorm:"uniqueIndex"` // chosen at registration, e.g. "hillside-primary"
+
+	// Codes the admins hand out to register into the organization, one per role so a family's code
+	// can't make a teacher. Empty means nobody can register with that role until an admin issues one.
+	ParentJoinCode  string `json:"-"`
+	TeacherJoinCode string `json:"-"`
+}
+
+// User model - represents parent, child, teacher and admin users
 type User struct {
 	gorm.Model
-	Name         string    `json:"name"` // Child's real name
-	Age          int       `json:"age"`
-	Password     string    `json:"-"` // Password hash, not exposed in JSON
-	Email        string    `json:"email,omitempty" gorm:"uniqueIndex;default:null"`
-	Role         string    `json:"role"` // "parent" or "child"
-	ParentID     *uint     `json:"parent_id,omitempty"`
-	Parent       *User     `json:"-" gorm:"foreignKey:ParentID"`
-	Children     []User    `json:"children,omitempty" gorm:"foreignKey:ParentID"`
-	PIN          string    `json:"-"` // Optional PIN for child login
-	LastLoginAt  time.Time `json:"last_login_at"`
-	ReadingLogs  []ReadingLog `json:"reading_logs,omitempty" gorm:"foreignKey:ChildID"`
+	Name        string       `json:"name"` // Child's real name
+	Age         int          `json:"age"`
+	Password    string       `json:"-"` // Password hash, not exposed in JSON
+	Email       string       `json:"email,omitempty" gorm:"uniqueIndex;default:null"`
+	Role        string       `json:"role"` // "parent", "child", "teacher" or "admin"
+	ParentID    *uint        `json:"parent_id,omitempty"`
+	Parent      *User        `json:"-" gorm:"foreignKey:ParentID"`
+	Children    []User       `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	PIN         string       `json:"-"` // Optional PIN for child login
+	LastLoginAt time.Time    `json:"last_login_at"`
+	ReadingLogs []ReadingLog `json:"reading_logs,omitempty" gorm:"foreignKey:ChildID"`
+
+	// The organization the user belongs to; children are always in their parent's
+	OrganizationID uint `json:"organization_id" gorm:"index"`
+
+	// Set once a parent follows the link in their verification email
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// Set while an admin has switched the account off; it can't sign in until reactivated
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+
+	// Family settings, set on parents; children use their parent's
+	TimeZone        string `json:"time_zone,omitempty"`                          // IANA name such as "America/Chicago"
+	StreakGraceDays int    `json:"streak_grace_days,omitempty" gorm:"default:0"` // missed days in a row a reading streak survives
+
+	// Child settings, set by their parent
+	RequiresLogApproval bool   `json:"requires_log_approval,omitempty" gorm:"default:false"` // the child's logs count only once a parent approves them
+	Avatar              string `json:"avatar,omitempty"`                                     // shown on the family login screen, e.g. an emoji or image name
+	LoginKey            string `json:"-" gorm:"uniqueIndex;default:null"`                    // random ID paired devices use instead of the database ID
+}
+
+// AdminAuditEntry model - one thing an admin did through the support API, reads included.
+// Entries are only ever added, so the organization can see who looked at or changed which account.
+type AdminAuditEntry struct {
+	gorm.Model
+	OrganizationID uint   `json:"organization_id" gorm:"index"`
+	AdminID        uint   `json:"admin_id" gorm:"index"`
+	Action         string `json:"action"` // see the AdminAction constants
+	TargetUserID   *uint  `json:"target_user_id,omitempty" gorm:"index"`
+	Detail         string `json:"detail,omitempty"` // e.g. the search query or the account merged into
+}
+
+// Guardianship model - gives another adult access to a child besides the owning parent
+type Guardianship struct {
+	gorm.Model
+	ChildID    uint   `json:"child_id" gorm:"uniqueIndex:idx_guardianship"`
+	GuardianID uint   `json:"guardian_id" gorm:"uniqueIndex:idx_guardianship;index"`
+	Guardian   *User  `json:"-" gorm:"foreignKey:GuardianID"`
+	Role       string `json:"role"` // GuardianCoParent or GuardianViewer
+}
+
+// GuardianInvite model - an emailed invitation to become a child's guardian.
+// Only the invited address can accept it, by the token in the link; only its hash is stored.
+type GuardianInvite struct {
+	gorm.Model
+	ChildID      uint       `json:"child_id" gorm:"index"`
+	InvitedByID  uint       `json:"invited_by_id"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	TokenHash    string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+	AcceptedByID *uint      `json:"accepted_by_id,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Classroom model - a teacher's class. Parents link a child to it with the join code the teacher hands out.
+type Classroom struct {
+	gorm.Model
+	OrganizationID uint   `json:"organization_id" gorm:"index"` // the teacher's; only children in it can join
+	TeacherID      uint   `json:"teacher_id" gorm:"index"`
+	Name           string `json:"name"`
+	JoinCode       string `json:"join_code" gorm:"uniqueIndex"`
+}
+
+// ClassroomMember model - a child in a classroom, added with a guardian's consent.
+// Deleting the row withdraws that consent and the teacher stops seeing the child.
+type ClassroomMember struct {
+	gorm.Model
+	ClassroomID   uint      `json:"classroom_id" gorm:"uniqueIndex:idx_classroom_member"`
+	ChildID       uint      `json:"child_id" gorm:"uniqueIndex:idx_classroom_member;index"`
+	ConsentedByID uint      `json:"consented_by_id"`
+	ConsentedAt   time.Time `json:"consented_at"`
+}
+
+// FamilyDevice model - a shared device, such as a family tablet, a parent has paired for child login.
+// The device keeps the pairing token; only its hash is stored.
+type FamilyDevice struct {
+	gorm.Model
+	ParentID   uint       `json:"parent_id" gorm:"index"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// AuthSession model - one signed-in device. The refresh token is stored hashed and replaced on every refresh;
+// revoking the session also stops the device's access token working.
+type AuthSession struct {
+	gorm.Model
+	UserID            uint       `json:"user_id" gorm:"index"`
+	User              *User      `json:"-" gorm:"foreignKey:UserID"`
+	RefreshTokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	PreviousTokenHash string     `json:"-" gorm:"index"` // the token this one replaced; seeing it again means it was stolen
+	UserAgent         string     `json:"user_agent,omitempty"`
+	IPAddress         string     `json:"ip_address,omitempty"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	ExpiresAt         time.Time  `json:"expires_at"` // when the refresh token stops working
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+}
+
+// EmailToken model - a one-time link emailed to a user, to verify their address or reset their password.
+// The link carries a signed token naming this row; the row makes it single-use.
+type EmailToken struct {
+	gorm.Model
+	UserID    uint       `json:"user_id" gorm:"index"`
+	Purpose   string     `json:"purpose"`              // see the EmailToken constants
+	Email     string     `json:"email"`                // the address the link was sent to
+	TokenHash string     `json:"-" gorm:"uniqueIndex"` // SHA-256 of the token's ID
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// LoginThrottle model - failed logins counted against one key, such as a child or an IP address.
+// Repeated failures slow further attempts down and eventually lock the key out for a while.
+type LoginThrottle struct {
+	gorm.Model
+	Key           string     `json:"key" gorm:"uniqueIndex"` // e.g. "child:12" or "ip:203.0.113.7"
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// Notification model - a message for a user, shown in the app until they mark it read
+type Notification struct {
+	gorm.Model
+	UserID  uint       `json:"user_id" gorm:"index"`
+	Kind    string     `json:"kind"` // see the Notification constants
+	Message string     `json:"message"`
+	ReadAt  *time.Time `json:"read_at,omitempty"`
 }
 
 // ReadingLog model - represents a book reading activity by a child
+// Title, Author, OpenLibraryKey, ISBN and CoverID mirror the linked Book so existing clients keep working.
 type ReadingLog struct {
 	gorm.Model
-	Title       string    `json:"title"`
-	Author      string    `json:"author,omitempty"`
-	Status      string    `json:"status"` // "started" or "completed"
-	Date        time.Time `json:"date"`
-	ChildID     uint      `json:"child_id"`
-	Child       User      `json:"-" gorm:"foreignKey:ChildID"`
-	OpenLibraryKey string `json:"open_library_key,omitempty"` // For books found via Open Library API
-	CoverID     *int      `json:"cover_id,omitempty"` // Open Library cover ID
-}
\ No newline at end of file
+	BookID         *uint                  `json:"book_id,omitempty" gorm:"index"`
+	Book           *Book                  `json:"book,omitempty" gorm:"foreignKey:BookID"`
+	Title          string                 `json:"title"`
+	Author         string                 `json:"author,omitempty"`
+	Status         string                 `json:"status"` // see reading_status.go for the lifecycle
+	Date           time.Time              `json:"date"`   // when the log last changed status
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	ChildID        uint                   `json:"child_id"`
+	Child          User                   `json:"-" gorm:"foreignKey:ChildID"`
+	OpenLibraryKey string                 `json:"open_library_key,omitempty"` // For books found via Open Library API
+	CoverID        *int                   `json:"cover_id,omitempty"`         // Open Library cover ID
+	ISBN           string                 `json:"isbn,omitempty"`             // ISBN-13
+	TotalPages     *int                   `json:"total_pages,omitempty"`
+	Transitions    []ReadingLogTransition `json:"-" gorm:"foreignKey:ReadingLogID"`
+	Sessions       []ReadingSession       `json:"-" gorm:"foreignKey:ReadingLogID"`
+
+	// Parent review, for children whose logs need approval. Logs that never needed it have no status and always count.
+	ApprovalStatus string     `json:"approval_status,omitempty" gorm:"index;default:null"` // see the Approval constants
+	ReviewedByID   *uint      `json:"reviewed_by_id,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	ReviewComment  string     `json:"review_comment,omitempty"`
+}
+
+// ReadingLogTransition model - records one status change of a reading log
+type ReadingLogTransition struct {
+	gorm.Model
+	ReadingLogID uint      `json:"reading_log_id" gorm:"index"`
+	FromStatus   string    `json:"from_status,omitempty"` // empty for the status a log was created with
+	ToStatus     string    `json:"to_status"`
+	ChangedAt    time.Time `json:"changed_at"`
+	ChangedByID  uint      `json:"changed_by_id"`
+}
+
+// ReadingSession model - one sitting with a book: pages covered and minutes spent
+type ReadingSession struct {
+	gorm.Model
+	ReadingLogID uint      `json:"reading_log_id" gorm:"index"`
+	ChildID      uint      `json:"child_id" gorm:"index"` // copied from the log so per-day totals don't need a join
+	StartPage    *int      `json:"start_page,omitempty"`  // where the bookmark was before; see SessionPagesRead
+	EndPage      *int      `json:"end_page,omitempty"`    // and after
+	Minutes      int       `json:"minutes"`
+	ReadAt       time.Time `json:"read_at"`
+}
+
+// Goal model - a reading target a parent sets for one child, e.g. 4 books per month
+type Goal struct {
+	gorm.Model
+	ChildID uint   `json:"child_id" gorm:"index"`
+	SetByID uint   `json:"set_by_id"` // the parent who set it
+	Metric  string `json:"metric"`    // "books", "minutes" or "pages"
+	Period  string `json:"period"`    // "week", "month" or "term"
+	Target  int    `json:"target"`
+}
+
+// AchievementRule model - a badge and the condition that earns it.
+// Rules are rows rather than code, so new and seasonal badges need no redeploy.
+type AchievementRule struct {
+	gorm.Model
+	Key         string     `json:"key" gorm:"uniqueIndex"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon,omitempty"`
+	Metric      string     `json:"metric"` // see the AchievementMetric constants
+	Threshold   int        `json:"threshold"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"` // for seasonal badges only reading inside
+	EndsAt      *time.Time `json:"ends_at,omitempty"`   // [StartsAt, EndsAt) counts, and only then can it be earned
+	Disabled    bool       `json:"disabled" gorm:"default:false"`
+}
+
+// ChildAchievement model - a badge a child has earned
+type ChildAchievement struct {
+	gorm.Model
+	ChildID   uint             `json:"child_id" gorm:"uniqueIndex:idx_child_achievement"`
+	RuleID    uint             `json:"rule_id" gorm:"uniqueIndex:idx_child_achievement"`
+	Rule      *AchievementRule `json:"rule,omitempty" gorm:"foreignKey:RuleID"`
+	AwardedAt time.Time        `json:"awarded_at"`
+}
+
+// PointRule model - how many points one kind of reading earns in a family.
+// A family without rules of its own earns by DefaultPointRules.
+type PointRule struct {
+	gorm.Model
+	ParentID uint   `json:"parent_id" gorm:"index"`
+	Event    string `json:"event"`         // see the PointEvent constants
+	Points   int    `json:"points"`        // may be 0 to switch an event off
+	Per      int    `json:"per,omitempty"` // for minutes and pages: points are earned per this many
+}
+
+// PointsLedgerEntry model - one change to a child's points. Earned points are positive, spent points negative;
+// the balance is the sum of the child's entries.
+type PointsLedgerEntry struct {
+	gorm.Model
+	ChildID          uint   `json:"child_id" gorm:"index"`
+	Points           int    `json:"points"`
+	Reason           string `json:"reason"` // see the PointsReason constants
+	ReadingLogID     *uint  `json:"reading_log_id,omitempty"`
+	ReadingSessionID *uint  `json:"reading_session_id,omitempty"`
+	RedemptionID     *uint  `json:"redemption_id,omitempty"`
+	Note             string `json:"note,omitempty"`
+	CreatedByID      *uint  `json:"created_by_id,omitempty"`           // the parent, for adjustments and redemptions
+	SourceKey        string `json:"-" gorm:"uniqueIndex;default:null"` // what earned the points, so nothing is credited twice
+}
+
+// Reward model - a prize a parent offers for points, e.g. "30 min screen time" for 50 points
+type Reward struct {
+	gorm.Model
+	ParentID    uint   `json:"parent_id" gorm:"index"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Cost        int    `json:"cost"`
+	Disabled    bool   `json:"disabled" gorm:"default:false"` // hidden from children but kept for past redemptions
+}
+
+// RewardRedemption model - a child's request to spend points on a reward, approved or rejected by their parent.
+// Name and cost are copied from the reward so later edits don't change what was asked for.
+type RewardRedemption struct {
+	gorm.Model
+	ChildID     uint       `json:"child_id" gorm:"index"`
+	RewardID    uint       `json:"reward_id" gorm:"index"`
+	RewardName  string     `json:"reward_name"`
+	Cost        int        `json:"cost"`
+	Status      string     `json:"status"` // see the RedemptionStatus constants
+	DecidedByID *uint      `json:"decided_by_id,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Comment     string     `json:"comment,omitempty"` // the parent's note, e.g. why it was rejected
+}
+
+// Book model - one catalog entry shared by every reading log of the same book
+type Book struct {
+	gorm.Model
+	Title          string `json:"title"`
+	Author         string `json:"author,omitempty"`
+	OpenLibraryKey string `json:"open_library_key,omitempty" gorm:"uniqueIndex;default:null"`
+	ISBN           string `json:"isbn,omitempty" gorm:"uniqueIndex;default:null"` // always stored as ISBN-13
+	CoverID        *int   `json:"cover_id,omitempty"`
+	PageCount      int    `json:"page_count,omitempty"`
+	Series         string `json:"series,omitempty" gorm:"index"`      // e.g. "Harry Potter", set by scripts/curate_book.go; a child who completes every catalog book in it has finished the series
+	Curated        bool   `json:"curated" gorm:"default:false;index"` // checked by an admin; searchable offline via the local catalog provider
+	MatchKey       string `json:"-" gorm:"index"`                     // normalized title and author, used to match free-text entries
+}
+
+// BookSearchCache model - a provider's results for one search query, kept until ExpiresAt
+// and served past that when the provider is down
+type BookSearchCache struct {
+	gorm.Model
+	Provider  string    `json:"provider" gorm:"uniqueIndex:idx_book_search_cache_provider_query"`
+	Query     string    `json:"query" gorm:"uniqueIndex:idx_book_search_cache_provider_query"`
+	Results   string    `json:"-" gorm:"type:text"` // JSON-encoded []books.SearchResult
+	FetchedAt time.Time `json:"fetched_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// Kinds of notification
+const (
+	NotificationChildLockedOut = "child_locked_out" // a child's PIN login was locked after too many wrong PINs
+)
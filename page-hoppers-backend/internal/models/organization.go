@@ -0,0 +1,8 @@
+package models
+
+// The default organization holds every account from before organizations existed,
+// and anyone who registers without naming one
+const (
+	DefaultOrganizationSlug = "default"
+	DefaultOrganizationName = "Page Hoppers"
+)
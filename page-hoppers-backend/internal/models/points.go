@@ -0,0 +1,38 @@
+package models
+
+// What a point rule pays out for
+const (
+	PointEventBookCompleted = "book_completed" // once per book logged, when it is first completed
+	PointEventSession       = "session"        // each reading session logged
+	PointEventMinutes       = "minutes"        // per Per minutes in a session
+	PointEventPages         = "pages"          // per Per pages in a session
+)
+
+// Why a child's points changed
+const (
+	PointsReasonBookCompleted = "book_completed"
+	PointsReasonSession       = "session"
+	PointsReasonRedemption    = "redemption"
+	PointsReasonAdjustment    = "adjustment" // added or taken away by a parent
+)
+
+// Reward redemption statuses
+const (
+	RedemptionStatusRequested = "requested"
+	RedemptionStatusApproved  = "approved"
+	RedemptionStatusRejected  = "rejected"
+)
+
+func IsValidPointEvent(event string) bool {
+	switch event {
+	case PointEventBookCompleted, PointEventSession, PointEventMinutes, PointEventPages:
+		return true
+	}
+	return false
+}
+
+// DefaultPointRules apply to families that haven't set their own: 10 points a book and 1 point per 10 minutes read
+var DefaultPointRules = []PointRule{
+	{Event: PointEventBookCompleted, Points: 10},
+	{Event: PointEventMinutes, Points: 1, Per: 10},
+}
@@ -0,0 +1,21 @@
+package models
+
+// Limits on one reading session. Points, goals and achievements are all counted from sessions,
+// so anything past these is a typo or an attempt to game them.
+const (
+	MaxSessionMinutes = 600 // ten hours in one sitting
+	MaxSessionPages   = 500 // pages covered in one sitting
+)
+
+// SessionPagesRead is the pages a session covered. StartPage and EndPage are where the bookmark was
+// before and after, so it's EndPage - StartPage: 30 to 50 is 20 pages, the next session picks up
+// at 50, and 5 to 5 is none. A session without both pages covered none.
+func SessionPagesRead(session *ReadingSession) int {
+	if session.StartPage == nil || session.EndPage == nil {
+		return 0
+	}
+	return *session.EndPage - *session.StartPage
+}
+
+// SessionPagesReadSQL is SessionPagesRead as SQL over a reading_sessions row, for totals the database adds up
+const SessionPagesReadSQL = "CASE WHEN start_page IS NOT NULL AND end_page IS NOT NULL THEN end_page - start_page ELSE 0 END"
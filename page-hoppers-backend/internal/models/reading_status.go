@@ -0,0 +1,81 @@
+package models
+
+// Reading log statuses. A log is one book read by one child and moves between
+// these states; every move is recorded as a ReadingLogTransition.
+const (
+	StatusWantToRead = "want_to_read"
+	StatusReading    = "reading"
+	StatusPaused     = "paused"
+	StatusAbandoned  = "abandoned"
+	StatusCompleted  = "completed"
+	StatusReReading  = "re_reading"
+
+	// StatusStarted is the pre-lifecycle name for StatusReading, still accepted from older clients
+	StatusStarted = "started"
+)
+
+// readingTransitions lists the statuses each status may move to
+var readingTransitions = map[string][]string{
+	StatusWantToRead: {StatusReading, StatusCompleted, StatusAbandoned},
+	StatusReading:    {StatusPaused, StatusAbandoned, StatusCompleted},
+	StatusPaused:     {StatusReading, StatusAbandoned, StatusCompleted},
+	StatusAbandoned:  {StatusReading},
+	StatusCompleted:  {StatusReReading},
+	StatusReReading:  {StatusPaused, StatusAbandoned, StatusCompleted},
+}
+
+// NormalizeReadingStatus maps legacy status names onto the lifecycle
+func NormalizeReadingStatus(status string) string {
+	if status == StatusStarted {
+		return StatusReading
+	}
+	return status
+}
+
+// IsValidReadingStatus reports whether status is part of the lifecycle (legacy names included)
+func IsValidReadingStatus(status string) bool {
+	_, ok := readingTransitions[NormalizeReadingStatus(status)]
+	return ok
+}
+
+// IsInitialReadingStatus reports whether a new log may start in status
+func IsInitialReadingStatus(status string) bool {
+	switch NormalizeReadingStatus(status) {
+	case StatusWantToRead, StatusReading, StatusCompleted:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a log in status from may move to status to
+func CanTransition(from, to string) bool {
+	from, to = NormalizeReadingStatus(from), NormalizeReadingStatus(to)
+	for _, next := range readingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInProgressStatus reports whether the book is currently being read (or on hold)
+func IsInProgressStatus(status string) bool {
+	switch NormalizeReadingStatus(status) {
+	case StatusReading, StatusReReading, StatusPaused:
+		return true
+	}
+	return false
+}
+
+// Approval statuses for logs of children whose parent reviews their entries
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// CountsTowardTotals reports whether a log counts toward summaries, goals, achievements and points:
+// anything not waiting for, or refused, a parent's approval.
+func CountsTowardTotals(log *ReadingLog) bool {
+	return log.ApprovalStatus == "" || log.ApprovalStatus == ApprovalApproved
+}
@@ -0,0 +1,131 @@
+// Package policy decides who may do what. Routes declare the roles they serve with Require;
+// handlers ask resource questions such as CanReadChild once they know which child a request is about.
+package policy
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// Account roles, as carried in access tokens
+const (
+	RoleParent  = "parent"
+	RoleChild   = "child"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin" // runs one organization
+)
+
+// Principal is the authenticated caller of a request. OrganizationID is the tenant from their token;
+// every resource they reach must be in it.
+type Principal struct {
+	UserID         uint
+	Role           string
+	OrganizationID uint
+}
+
+// FromContext returns the principal the auth middleware stored on the request
+func FromContext(c *gin.Context) (Principal, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return Principal{}, false
+	}
+	userID, ok := value.(uint)
+	if !ok {
+		return Principal{}, false
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	org, _ := c.Get("organization_id")
+	orgID, _ := org.(uint)
+	return Principal{UserID: userID, Role: roleStr, OrganizationID: orgID}, true
+}
+
+// Is reports whether the principal has one of roles
+func (p Principal) Is(roles ...string) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Require is route middleware that only lets principals with one of roles through.
+// It must run after the auth middleware; unauthenticated requests get 401 and other roles 403.
+func Require(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := FromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		if !principal.Is(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": ErrWrongRole})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ErrWrongRole is the 403 message Require gives a principal whose role the route doesn't serve
+const ErrWrongRole = "Your account can't do this"
+
+// CanReadChild reports whether the principal may see the child's data: the child themselves,
+// or any guardian, viewers included
+func CanReadChild(db *gorm.DB, p Principal, childID uint) bool {
+	switch p.Role {
+	case RoleChild:
+		return p.UserID == childID
+	case RoleParent:
+		role, _ := repository.GuardianRole(db, p.UserID, childID)
+		return role != "" && inOrganization(db, p, childID)
+	}
+	return false
+}
+
+// CanWriteChild reports whether the principal may change the child's data: the child themselves,
+// or the child's owner or a co-parent
+func CanWriteChild(db *gorm.DB, p Principal, childID uint) bool {
+	switch p.Role {
+	case RoleChild:
+		return p.UserID == childID
+	case RoleParent:
+		role, _ := repository.GuardianRole(db, p.UserID, childID)
+		return (role == models.GuardianOwner || role == models.GuardianCoParent) && inOrganization(db, p, childID)
+	}
+	return false
+}
+
+// CanManageGuardians reports whether the principal decides who else can see the child: only the owner
+func CanManageGuardians(db *gorm.DB, p Principal, childID uint) bool {
+	if p.Role != RoleParent {
+		return false
+	}
+	role, _ := repository.GuardianRole(db, p.UserID, childID)
+	return role == models.GuardianOwner && inOrganization(db, p, childID)
+}
+
+// CanTeachClassroom reports whether the principal may see the classroom and its students' reading:
+// only the teacher who runs it
+func CanTeachClassroom(db *gorm.DB, p Principal, classroomID uint) bool {
+	if p.Role != RoleTeacher {
+		return false
+	}
+	var count int64
+	db.Model(&models.Classroom{}).
+		Where("id = ? AND teacher_id = ? AND organization_id = ?", classroomID, p.UserID, p.OrganizationID).
+		Count(&count)
+	return count > 0
+}
+
+// inOrganization reports whether the user is in the principal's organization. Guardianships never cross
+// organizations, so this only matters if one somehow did.
+func inOrganization(db *gorm.DB, p Principal, userID uint) bool {
+	orgID, err := repository.UserOrganizationID(db, userID)
+	return err == nil && orgID == p.OrganizationID
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// SeedAchievementRules adds any default rule whose key isn't in the database yet.
+// Rules already there are left alone, so edits made in the database survive a migration.
+func SeedAchievementRules(db *gorm.DB) error {
+	for _, rule := range models.DefaultAchievementRules {
+		if err := db.Where(models.AchievementRule{Key: rule.Key}).
+			Attrs(rule).
+			FirstOrCreate(&models.AchievementRule{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetChildAchievements returns the badges a child has earned, newest first
+func GetChildAchievements(db *gorm.DB, childID uint) ([]models.ChildAchievement, error) {
+	var earned []models.ChildAchievement
+	err := db.Preload("Rule").Where("child_id = ?", childID).Order("awarded_at DESC").Find(&earned).Error
+	return earned, err
+}
+
+// EvaluateAchievements awards every rule the child now meets and hasn't earned yet, and returns the new awards.
+// Call it after committing anything that changes a child's reading.
+func EvaluateAchievements(db *gorm.DB, childID uint, now time.Time) ([]models.ChildAchievement, error) {
+	var rules []models.AchievementRule
+	if err := db.Where("disabled = ?", false).
+		Where("id NOT IN (?)", db.Model(&models.ChildAchievement{}).Select("rule_id").Where("child_id = ?", childID)).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	metrics := &achievementMetrics{db: db, childID: childID, now: now, values: map[string]int{}}
+	var awarded []models.ChildAchievement
+	for i := range rules {
+		rule := &rules[i]
+		if rule.StartsAt != nil && now.Before(*rule.StartsAt) || rule.EndsAt != nil && !now.Before(*rule.EndsAt) {
+			continue
+		}
+
+		value, err := metrics.value(rule)
+		if err != nil {
+			return nil, err
+		}
+		if value < rule.Threshold {
+			continue
+		}
+
+		award := models.ChildAchievement{ChildID: childID, RuleID: rule.ID, AwardedAt: now}
+		// Two requests can evaluate at once; the unique index keeps one award
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&award)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			award.Rule = rule
+			awarded = append(awarded, award)
+		}
+	}
+	return awarded, nil
+}
+
+// achievementMetrics computes each metric once per window while evaluating a child's rules
+type achievementMetrics struct {
+	db      *gorm.DB
+	childID uint
+	now     time.Time
+	values  map[string]int
+}
+
+func (m *achievementMetrics) value(rule *models.AchievementRule) (int, error) {
+	key := rule.Metric
+	if rule.StartsAt != nil {
+		key += "|" + rule.StartsAt.String()
+	}
+	if rule.EndsAt != nil {
+		key += "|" + rule.EndsAt.String()
+	}
+	if value, ok := m.values[key]; ok {
+		return value, nil
+	}
+
+	value, err := m.compute(rule.Metric, rule.StartsAt, rule.EndsAt)
+	if err != nil {
+		return 0, err
+	}
+	m.values[key] = value
+	return value, nil
+}
+
+func (m *achievementMetrics) compute(metric string, from, to *time.Time) (int, error) {
+	// Completed logs count by when they were completed, falling back to the log date for older rows
+	completed := func() *gorm.DB {
+		query := m.db.Model(&models.ReadingLog{}).
+			Where("reading_logs.child_id = ? AND reading_logs.status = ?", m.childID, models.StatusCompleted).
+			Scopes(countedLogs)
+		if from != nil {
+			query = query.Where("COALESCE(reading_logs.completed_at, reading_logs.date) >= ?", from.UTC())
+		}
+		if to != nil {
+			query = query.Where("COALESCE(reading_logs.completed_at, reading_logs.date) < ?", to.UTC())
+		}
+		return query
+	}
+
+	var count int64
+	switch metric {
+	case models.AchievementMetricBooksCompleted:
+		err := completed().Count(&count).Error
+		return int(count), err
+
+	case models.AchievementMetricDistinctAuthors:
+		err := completed().Where("reading_logs.author <> ''").Distinct("LOWER(reading_logs.author)").Count(&count).Error
+		return int(count), err
+
+	case models.AchievementMetricSeriesCompleted:
+		return m.seriesCompleted(completed())
+
+	case models.AchievementMetricMinutesRead, models.AchievementMetricPagesRead:
+		query := m.db.Model(&models.ReadingSession{}).
+			Joins("JOIN reading_logs ON reading_logs.id = reading_sessions.reading_log_id AND reading_logs.deleted_at IS NULL").
+			Where("reading_sessions.child_id = ?", m.childID).
+			Scopes(countedSessions)
+		if from != nil {
+			query = query.Where("reading_sessions.read_at >= ?", from.UTC())
+		}
+		if to != nil {
+			query = query.Where("reading_sessions.read_at < ?", to.UTC())
+		}
+		column := "COALESCE(SUM(minutes), 0)"
+		if metric == models.AchievementMetricPagesRead {
+			column = "COALESCE(SUM(" + models.SessionPagesReadSQL + "), 0)"
+		}
+		var total int
+		err := query.Select(column).Scan(&total).Error
+		return total, err
+
+	case models.AchievementMetricStreakDays:
+		return m.longestStreak(from, to)
+	}
+	// An unknown metric (e.g. a typo in a hand-added rule) is never met
+	return 0, nil
+}
+
+// seriesCompleted counts series where the child has completed every catalog book
+func (m *achievementMetrics) seriesCompleted(completed *gorm.DB) (int, error) {
+	var read []struct {
+		Series string
+		Books  int
+	}
+	if err := completed.Joins("JOIN books ON books.id = reading_logs.book_id AND books.deleted_at IS NULL").
+		Where("books.series <> ''").
+		Select("books.series AS series, COUNT(DISTINCT books.id) AS books").
+		Group("books.series").
+		Scan(&read).Error; err != nil {
+		return 0, err
+	}
+
+	finished := 0
+	for _, series := range read {
+		var total int64
+		if err := m.db.Model(&models.Book{}).Where("series = ?", series.Series).Count(&total).Error; err != nil {
+			return 0, err
+		}
+		// A one-book "series" isn't much of an achievement
+		if total > 1 && int64(series.Books) >= total {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+// longestStreak is the child's longest streak in the family's time zone, counting only days in the window
+func (m *achievementMetrics) longestStreak(from, to *time.Time) (int, error) {
+	loc, err := FamilyLocation(m.db, m.childID)
+	if err != nil {
+		return 0, err
+	}
+	graceDays, err := FamilyStreakGraceDays(m.db, m.childID)
+	if err != nil {
+		return 0, err
+	}
+
+	days, err := readingDays(m.db, m.childID, m.now.In(loc))
+	if err != nil {
+		return 0, err
+	}
+	if from != nil || to != nil {
+		inWindow := days[:0]
+		for _, day := range days {
+			if from != nil && time.Time(day).Before(time.Time(toCivilDay(*from, loc))) {
+				continue
+			}
+			if to != nil && !time.Time(day).Before(time.Time(toCivilDay(*to, loc))) {
+				continue
+			}
+			inWindow = append(inWindow, day)
+		}
+		days = inWindow
+	}
+	if len(days) == 0 {
+		return 0, nil
+	}
+	return computeStreaks(days, days[len(days)-1], graceDays).LongestStreak, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+var (
+	// ErrChildNotDeleted is returned when restoring a child who was never deleted
+	ErrChildNotDeleted = errors.New("child is not deleted")
+	// ErrMergeNotAllowed is returned unless both accounts are different parents in the same organization
+	ErrMergeNotAllowed = errors.New("only two parents in the same organization can be merged")
+)
+
+// maxUserSearchResults caps SearchUsers, so a short query can't page through a whole school
+const maxUserSearchResults = 50
+
+// RecordAdminAction adds an entry to the organization's admin audit log. targetUserID is 0 for actions
+// not about one account, such as a search.
+func RecordAdminAction(db *gorm.DB, orgID, adminID uint, action string, targetUserID uint, detail string) error {
+	entry := models.AdminAuditEntry{OrganizationID: orgID, AdminID: adminID, Action: action, Detail: detail}
+	if targetUserID != 0 {
+		entry.TargetUserID = &targetUserID
+	}
+	return db.Create(&entry).Error
+}
+
+// AdminAuditLog returns the organization's most recent admin audit entries, newest first
+func AdminAuditLog(db *gorm.DB, orgID uint, limit int) ([]models.AdminAuditEntry, error) {
+	entries := []models.AdminAuditEntry{}
+	err := db.Where("organization_id = ?", orgID).Order("id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// IsActiveAdmin reports whether the user is an admin of the organization whose account is still switched on
+func IsActiveAdmin(db *gorm.DB, userID, orgID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.User{}).
+		Where("id = ? AND role = ? AND organization_id = ? AND deactivated_at IS NULL", userID, "admin", orgID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SearchUsers finds accounts in the organization whose name or email contains query, optionally only those
+// with role. Deactivated and deleted accounts are included, since those are often what support is looking for.
+func SearchUsers(db *gorm.DB, orgID uint, query, role string) ([]models.User, error) {
+	search := db.Unscoped().Scopes(InOrganization(orgID))
+	if query = strings.ToLower(strings.TrimSpace(query)); query != "" {
+		pattern := ContainsPattern(query)
+		search = search.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+	if role != "" {
+		search = search.Where("role = ?", role)
+	}
+	users := []models.User{}
+	err := search.Order("id ASC").Limit(maxUserSearchResults).Find(&users).Error
+	return users, err
+}
+
+// FamilyTree returns the parent at the head of the user's family with every child they own, deleted ones
+// included: a parent's own family, or a child's parent's. Adults without children come back on their own.
+func FamilyTree(db *gorm.DB, orgID, userID uint) (*models.User, error) {
+	var user models.User
+	if err := db.Unscoped().Scopes(InOrganization(orgID)).First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	rootID := user.ID
+	if user.Role == "child" && user.ParentID != nil {
+		rootID = *user.ParentID
+	}
+
+	var root models.User
+	err := db.Unscoped().Scopes(InOrganization(orgID)).
+		Preload("Children", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Order("id ASC") }).
+		First(&root, rootID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// DeactivateUser switches an account off and signs out all its devices. It can't sign in again until reactivated.
+func DeactivateUser(db *gorm.DB, userID uint, now time.Time) error {
+	now = now.UTC()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ? AND deactivated_at IS NULL", userID).
+			Update("deactivated_at", now).Error; err != nil {
+			return err
+		}
+		return RevokeUserAuthSessions(tx, userID, now)
+	})
+}
+
+// ReactivateUser lets a deactivated account sign in again
+func ReactivateUser(db *gorm.DB, userID uint) error {
+	return db.Model(&models.User{}).Where("id = ?", userID).Update("deactivated_at", nil).Error
+}
+
+// RestoreChild brings back a deleted child in the organization, with their reading history intact
+func RestoreChild(db *gorm.DB, orgID, childID uint) error {
+	result := db.Unscoped().Model(&models.User{}).Scopes(InOrganization(orgID)).
+		Where("id = ? AND role = ? AND deleted_at IS NOT NULL", childID, "child").
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChildNotDeleted
+	}
+	return nil
+}
+
+// MergeParents moves everything a duplicate parent account owns onto the one the family keeps: their children,
+// guardianships, rewards, paired devices, invites, goals and notifications. Family point rules move only if the
+// kept account has none of its own. The duplicate is then deactivated.
+// Who approved a log or a redemption stays as it was, since that is history.
+func MergeParents(db *gorm.DB, fromID, intoID uint, now time.Time) error {
+	if fromID == intoID {
+		return ErrMergeNotAllowed
+	}
+	var from, into models.User
+	if err := db.Where("id = ? AND role = ?", fromID, "parent").First(&from).Error; err != nil {
+		return ErrMergeNotAllowed
+	}
+	if err := db.Where("id = ? AND role = ?", intoID, "parent").First(&into).Error; err != nil {
+		return ErrMergeNotAllowed
+	}
+	if from.OrganizationID != into.OrganizationID {
+		return ErrMergeNotAllowed
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.User{}).Where("parent_id = ?", fromID).
+			Update("parent_id", intoID).Error; err != nil {
+			return err
+		}
+
+		// A guardianship the kept account already covers, as owner or guardian, is dropped rather than duplicated
+		owned := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.User{}).Select("id").Where("parent_id = ?", intoID)
+		guarded := tx.Session(&gorm.Session{NewDB: true}).Model(&models.Guardianship{}).Select("child_id").Where("guardian_id = ?", intoID)
+		if err := tx.Unscoped().Where("guardian_id = ? AND (child_id IN (?) OR child_id IN (?))", fromID, owned, guarded).
+			Delete(&models.Guardianship{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Guardianship{}).Where("guardian_id = ?", fromID).Update("guardian_id", intoID).Error; err != nil {
+			return err
+		}
+		// The kept account may have been invited to the duplicate's children, whom it now owns
+		if err := tx.Unscoped().Where("guardian_id = ? AND child_id IN (?)", intoID, owned).
+			Delete(&models.Guardianship{}).Error; err != nil {
+			return err
+		}
+
+		moves := []struct {
+			model  interface{}
+			column string
+		}{
+			{&models.Reward{}, "parent_id"},
+			{&models.FamilyDevice{}, "parent_id"},
+			{&models.GuardianInvite{}, "invited_by_id"},
+			{&models.Goal{}, "set_by_id"},
+			{&models.Notification{}, "user_id"},
+		}
+		for _, move := range moves {
+			if err := tx.Model(move.model).Where(move.column+" = ?", fromID).Update(move.column, intoID).Error; err != nil {
+				return err
+			}
+		}
+
+		var rules int64
+		if err := tx.Model(&models.PointRule{}).Where("parent_id = ?", intoID).Count(&rules).Error; err != nil {
+			return err
+		}
+		if rules == 0 {
+			if err := tx.Model(&models.PointRule{}).Where("parent_id = ?", fromID).Update("parent_id", intoID).Error; err != nil {
+				return err
+			}
+		}
+
+		return DeactivateUser(tx, fromID, now)
+	})
+}
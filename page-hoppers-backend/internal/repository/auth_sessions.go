@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// RefreshTokenTTL is how long a device stays signed in without being used. Each refresh restarts it.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	// ErrInvalidRefreshToken is returned for refresh tokens that are unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when an already-rotated refresh token comes back.
+	// Only a copy of the token could do that, so the whole session is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// CreateAuthSession signs a device in and returns the session with its refresh token.
+// Only the token's hash is stored, so the token itself is never seen again.
+func CreateAuthSession(db *gorm.DB, userID uint, userAgent, ipAddress string, now time.Time) (*models.AuthSession, string, error) {
+	now = now.UTC() // SQLite compares times as text, so keep one offset
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session := &models.AuthSession{
+		UserID:           userID,
+		RefreshTokenHash: hashRefreshToken(token),
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+	}
+	if err := db.Create(session).Error; err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one on the same session
+func RotateRefreshToken(db *gorm.DB, token string, now time.Time) (*models.AuthSession, string, error) {
+	now = now.UTC()
+	hash := hashRefreshToken(token)
+
+	var session models.AuthSession
+	if err := db.Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, "", err
+		}
+		// A token that was already swapped for a new one is being replayed
+		var reused models.AuthSession
+		if err := db.Where("previous_token_hash = ? AND revoked_at IS NULL", hash).First(&reused).Error; err == nil {
+			if err := RevokeAuthSession(db, &reused, now); err != nil {
+				return nil, "", err
+			}
+			return nil, "", ErrRefreshTokenReused
+		}
+		return nil, "", ErrInvalidRefreshToken
+	}
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	// Matching on the old hash means two refreshes racing with the same token can't both succeed
+	result := db.Model(&models.AuthSession{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, hash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  hashRefreshToken(next),
+			"previous_token_hash": hash,
+			"last_used_at":        now,
+			"expires_at":          now.Add(RefreshTokenTTL),
+		})
+	if result.Error != nil {
+		return nil, "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	session.PreviousTokenHash = hash
+	session.RefreshTokenHash = hashRefreshToken(next)
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(RefreshTokenTTL)
+	return &session, next, nil
+}
+
+// RevokeAuthSession signs the session's device out
+func RevokeAuthSession(db *gorm.DB, session *models.AuthSession, now time.Time) error {
+	now = now.UTC()
+	if err := db.Model(session).Where("revoked_at IS NULL").Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	session.RevokedAt = &now
+	return nil
+}
+
+// IsAuthSessionActive reports whether the session exists for userID and hasn't been revoked or expired
+func IsAuthSessionActive(db *gorm.DB, sessionID, userID uint, now time.Time) (bool, error) {
+	var count int64
+	err := db.Model(&models.AuthSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, now.UTC()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListActiveAuthSessions returns the signed-in devices of the given users, most recently used first
+func ListActiveAuthSessions(db *gorm.DB, userIDs []uint, now time.Time) ([]models.AuthSession, error) {
+	var sessions []models.AuthSession
+	err := db.Preload("User").
+		Where("user_id IN ? AND revoked_at IS NULL AND expires_at > ?", userIDs, now.UTC()).
+		Order("last_used_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeUserAuthSessions signs all of a user's devices out, e.g. after their password changes
+func RevokeUserAuthSessions(db *gorm.DB, userID uint, now time.Time) error {
+	return db.Model(&models.AuthSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now.UTC()).Error
+}
+
+func newRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken uses SHA-256 rather than bcrypt: the token is 256 random bits, so there is nothing to
+// brute-force, and a plain hash can be looked up directly.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// BookMatchKey normalizes a title and author so "The BFG" by "Roald Dahl" and
+// "the bfg" by "roald  dahl." land on the same Book
+func BookMatchKey(title, author string) string {
+	normalize := func(s string) string {
+		var b strings.Builder
+		space := false
+		for _, r := range strings.ToLower(s) {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				if space && b.Len() > 0 {
+					b.WriteRune(' ')
+				}
+				b.WriteRune(r)
+				space = false
+			case unicode.IsSpace(r) || r == '-' || r == '_':
+				space = true
+			}
+		}
+		return b.String()
+	}
+	return normalize(title) + "|" + normalize(author)
+}
+
+// FindOrCreateBook returns the catalog entry for the given book details, creating it if needed.
+// Matches by Open Library key, then ISBN, then normalized title and author; missing
+// identifiers and covers on a matched book are filled in from the input.
+func FindOrCreateBook(db *gorm.DB, input models.Book) (*models.Book, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Author = strings.TrimSpace(input.Author)
+	input.MatchKey = BookMatchKey(input.Title, input.Author)
+
+	var book models.Book
+	found := false
+	if input.OpenLibraryKey != "" {
+		found = db.Where("open_library_key = ?", input.OpenLibraryKey).Limit(1).Find(&book).RowsAffected > 0
+	}
+	if !found && input.ISBN != "" {
+		found = db.Where("isbn = ?", input.ISBN).Limit(1).Find(&book).RowsAffected > 0
+	}
+	if !found {
+		query := db.Where("match_key = ?", input.MatchKey)
+		// Don't merge two books that both carry different Open Library keys
+		if input.OpenLibraryKey != "" {
+			query = query.Where("open_library_key IS NULL")
+		}
+		found = query.Order("id").Limit(1).Find(&book).RowsAffected > 0
+	}
+
+	if !found {
+		if err := db.Create(&input).Error; err != nil {
+			return nil, err
+		}
+		return &input, nil
+	}
+
+	updates := map[string]interface{}{}
+	if book.OpenLibraryKey == "" && input.OpenLibraryKey != "" {
+		updates["open_library_key"] = input.OpenLibraryKey
+	}
+	if book.ISBN == "" && input.ISBN != "" {
+		updates["isbn"] = input.ISBN
+	}
+	if book.CoverID == nil && input.CoverID != nil {
+		updates["cover_id"] = input.CoverID
+	}
+	if book.Author == "" && input.Author != "" {
+		updates["author"] = input.Author
+	}
+	if book.PageCount == 0 && input.PageCount != 0 {
+		updates["page_count"] = input.PageCount
+	}
+	if book.Series == "" && input.Series != "" {
+		updates["series"] = input.Series
+	}
+	if len(updates) > 0 {
+		if err := db.Model(&book).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &book, nil
+}
+
+// CurateBook puts a book in the curated catalog the local provider searches offline, or takes it out
+// when curated is false. The book is found or created as FindOrCreateBook does. A series given here
+// replaces the book's, since the catalog is where series are kept: finishing one means completing
+// every catalog book in it.
+func CurateBook(db *gorm.DB, input models.Book, curated bool) (*models.Book, error) {
+	book, err := FindOrCreateBook(db, input)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{"curated": curated}
+	if input.Series != "" {
+		updates["series"] = input.Series
+	}
+	if err := db.Model(book).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	book.Curated = curated
+	if input.Series != "" {
+		book.Series = input.Series
+	}
+	return book, nil
+}
+
+// LinkReadingLogToBook points a log at its catalog entry and copies the canonical details onto it
+func LinkReadingLogToBook(log *models.ReadingLog, book *models.Book) {
+	log.BookID = &book.ID
+	log.Book = book
+	log.Title = book.Title
+	log.Author = book.Author
+	log.OpenLibraryKey = book.OpenLibraryKey
+	log.ISBN = book.ISBN
+	log.CoverID = book.CoverID
+}
+
+// MigrateBooks moves the per-log title/author/key/cover onto shared Book rows.
+// Logs for the same book (by Open Library key, or by normalized title and author) end up on one
+// Book, and their copies are rewritten to that Book's spelling. A child left with several logs
+// for one book by this linking then has them merged, as logging it again would have done; once every
+// log has a Book there's nothing left to link, so later startups don't merge anything.
+func MigrateBooks(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var logs []models.ReadingLog
+		if err := tx.Unscoped().Where("book_id IS NULL").Order("id").Find(&logs).Error; err != nil {
+			return err
+		}
+
+		linked := map[[2]uint]bool{}
+		for i := range logs {
+			log := &logs[i]
+			book, err := FindOrCreateBook(tx, models.Book{
+				Title:          log.Title,
+				Author:         log.Author,
+				OpenLibraryKey: log.OpenLibraryKey,
+				CoverID:        log.CoverID,
+			})
+			if err != nil {
+				return err
+			}
+
+			LinkReadingLogToBook(log, book)
+			if err := tx.Unscoped().Model(log).
+				Select("book_id", "title", "author", "open_library_key", "isbn", "cover_id").
+				Updates(log).Error; err != nil {
+				return err
+			}
+			linked[[2]uint{log.ChildID, book.ID}] = true
+		}
+		return mergeDuplicateReadingLogs(tx, linked)
+	})
+}
+
+// mergeDuplicateReadingLogs folds each child's logs for the same book into the latest one, the log that
+// logging the book again would move along, for the child and book pairs in linked. The others' sessions,
+// status history and points move onto it, it keeps the earliest start, and they are deleted (restorable from the trash).
+func mergeDuplicateReadingLogs(tx *gorm.DB, linked map[[2]uint]bool) error {
+	var groups []struct {
+		ChildID uint
+		BookID  uint
+	}
+	if err := tx.Model(&models.ReadingLog{}).Select("child_id, book_id").
+		Where("book_id IS NOT NULL").Group("child_id, book_id").Having("COUNT(*) > 1").
+		Scan(&groups).Error; err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		if !linked[[2]uint{group.ChildID, group.BookID}] {
+			continue
+		}
+		var logs []models.ReadingLog
+		if err := tx.Where("child_id = ? AND book_id = ?", group.ChildID, group.BookID).
+			Order("date DESC, id DESC").Find(&logs).Error; err != nil {
+			return err
+		}
+		kept, duplicates := logs[0], logs[1:]
+
+		ids := make([]uint, len(duplicates))
+		updates := map[string]interface{}{}
+		for i, duplicate := range duplicates {
+			ids[i] = duplicate.ID
+			if duplicate.StartedAt != nil && (kept.StartedAt == nil || duplicate.StartedAt.Before(*kept.StartedAt)) {
+				kept.StartedAt = duplicate.StartedAt
+				updates["started_at"] = *duplicate.StartedAt
+			}
+			if kept.CompletedAt == nil && duplicate.CompletedAt != nil {
+				kept.CompletedAt = duplicate.CompletedAt
+				updates["completed_at"] = *duplicate.CompletedAt
+			}
+			if kept.TotalPages == nil && duplicate.TotalPages != nil {
+				kept.TotalPages = duplicate.TotalPages
+				updates["total_pages"] = *duplicate.TotalPages
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&kept).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		for _, model := range []interface{}{&models.ReadingSession{}, &models.ReadingLogTransition{}, &models.PointsLedgerEntry{}} {
+			if err := tx.Model(model).Where("reading_log_id IN ?", ids).Update("reading_log_id", kept.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&models.ReadingLog{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
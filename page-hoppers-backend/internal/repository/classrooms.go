@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ErrInvalidJoinCode is returned for join codes that don't belong to any classroom
+var ErrInvalidJoinCode = errors.New("invalid join code")
+
+// Join codes are read aloud and copied off a whiteboard, so they leave out look-alikes such as 0/O and 1/I
+const (
+	joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	joinCodeLength   = 8
+)
+
+// NewJoinCode makes a random classroom join code
+func NewJoinCode() (string, error) {
+	buf := make([]byte, joinCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		// 256 is a multiple of the alphabet's 32 letters, so every letter is equally likely
+		buf[i] = joinCodeAlphabet[int(b)%len(joinCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// NormalizeJoinCode accepts a code however it was typed: any case, with spaces or dashes
+func NormalizeJoinCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// CreateClassroom starts a class for the teacher, in the teacher's organization, with a fresh join code
+func CreateClassroom(db *gorm.DB, teacherID uint, name string) (*models.Classroom, error) {
+	orgID, err := UserOrganizationID(db, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	code, err := NewJoinCode()
+	if err != nil {
+		return nil, err
+	}
+	classroom := &models.Classroom{OrganizationID: orgID, TeacherID: teacherID, Name: name, JoinCode: code}
+	if err := db.Create(classroom).Error; err != nil {
+		return nil, err
+	}
+	return classroom, nil
+}
+
+// TeacherClassrooms returns the teacher's classes, oldest first
+func TeacherClassrooms(db *gorm.DB, teacherID uint) ([]models.Classroom, error) {
+	var classrooms []models.Classroom
+	err := db.Where("teacher_id = ?", teacherID).Order("id ASC").Find(&classrooms).Error
+	return classrooms, err
+}
+
+// RotateJoinCode gives the classroom a new join code; the old one stops working. Children already in
+// the class stay in it.
+func RotateJoinCode(db *gorm.DB, classroom *models.Classroom) error {
+	code, err := NewJoinCode()
+	if err != nil {
+		return err
+	}
+	if err := db.Model(classroom).Update("join_code", code).Error; err != nil {
+		return err
+	}
+	classroom.JoinCode = code
+	return nil
+}
+
+// JoinClassroom adds the child to the classroom with the given join code, recording which guardian consented.
+// Only classrooms in the child's organization can be joined. Joining a class the child is already in is not an error.
+func JoinClassroom(db *gorm.DB, code string, childID, consentedByID uint, now time.Time) (*models.Classroom, error) {
+	code = NormalizeJoinCode(code)
+	if code == "" {
+		return nil, ErrInvalidJoinCode
+	}
+
+	var classroom models.Classroom
+	childOrg := db.Model(&models.User{}).Select("organization_id").Where("id = ?", childID)
+	if err := db.Where("join_code = ? AND organization_id IN (?)", code, childOrg).First(&classroom).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidJoinCode
+		}
+		return nil, err
+	}
+
+	member := models.ClassroomMember{
+		ClassroomID:   classroom.ID,
+		ChildID:       childID,
+		ConsentedByID: consentedByID,
+		ConsentedAt:   now.UTC(),
+	}
+	// Two guardians can consent at once; the unique index keeps one membership
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&member).Error; err != nil {
+		return nil, err
+	}
+	return &classroom, nil
+}
+
+// LeaveClassroom takes the child out of the classroom, withdrawing consent. It reports whether they were in it.
+func LeaveClassroom(db *gorm.DB, classroomID, childID uint) (bool, error) {
+	result := db.Unscoped().Where("classroom_id = ? AND child_id = ?", classroomID, childID).Delete(&models.ClassroomMember{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ChildClassroom is a class a child is in, as the child's guardians see it
+type ChildClassroom struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	TeacherName string    `json:"teacher_name"`
+	ConsentedAt time.Time `json:"consented_at"`
+}
+
+// ChildClassrooms returns the classes the child is in, in the order they joined
+func ChildClassrooms(db *gorm.DB, childID uint) ([]ChildClassroom, error) {
+	classrooms := []ChildClassroom{}
+	err := db.Model(&models.ClassroomMember{}).
+		Joins("JOIN classrooms ON classrooms.id = classroom_members.classroom_id AND classrooms.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = classrooms.teacher_id").
+		Where("classroom_members.child_id = ?", childID).
+		Select("classrooms.id AS id, classrooms.name AS name, users.name AS teacher_name, classroom_members.consented_at AS consented_at").
+		Order("classroom_members.id ASC").
+		Scan(&classrooms).Error
+	return classrooms, err
+}
+
+// ClassroomStudents returns the children in the classroom, by name. Only children in the classroom's
+// organization are listed.
+func ClassroomStudents(db *gorm.DB, classroomID uint) ([]models.User, error) {
+	var students []models.User
+	err := db.Where("role = ? AND id IN (?) AND organization_id IN (?)", "child",
+		db.Model(&models.ClassroomMember{}).Select("child_id").Where("classroom_id = ?", classroomID),
+		db.Model(&models.Classroom{}).Select("organization_id").Where("id = ?", classroomID)).
+		Order("name ASC").Order("id ASC").
+		Find(&students).Error
+	return students, err
+}
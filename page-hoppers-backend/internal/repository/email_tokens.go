@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ErrInvalidEmailToken is returned for email links that are unknown, expired, already used or replaced by a newer one
+var ErrInvalidEmailToken = errors.New("invalid email token")
+
+// IssueEmailToken records a new one-time token for the user and returns it with the ID to sign into the link.
+// Earlier unused tokens for the same purpose stop working, so only the newest email's link does.
+func IssueEmailToken(db *gorm.DB, userID uint, purpose, email string, ttl time.Duration, now time.Time) (*models.EmailToken, string, error) {
+	now = now.UTC() // SQLite compares times as text, so keep one offset
+	tokenID, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.EmailToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: hashRefreshToken(tokenID),
+		ExpiresAt: now.Add(ttl),
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return token, tokenID, nil
+}
+
+// ConsumeEmailToken uses up the token with the given ID, which must be for purpose and still valid
+func ConsumeEmailToken(db *gorm.DB, tokenID, purpose string, now time.Time) (*models.EmailToken, error) {
+	now = now.UTC()
+	var token models.EmailToken
+	if err := db.Where("token_hash = ? AND purpose = ?", hashRefreshToken(tokenID), purpose).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidEmailToken
+		}
+		return nil, err
+	}
+
+	// Only one of two requests racing with the same link gets to use it
+	result := db.Model(&models.EmailToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidEmailToken
+	}
+	token.UsedAt = &now
+	return &token, nil
+}
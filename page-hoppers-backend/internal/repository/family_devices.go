@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ErrInvalidDeviceToken is returned for pairing tokens that are unknown or whose device was unpaired
+var ErrInvalidDeviceToken = errors.New("invalid device token")
+
+// CreateFamilyDevice pairs a new device with the parent's family and returns it with its pairing token.
+// As with refresh tokens, only the token's hash is stored.
+func CreateFamilyDevice(db *gorm.DB, parentID uint, name string, now time.Time) (*models.FamilyDevice, string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	device := &models.FamilyDevice{ParentID: parentID, Name: name, TokenHash: hashRefreshToken(token)}
+	if err := db.Create(device).Error; err != nil {
+		return nil, "", err
+	}
+	return device, token, nil
+}
+
+// FindFamilyDevice returns the paired device a token belongs to and notes that it was used
+func FindFamilyDevice(db *gorm.DB, token string, now time.Time) (*models.FamilyDevice, error) {
+	now = now.UTC() // SQLite compares times as text, so keep one offset
+	if token == "" {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	var device models.FamilyDevice
+	if err := db.Where("token_hash = ? AND revoked_at IS NULL", hashRefreshToken(token)).First(&device).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidDeviceToken
+		}
+		return nil, err
+	}
+
+	if err := db.Model(&device).Update("last_used_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ListFamilyDevices returns the parent's paired devices, newest first
+func ListFamilyDevices(db *gorm.DB, parentID uint) ([]models.FamilyDevice, error) {
+	var devices []models.FamilyDevice
+	err := db.Where("parent_id = ? AND revoked_at IS NULL", parentID).Order("created_at DESC, id DESC").Find(&devices).Error
+	return devices, err
+}
+
+// RevokeFamilyDevice unpairs one of the parent's devices. It reports false if the parent has no such device.
+func RevokeFamilyDevice(db *gorm.DB, parentID, deviceID uint, now time.Time) (bool, error) {
+	result := db.Model(&models.FamilyDevice{}).
+		Where("id = ? AND parent_id = ? AND revoked_at IS NULL", deviceID, parentID).
+		Update("revoked_at", now.UTC())
+	return result.RowsAffected > 0, result.Error
+}
+
+// FamilyLoginChildren returns the children the parent manages for a login screen, oldest first,
+// giving any child without a login key one first
+func FamilyLoginChildren(db *gorm.DB, parentID uint) ([]models.User, error) {
+	var children []models.User
+	if err := db.Scopes(GuardedChildren(parentID, ManagingGuardianRoles...)).Order("id ASC").Find(&children).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range children {
+		if children[i].LoginKey != "" {
+			continue
+		}
+		key, err := NewChildLoginKey()
+		if err != nil {
+			return nil, err
+		}
+		// Only fill an empty key, so two screens loading at once agree on one
+		if err := db.Model(&children[i]).Where("login_key IS NULL").Update("login_key", key).Error; err != nil {
+			return nil, err
+		}
+		if err := db.Select("login_key").First(&children[i], children[i].ID).Error; err != nil {
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// FindChildByLoginKey returns the child with the given login key, if the parent manages them
+func FindChildByLoginKey(db *gorm.DB, parentID uint, key string) (*models.User, error) {
+	var child models.User
+	if err := db.Scopes(GuardedChildren(parentID, ManagingGuardianRoles...)).Where("login_key = ?", key).First(&child).Error; err != nil {
+		return nil, err
+	}
+	return &child, nil
+}
+
+// NewChildLoginKey makes a random key for a child, so login screens never see database IDs
+func NewChildLoginKey() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// GoalProgress is how far a child is through a goal's current period
+type GoalProgress struct {
+	models.Goal
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Current     int       `json:"current"`
+	Expected    int       `json:"expected"` // where the child would be by now at an even pace
+	Percent     float64   `json:"percent"`  // of the target, capped at 100
+	Status      string    `json:"status"`   // models.GoalStatusAchieved, GoalStatusOnTrack or GoalStatusBehind
+}
+
+// GetChildGoals returns a child's goals, oldest first
+func GetChildGoals(db *gorm.DB, childID uint) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := db.Where("child_id = ?", childID).Order("created_at ASC").Find(&goals).Error
+	return goals, err
+}
+
+// GetGoalProgress measures each goal against its current week, month or term.
+// now should be in the family's time zone so periods start at local midnight.
+func GetGoalProgress(db *gorm.DB, goals []models.Goal, now time.Time, cal SchoolCalendar) ([]GoalProgress, error) {
+	progress := make([]GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		period, err := NamedPeriod("this_"+goal.Period, now, cal)
+		if err != nil {
+			return nil, err
+		}
+		totals, err := GetPeriodTotals(db, goal.ChildID, []Period{period})
+		if err != nil {
+			return nil, err
+		}
+
+		current := totals[0].BooksCompleted
+		switch goal.Metric {
+		case models.GoalMetricMinutes:
+			current = totals[0].MinutesRead
+		case models.GoalMetricPages:
+			current = totals[0].PagesRead
+		}
+
+		progress = append(progress, newGoalProgress(goal, period, current, now))
+	}
+	return progress, nil
+}
+
+func newGoalProgress(goal models.Goal, period Period, current int, now time.Time) GoalProgress {
+	elapsed := now.Sub(period.Start).Seconds() / period.End.Sub(period.Start).Seconds()
+	if elapsed > 1 {
+		elapsed = 1
+	}
+	expected := int(float64(goal.Target) * elapsed)
+
+	p := GoalProgress{
+		Goal:        goal,
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+		Current:     current,
+		Expected:    expected,
+		Percent:     100,
+	}
+	if goal.Target > 0 && current < goal.Target {
+		p.Percent = float64(current) * 100 / float64(goal.Target)
+	}
+
+	switch {
+	case current >= goal.Target:
+		p.Status = models.GoalStatusAchieved
+	case current >= expected:
+		p.Status = models.GoalStatusOnTrack
+	default:
+		p.Status = models.GoalStatusBehind
+	}
+	return p
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+var (
+	// ErrInvalidGuardianInvite is returned for invite tokens that are unknown, expired, revoked or already accepted
+	ErrInvalidGuardianInvite = errors.New("invalid guardian invite")
+	// ErrGuardianInviteEmail is returned when someone other than the invited address tries to accept an invite
+	ErrGuardianInviteEmail = errors.New("guardian invite is for a different email")
+	// ErrGuardianInviteOrganization is returned when the invited account is in a different organization from the child
+	ErrGuardianInviteOrganization = errors.New("guardian invite is for another organization")
+)
+
+// ManagingGuardianRoles are the roles that may change a child's data, not just see it
+var ManagingGuardianRoles = []string{models.GuardianOwner, models.GuardianCoParent}
+
+// GuardianRole returns the parent's role for the child: GuardianOwner for the child's own parent,
+// the Guardianship role for anyone invited, or "" for everyone else
+func GuardianRole(db *gorm.DB, parentID, childID uint) (string, error) {
+	var child models.User
+	if err := db.Select("id", "parent_id").Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	if child.ParentID != nil && *child.ParentID == parentID {
+		return models.GuardianOwner, nil
+	}
+
+	var guardianship models.Guardianship
+	if err := db.Where("child_id = ? AND guardian_id = ?", childID, parentID).First(&guardianship).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return guardianship.Role, nil
+}
+
+// GuardedChildren scopes a users query to the children the parent is a guardian of in one of roles
+// (any role if none are given). Only children in the parent's own organization count.
+func GuardedChildren(parentID uint, roles ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		newDB := db.Session(&gorm.Session{NewDB: true})
+		invited := newDB.Model(&models.Guardianship{}).Select("child_id").Where("guardian_id = ?", parentID)
+		if len(roles) > 0 {
+			invited = invited.Where("role IN ?", roles)
+		}
+		parentOrg := newDB.Model(&models.User{}).Select("organization_id").Where("id = ?", parentID)
+		db = db.Where("users.organization_id IN (?)", parentOrg)
+
+		ownerCounts := len(roles) == 0
+		for _, role := range roles {
+			ownerCounts = ownerCounts || role == models.GuardianOwner
+		}
+		if !ownerCounts {
+			return db.Where("users.role = ? AND users.id IN (?)", "child", invited)
+		}
+		return db.Where("users.role = ? AND (users.parent_id = ? OR users.id IN (?))", "child", parentID, invited)
+	}
+}
+
+// GuardedChildIDs is a subquery of the IDs of the children the parent is a guardian of in one of roles,
+// for filters such as "child_id IN (?)"
+func GuardedChildIDs(db *gorm.DB, parentID uint, roles ...string) *gorm.DB {
+	return db.Model(&models.User{}).Select("users.id").Scopes(GuardedChildren(parentID, roles...))
+}
+
+// GuardedFamilyIDs returns the parents whose families guardianID helps run in one of roles (any role if none
+// are given): the owners of the children they guard, themselves included for their own children. Family-wide
+// settings such as rewards and point rules are kept against the owner. A parent guarding no one yet gets
+// their own family.
+func GuardedFamilyIDs(db *gorm.DB, guardianID uint, roles ...string) ([]uint, error) {
+	var ids []uint
+	if err := db.Model(&models.User{}).Scopes(GuardedChildren(guardianID, roles...)).
+		Where("users.parent_id IS NOT NULL").Distinct().Order("users.parent_id").
+		Pluck("users.parent_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		ids = []uint{guardianID}
+	}
+	return ids, nil
+}
+
+// ChildGuardianIDs returns every adult with one of roles for the child, the owner first
+func ChildGuardianIDs(db *gorm.DB, childID uint, roles ...string) ([]uint, error) {
+	guardians, err := ListGuardians(db, childID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, guardian := range guardians {
+		for _, role := range roles {
+			if guardian.Role == role {
+				ids = append(ids, guardian.UserID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// Guardian is one adult with access to a child
+type Guardian struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+}
+
+// ListGuardians returns the child's owner followed by every invited guardian, oldest first
+func ListGuardians(db *gorm.DB, childID uint) ([]Guardian, error) {
+	var child models.User
+	if err := db.Preload("Parent").Where("id = ? AND role = ?", childID, "child").First(&child).Error; err != nil {
+		return nil, err
+	}
+
+	guardians := []Guardian{}
+	if child.Parent != nil {
+		guardians = append(guardians, Guardian{UserID: child.Parent.ID, Name: child.Parent.Name, Email: child.Parent.Email, Role: models.GuardianOwner})
+	}
+
+	var invited []models.Guardianship
+	if err := db.Preload("Guardian").Where("child_id = ?", childID).Order("created_at ASC, id ASC").Find(&invited).Error; err != nil {
+		return nil, err
+	}
+	for _, guardianship := range invited {
+		if guardianship.Guardian == nil {
+			continue
+		}
+		guardians = append(guardians, Guardian{
+			UserID: guardianship.GuardianID,
+			Name:   guardianship.Guardian.Name,
+			Email:  guardianship.Guardian.Email,
+			Role:   guardianship.Role,
+		})
+	}
+	return guardians, nil
+}
+
+// SetGuardianRole changes an invited guardian's role. It reports false if they aren't the child's guardian.
+func SetGuardianRole(db *gorm.DB, childID, guardianID uint, role string) (bool, error) {
+	result := db.Model(&models.Guardianship{}).
+		Where("child_id = ? AND guardian_id = ?", childID, guardianID).
+		Update("role", role)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveGuardian takes an invited guardian's access away. It reports false if they aren't the child's guardian.
+func RemoveGuardian(db *gorm.DB, childID, guardianID uint) (bool, error) {
+	result := db.Unscoped().Where("child_id = ? AND guardian_id = ?", childID, guardianID).Delete(&models.Guardianship{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateGuardianInvite records an invitation for email to become the child's guardian and returns the token
+// to email. A new invite to the same address replaces any earlier one for the child.
+func CreateGuardianInvite(db *gorm.DB, childID, invitedByID uint, email, role string, now time.Time) (*models.GuardianInvite, string, error) {
+	now = now.UTC() // SQLite compares times as text, so keep one offset
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	invite := &models.GuardianInvite{
+		ChildID:     childID,
+		InvitedByID: invitedByID,
+		Email:       normalizeEmail(email),
+		Role:        role,
+		TokenHash:   hashRefreshToken(token),
+		ExpiresAt:   now.Add(models.GuardianInviteTTL),
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GuardianInvite{}).
+			Where("child_id = ? AND email = ? AND accepted_at IS NULL AND revoked_at IS NULL", childID, invite.Email).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(invite).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return invite, token, nil
+}
+
+// ListPendingGuardianInvites returns the child's invites that can still be accepted, newest first
+func ListPendingGuardianInvites(db *gorm.DB, childID uint, now time.Time) ([]models.GuardianInvite, error) {
+	var invites []models.GuardianInvite
+	err := db.Where("child_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", childID, now.UTC()).
+		Order("created_at DESC, id DESC").
+		Find(&invites).Error
+	return invites, err
+}
+
+// RevokeGuardianInvite cancels an invite that hasn't been accepted. It reports false if there is no such invite.
+func RevokeGuardianInvite(db *gorm.DB, invite *models.GuardianInvite, now time.Time) (bool, error) {
+	result := db.Model(&models.GuardianInvite{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invite.ID).
+		Update("revoked_at", now.UTC())
+	return result.RowsAffected > 0, result.Error
+}
+
+// AcceptGuardianInvite makes parent a guardian of the invite's child. The parent's email must be the invited one.
+// Someone already guarding the child takes the invite's role; the owner stays the owner.
+func AcceptGuardianInvite(db *gorm.DB, token string, parent *models.User, now time.Time) (*models.GuardianInvite, error) {
+	now = now.UTC()
+	var invite models.GuardianInvite
+	if err := db.Where("token_hash = ?", hashRefreshToken(token)).First(&invite).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidGuardianInvite
+		}
+		return nil, err
+	}
+	if invite.Email != normalizeEmail(parent.Email) {
+		return nil, ErrGuardianInviteEmail
+	}
+	childOrg, err := UserOrganizationID(db, invite.ChildID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrInvalidGuardianInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+	if childOrg != parent.OrganizationID {
+		return nil, ErrGuardianInviteOrganization
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Only one of two requests racing with the same link gets to use it
+		result := tx.Model(&models.GuardianInvite{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", invite.ID, now).
+			Updates(map[string]interface{}{"accepted_at": now, "accepted_by_id": parent.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidGuardianInvite
+		}
+
+		role, err := GuardianRole(tx, parent.ID, invite.ChildID)
+		if err != nil {
+			return err
+		}
+		switch role {
+		case models.GuardianOwner:
+			return nil
+		case "":
+			return tx.Create(&models.Guardianship{ChildID: invite.ChildID, GuardianID: parent.ID, Role: invite.Role}).Error
+		default:
+			_, err := SetGuardianRole(tx, invite.ChildID, parent.ID, invite.Role)
+			return err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	invite.AcceptedAt = &now
+	invite.AcceptedByID = &parent.ID
+	return &invite, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ThrottlePolicy decides how failed logins against one key slow down and lock further attempts.
+// After FreeAttempts failures each attempt must wait twice as long as the last (1s, 2s, 4s, ...) up to MaxDelay;
+// at LockoutAfter failures the key is locked for LockoutFor. Failures are forgotten ResetAfter the last one.
+type ThrottlePolicy struct {
+	FreeAttempts int
+	MaxDelay     time.Duration
+	LockoutAfter int
+	LockoutFor   time.Duration
+	ResetAfter   time.Duration
+}
+
+var (
+	// ChildPINPolicy protects one child's PIN: a 4-digit PIN gets a handful of guesses, not ten thousand
+	ChildPINPolicy = ThrottlePolicy{
+		FreeAttempts: 3,
+		MaxDelay:     5 * time.Minute,
+		LockoutAfter: 10,
+		LockoutFor:   30 * time.Minute,
+		ResetAfter:   time.Hour,
+	}
+	// LoginIPPolicy limits one address across every child, so guessing can't move from child to child
+	LoginIPPolicy = ThrottlePolicy{
+		FreeAttempts: 10,
+		MaxDelay:     5 * time.Minute,
+		LockoutAfter: 50,
+		LockoutFor:   time.Hour,
+		ResetAfter:   time.Hour,
+	}
+)
+
+// ChildThrottleKey and IPThrottleKey name the counters for a child and an address
+func ChildThrottleKey(childID uint) string { return fmt.Sprintf("child:%d", childID) }
+func IPThrottleKey(ip string) string       { return "ip:" + ip }
+
+// CheckLoginThrottle returns how long the caller must wait before trying key again (0 to go ahead)
+// and whether that's because the key is locked out rather than just slowed down.
+func CheckLoginThrottle(db *gorm.DB, key string, policy ThrottlePolicy, now time.Time) (time.Duration, bool, error) {
+	var throttle models.LoginThrottle
+	if err := db.Where("key = ?", key).First(&throttle).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now), true, nil
+	}
+	if policy.stale(&throttle, now) {
+		return 0, false, nil
+	}
+	if next := throttle.LastFailureAt.Add(policy.delay(throttle.Failures)); now.Before(next) {
+		return next.Sub(now), false, nil
+	}
+	return 0, false, nil
+}
+
+// LoginAttempt is what ReserveLoginAttempt decided about one attempt
+type LoginAttempt struct {
+	Wait      time.Duration // how long to wait before trying again; 0 if the attempt may go ahead
+	Locked    bool          // the wait is a lockout rather than a backoff
+	LockedNow bool          // this attempt reached LockoutAfter and started a lockout
+}
+
+// errLoginThrottled rolls back an attempt that has to wait, so it isn't counted
+var errLoginThrottled = errors.New("login attempt throttled")
+
+// ReserveLoginAttempt counts an attempt against key before the credentials are checked, and decides from the
+// new count whether it may go ahead. Parallel guesses each take their own place in the count, so they can't all
+// pass the same check. An attempt that has to wait isn't counted. A correct login hands its attempt back with
+// ReleaseLoginAttempt, or forgets the key with ClearLoginThrottle.
+func ReserveLoginAttempt(db *gorm.DB, key string, policy ThrottlePolicy, now time.Time) (LoginAttempt, error) {
+	now = now.UTC() // SQLite compares times as text, so keep one offset
+	var attempt LoginAttempt
+	err := db.Transaction(func(tx *gorm.DB) error {
+		throttle, err := countLoginAttempt(tx, key, policy, now)
+		if err != nil {
+			return err
+		}
+
+		if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+			attempt.Wait, attempt.Locked = throttle.LockedUntil.Sub(now), true
+			return errLoginThrottled
+		}
+		if next := throttle.LastFailureAt.Add(policy.delay(throttle.Failures - 1)); now.Before(next) {
+			attempt.Wait = next.Sub(now)
+			return errLoginThrottled
+		}
+		attempt.LockedNow, err = policy.record(tx, throttle, now)
+		return err
+	})
+	if errors.Is(err, errLoginThrottled) {
+		err = nil
+	}
+	return attempt, err
+}
+
+// ReleaseLoginAttempt takes back an attempt reserved against key that turned out to be a correct login
+func ReleaseLoginAttempt(db *gorm.DB, key string) error {
+	return db.Model(&models.LoginThrottle{}).Where("key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+// RecordLoginFailure counts a failed login against key. lockedNow is true when this failure started a lockout.
+func RecordLoginFailure(db *gorm.DB, key string, policy ThrottlePolicy, now time.Time) (lockedNow bool, err error) {
+	now = now.UTC()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		throttle, err := countLoginAttempt(tx, key, policy, now)
+		if err != nil {
+			return err
+		}
+		lockedNow, err = policy.record(tx, throttle, now)
+		return err
+	})
+	return lockedNow, err
+}
+
+// countLoginAttempt adds one to key's failures in a single upsert, which also holds the row until tx ends,
+// and returns the row with the count restarted if the earlier failures are stale. LastFailureAt is still
+// the previous failure's, for working out the backoff.
+func countLoginAttempt(tx *gorm.DB, key string, policy ThrottlePolicy, now time.Time) (*models.LoginThrottle, error) {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"failures": gorm.Expr("failures + 1"), "updated_at": now}),
+	}).Create(&models.LoginThrottle{Key: key, Failures: 1, LastFailureAt: now}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var throttle models.LoginThrottle
+	if err := tx.Where("key = ?", key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	if policy.stale(&throttle, now) {
+		throttle.Failures = 1
+	}
+	return &throttle, nil
+}
+
+// record stamps a counted attempt and locks the key if it reached LockoutAfter.
+// lockedNow is true when this attempt started the lockout.
+func (policy ThrottlePolicy) record(tx *gorm.DB, throttle *models.LoginThrottle, now time.Time) (lockedNow bool, err error) {
+	throttle.LastFailureAt = now
+	if throttle.Failures >= policy.LockoutAfter && (throttle.LockedUntil == nil || !now.Before(*throttle.LockedUntil)) {
+		lockedUntil := now.Add(policy.LockoutFor)
+		throttle.LockedUntil = &lockedUntil
+		lockedNow = true
+	}
+	return lockedNow, tx.Save(throttle).Error
+}
+
+// ClearLoginThrottle forgets the failures against key, e.g. after a successful login or a parent's unlock
+func ClearLoginThrottle(db *gorm.DB, key string) error {
+	return db.Unscoped().Where("key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
+
+// stale reports whether the last failure was long enough ago to start counting again
+func (policy ThrottlePolicy) stale(throttle *models.LoginThrottle, now time.Time) bool {
+	lockedOver := throttle.LockedUntil == nil || !now.Before(*throttle.LockedUntil)
+	return lockedOver && now.Sub(throttle.LastFailureAt) >= policy.ResetAfter
+}
+
+// delay is the wait required after the given number of failures
+func (policy ThrottlePolicy) delay(failures int) time.Duration {
+	if failures < policy.FreeAttempts {
+		return 0
+	}
+	delay := time.Second
+	for i := policy.FreeAttempts; i < failures && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, policy.MaxDelay)
+}
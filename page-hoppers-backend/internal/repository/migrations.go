@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// Migrate creates or updates every table and then runs the data migrations.
+// InitDB, scripts/migrate.go and the tests all go through here so the schema stays in one place.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&models.Organization{},
+		&models.User{},
+		&models.AdminAuditEntry{},
+		&models.AuthSession{},
+		&models.EmailToken{},
+		&models.LoginThrottle{},
+		&models.Notification{},
+		&models.FamilyDevice{},
+		&models.Guardianship{},
+		&models.GuardianInvite{},
+		&models.Classroom{},
+		&models.ClassroomMember{},
+		&models.Book{},
+		&models.BookSearchCache{},
+		&models.ReadingLog{},
+		&models.ReadingLogTransition{},
+		&models.ReadingSession{},
+		&models.Goal{},
+		&models.AchievementRule{},
+		&models.ChildAchievement{},
+		&models.PointRule{},
+		&models.PointsLedgerEntry{},
+		&models.Reward{},
+		&models.RewardRedemption{},
+	); err != nil {
+		return err
+	}
+
+	if err := MigrateOrganizations(db); err != nil {
+		return err
+	}
+
+	if err := SeedAchievementRules(db); err != nil {
+		return err
+	}
+
+	if err := MigrateReadingStatuses(db); err != nil {
+		return err
+	}
+
+	return MigrateBooks(db)
+}
+
+// MigrateReadingStatuses moves logs written before the status lifecycle onto it.
+// Older clients wrote one "started" row and later a separate "completed" row for the same book;
+// the "started" row is folded into the completed one, and any other "started" row becomes "reading".
+func MigrateReadingStatuses(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Oldest first, so when several started rows fold into one completed log the first start wins
+		var legacy []models.ReadingLog
+		if err := tx.Where("status = ?", models.StatusStarted).Order("date ASC, id ASC").Find(&legacy).Error; err != nil {
+			return err
+		}
+
+		for _, started := range legacy {
+			var completed models.ReadingLog
+			err := tx.Where("child_id = ? AND status = ? AND LOWER(title) = ? AND date >= ?",
+				started.ChildID, models.StatusCompleted, strings.ToLower(started.Title), started.Date).
+				Order("date ASC").
+				First(&completed).Error
+
+			if err == nil {
+				if err := foldStartedLog(tx, &started, &completed); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			startedAt := started.Date
+			if err := tx.Model(&started).Updates(map[string]interface{}{
+				"status":     models.StatusReading,
+				"started_at": startedAt,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.ReadingLogTransition{
+				ReadingLogID: started.ID,
+				ToStatus:     models.StatusReading,
+				ChangedAt:    startedAt,
+				ChangedByID:  started.ChildID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// foldStartedLog merges a legacy started row into the completed log for the same book. The completed
+// log gets the start date, whatever book details only the started row had, and its sessions, and is
+// given its reading and completed transitions unless it already has a history.
+func foldStartedLog(tx *gorm.DB, started, completed *models.ReadingLog) error {
+	startedAt, completedAt := started.Date, completed.Date
+	updates := map[string]interface{}{}
+	if completed.StartedAt == nil {
+		updates["started_at"] = startedAt
+	} else {
+		startedAt = *completed.StartedAt
+	}
+	if completed.CompletedAt == nil {
+		updates["completed_at"] = completedAt
+	} else {
+		completedAt = *completed.CompletedAt
+	}
+	if completed.Author == "" && started.Author != "" {
+		updates["author"] = started.Author
+	}
+	if completed.OpenLibraryKey == "" && started.OpenLibraryKey != "" {
+		updates["open_library_key"] = started.OpenLibraryKey
+	}
+	if completed.CoverID == nil && started.CoverID != nil {
+		updates["cover_id"] = *started.CoverID
+	}
+	if completed.ISBN == "" && started.ISBN != "" {
+		updates["isbn"] = started.ISBN
+	}
+	if completed.TotalPages == nil && started.TotalPages != nil {
+		updates["total_pages"] = *started.TotalPages
+	}
+	if completed.BookID == nil && started.BookID != nil {
+		updates["book_id"] = *started.BookID
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(completed).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	var history int64
+	if err := tx.Model(&models.ReadingLogTransition{}).Where("reading_log_id = ?", completed.ID).Count(&history).Error; err != nil {
+		return err
+	}
+	if history == 0 {
+		if err := tx.Create(&[]models.ReadingLogTransition{
+			{ReadingLogID: completed.ID, ToStatus: models.StatusReading, ChangedAt: startedAt, ChangedByID: started.ChildID},
+			{ReadingLogID: completed.ID, FromStatus: models.StatusReading, ToStatus: models.StatusCompleted, ChangedAt: completedAt, ChangedByID: completed.ChildID},
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.ReadingSession{}).Where("reading_log_id = ?", started.ID).
+		Update("reading_log_id", completed.ID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(started).Error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// Notify leaves a message for a user
+func Notify(db *gorm.DB, userID uint, kind, message string) (*models.Notification, error) {
+	notification := &models.Notification{UserID: userID, Kind: kind, Message: message}
+	if err := db.Create(notification).Error; err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// GetNotifications returns a user's notifications, newest first, optionally only the unread ones
+func GetNotifications(db *gorm.DB, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	query := db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []models.Notification
+	err := query.Order("created_at DESC, id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkNotificationRead marks one of the user's notifications read. It reports false if there is no such notification.
+func MarkNotificationRead(db *gorm.DB, userID, notificationID uint, now time.Time) (bool, error) {
+	result := db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+	// Already read still counts as found
+	var count int64
+	err := db.Model(&models.Notification{}).Where("id = ? AND user_id = ?", notificationID, userID).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"crypto/subtle"
+	"errors"
+	"regexp"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+var (
+	// ErrUnknownOrganization is returned for organization slugs that don't exist
+	ErrUnknownOrganization = errors.New("unknown organization")
+	// ErrInvalidOrganizationSlug is returned for slugs that aren't 2-40 lowercase letters, digits and dashes
+	ErrInvalidOrganizationSlug = errors.New("invalid organization slug")
+	// ErrWrongOrganizationJoinCode is returned when registering into an organization without its join code
+	ErrWrongOrganizationJoinCode = errors.New("wrong organization join code")
+)
+
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,39}$`)
+
+// CreateOrganization adds a tenant. The slug is what people name when they register.
+func CreateOrganization(db *gorm.DB, name, slug string) (*models.Organization, error) {
+	if !organizationSlugPattern.MatchString(slug) {
+		return nil, ErrInvalidOrganizationSlug
+	}
+	org := &models.Organization{Name: name, Slug: slug}
+	if err := db.Create(org).Error; err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// FindOrganization returns the organization with the given slug, or the default one for ""
+func FindOrganization(db *gorm.DB, slug string) (*models.Organization, error) {
+	if slug == "" {
+		slug = models.DefaultOrganizationSlug
+	}
+	var org models.Organization
+	if err := db.Where("slug = ?", slug).First(&org).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrUnknownOrganization
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+// RotateOrganizationJoinCode issues a new code for registering into the organization as role (parent
+// or teacher). The old code stops working; accounts that already joined with it stay.
+func RotateOrganizationJoinCode(db *gorm.DB, org *models.Organization, role string) (string, error) {
+	code, err := NewJoinCode()
+	if err != nil {
+		return "", err
+	}
+	column, field := "parent_join_code", &org.ParentJoinCode
+	if role == "teacher" {
+		column, field = "teacher_join_code", &org.TeacherJoinCode
+	}
+	if err := db.Model(org).Update(column, code).Error; err != nil {
+		return "", err
+	}
+	*field = code
+	return code, nil
+}
+
+// CheckOrganizationJoinCode returns ErrWrongOrganizationJoinCode unless code lets someone register into
+// org as role. The default organization is open to anyone.
+func CheckOrganizationJoinCode(org *models.Organization, role, code string) error {
+	if org.Slug == models.DefaultOrganizationSlug {
+		return nil
+	}
+	want := org.ParentJoinCode
+	if role == "teacher" {
+		want = org.TeacherJoinCode
+	}
+	if want == "" || subtle.ConstantTimeCompare([]byte(NormalizeJoinCode(code)), []byte(want)) != 1 {
+		return ErrWrongOrganizationJoinCode
+	}
+	return nil
+}
+
+// UserOrganizationID returns the organization of an account that still exists
+func UserOrganizationID(db *gorm.DB, userID uint) (uint, error) {
+	var user models.User
+	if err := db.Select("id", "organization_id").First(&user, userID).Error; err != nil {
+		return 0, err
+	}
+	return user.OrganizationID, nil
+}
+
+// InOrganization scopes a users query to one organization
+func InOrganization(orgID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("users.organization_id = ?", orgID)
+	}
+}
+
+// OrganizationMembers returns the organization's accounts, optionally only those with role, oldest first
+func OrganizationMembers(db *gorm.DB, orgID uint, role string) ([]models.User, error) {
+	query := db.Scopes(InOrganization(orgID))
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	var members []models.User
+	err := query.Order("id ASC").Find(&members).Error
+	return members, err
+}
+
+// OrganizationClassroom is a classroom as the organization's admins see it
+type OrganizationClassroom struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	TeacherID   uint   `json:"teacher_id"`
+	TeacherName string `json:"teacher_name"`
+	Students    int    `json:"students"`
+}
+
+// OrganizationClassrooms returns every classroom in the organization with its teacher and class size
+func OrganizationClassrooms(db *gorm.DB, orgID uint) ([]OrganizationClassroom, error) {
+	classrooms := []OrganizationClassroom{}
+	students := db.Model(&models.ClassroomMember{}).Select("COUNT(*)").Where("classroom_members.classroom_id = classrooms.id")
+	err := db.Model(&models.Classroom{}).
+		Joins("JOIN users ON users.id = classrooms.teacher_id").
+		Where("classrooms.organization_id = ?", orgID).
+		Select("classrooms.id AS id, classrooms.name AS name, classrooms.teacher_id AS teacher_id, users.name AS teacher_name, (?) AS students", students).
+		Order("classrooms.id ASC").
+		Scan(&classrooms).Error
+	return classrooms, err
+}
+
+// MigrateOrganizations makes sure the default organization exists and puts every account and classroom
+// from before organizations into one: adults into the default, children into their parent's
+func MigrateOrganizations(db *gorm.DB) error {
+	org := models.Organization{Name: models.DefaultOrganizationName, Slug: models.DefaultOrganizationSlug}
+	if err := db.Where(models.Organization{Slug: org.Slug}).FirstOrCreate(&org).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Unscoped().
+			Where("organization_id = 0 OR organization_id IS NULL").Where("role <> ?", "child").
+			Update("organization_id", org.ID).Error; err != nil {
+			return err
+		}
+		parentOrg := tx.Model(&models.User{}).Unscoped().Select("parents.organization_id").
+			Table("users AS parents").Where("parents.id = users.parent_id")
+		if err := tx.Model(&models.User{}).Unscoped().
+			Where("(organization_id = 0 OR organization_id IS NULL) AND role = ? AND parent_id IS NOT NULL", "child").
+			Update("organization_id", parentOrg).Error; err != nil {
+			return err
+		}
+		// A child without a parent has nowhere else to go
+		if err := tx.Model(&models.User{}).Unscoped().
+			Where("organization_id = 0 OR organization_id IS NULL").
+			Update("organization_id", org.ID).Error; err != nil {
+			return err
+		}
+
+		teacherOrg := tx.Model(&models.User{}).Unscoped().Select("users.organization_id").Where("users.id = classrooms.teacher_id")
+		return tx.Model(&models.Classroom{}).Unscoped().
+			Where("organization_id = 0 OR organization_id IS NULL").
+			Update("organization_id", teacherOrg).Error
+	})
+}
@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"page-hoppers-backend/internal/models"
+)
+
+var (
+	// ErrInsufficientPoints is returned when a child can't afford a redemption or adjustment
+	ErrInsufficientPoints = errors.New("not enough points")
+	// ErrRedemptionDecided is returned when approving or rejecting a redemption that was already decided
+	ErrRedemptionDecided = errors.New("redemption already decided")
+)
+
+// PointsBalance sums a child's ledger. Held is the cost of redemptions still waiting for a parent;
+// it can't be requested again until they're decided.
+type PointsBalance struct {
+	Earned    int `json:"earned"`
+	Spent     int `json:"spent"`
+	Balance   int `json:"balance"`
+	Held      int `json:"held"`
+	Available int `json:"available"`
+}
+
+// GetPointRules returns the family's point rules, or DefaultPointRules if the parent hasn't set any
+func GetPointRules(db *gorm.DB, parentID uint) ([]models.PointRule, error) {
+	var rules []models.PointRule
+	if err := db.Where("parent_id = ?", parentID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		rules = append(rules, models.DefaultPointRules...)
+	}
+	return rules, nil
+}
+
+// SetPointRules replaces the family's point rules. An empty list goes back to the defaults.
+// Points already earned are left as they are.
+func SetPointRules(db *gorm.DB, parentID uint, rules []models.PointRule) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("parent_id = ?", parentID).Delete(&models.PointRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].ID = 0
+			rules[i].ParentID = parentID
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// AwardCompletionPoints credits a completed log by the family's rules. Each book earns a child its completion
+// once, so calling it again returns nil; finishing a re-read earns only the points of its sessions, otherwise
+// moving a log between completed and re_reading, or deleting it and logging the book again, would pay out every time.
+// A log waiting for approval earns nothing until it's approved.
+func AwardCompletionPoints(db *gorm.DB, log *models.ReadingLog) (*models.PointsLedgerEntry, error) {
+	if models.NormalizeReadingStatus(log.Status) != models.StatusCompleted || log.CompletedAt == nil || !models.CountsTowardTotals(log) {
+		return nil, nil
+	}
+
+	// Older credits are keyed by the log, or by its completion time, rather than the book
+	credited := db.Model(&models.PointsLedgerEntry{}).Where("reason = ?", models.PointsReasonBookCompleted)
+	sourceKey := fmt.Sprintf("log:%d:completed", log.ID)
+	if log.BookID != nil {
+		sameBook := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.ReadingLog{}).
+			Select("id").Where("child_id = ? AND book_id = ?", log.ChildID, *log.BookID)
+		credited = credited.Where("reading_log_id = ? OR reading_log_id IN (?)", log.ID, sameBook)
+		sourceKey = fmt.Sprintf("child:%d:book:%d:completed", log.ChildID, *log.BookID)
+	} else {
+		credited = credited.Where("reading_log_id = ?", log.ID)
+	}
+	var count int64
+	if err := credited.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, nil
+	}
+
+	rules, err := familyPointRules(db, log.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	points := 0
+	for _, rule := range rules {
+		if rule.Event == models.PointEventBookCompleted {
+			points += rule.Points
+		}
+	}
+
+	logID := log.ID
+	return creditPoints(db, &models.PointsLedgerEntry{
+		ChildID:      log.ChildID,
+		Points:       points,
+		Reason:       models.PointsReasonBookCompleted,
+		ReadingLogID: &logID,
+		Note:         log.Title,
+		SourceKey:    sourceKey,
+	})
+}
+
+// AwardSessionPoints credits a new reading session by the family's rules, once per session.
+// Sessions on a log waiting for approval earn nothing until it's approved.
+func AwardSessionPoints(db *gorm.DB, session *models.ReadingSession) (*models.PointsLedgerEntry, error) {
+	points, err := sessionPoints(db, session)
+	if err != nil {
+		return nil, err
+	}
+
+	logID, sessionID := session.ReadingLogID, session.ID
+	return creditPoints(db, &models.PointsLedgerEntry{
+		ChildID:          session.ChildID,
+		Points:           points,
+		Reason:           models.PointsReasonSession,
+		ReadingLogID:     &logID,
+		ReadingSessionID: &sessionID,
+		SourceKey:        sessionSourceKey(session.ID),
+	})
+}
+
+// RepriceSessionPoints brings what a session earned in line with it after an edit: its credit is changed
+// to what the session earns now, or to nothing once it's deleted. A session that hadn't earned yet is
+// credited as AwardSessionPoints would. Points already spent stay spent, so the balance can drop below zero.
+func RepriceSessionPoints(db *gorm.DB, session *models.ReadingSession) error {
+	points, err := sessionPoints(db, session)
+	if err != nil {
+		return err
+	}
+
+	var entry models.PointsLedgerEntry
+	err = db.Where("source_key = ?", sessionSourceKey(session.ID)).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_, err = AwardSessionPoints(db, session)
+		return err
+	}
+	if err != nil || entry.Points == points {
+		return err
+	}
+	return db.Model(&entry).Update("points", points).Error
+}
+
+// sessionPoints is what the session earns by the family's rules: nothing if it's deleted
+// or its log doesn't count yet, and at most MaxSessionMinutes and MaxSessionPages worth
+func sessionPoints(db *gorm.DB, session *models.ReadingSession) (int, error) {
+	var counted int64
+	if err := db.Model(&models.ReadingSession{}).Where("id = ?", session.ID).Scopes(countedSessions).Count(&counted).Error; err != nil {
+		return 0, err
+	}
+	if counted == 0 {
+		return 0, nil
+	}
+
+	rules, err := familyPointRules(db, session.ChildID)
+	if err != nil {
+		return 0, err
+	}
+
+	minutes := min(session.Minutes, models.MaxSessionMinutes)
+	pages := min(models.SessionPagesRead(session), models.MaxSessionPages)
+	points := 0
+	for _, rule := range rules {
+		switch rule.Event {
+		case models.PointEventSession:
+			points += rule.Points
+		case models.PointEventMinutes:
+			points += rule.Points * per(minutes, rule.Per)
+		case models.PointEventPages:
+			points += rule.Points * per(pages, rule.Per)
+		}
+	}
+	return points, nil
+}
+
+func sessionSourceKey(sessionID uint) string {
+	return fmt.Sprintf("session:%d", sessionID)
+}
+
+// per counts whole units of size in amount; a missing size counts every one
+func per(amount, size int) int {
+	if amount <= 0 {
+		return 0
+	}
+	if size <= 1 {
+		return amount
+	}
+	return amount / size
+}
+
+func familyPointRules(db *gorm.DB, childID uint) ([]models.PointRule, error) {
+	parentID, err := FamilyParentID(db, childID)
+	if err != nil {
+		return nil, err
+	}
+	return GetPointRules(db, parentID)
+}
+
+// creditPoints adds an earned entry unless it's worth nothing or its source was already credited
+func creditPoints(db *gorm.DB, entry *models.PointsLedgerEntry) (*models.PointsLedgerEntry, error) {
+	if entry.Points <= 0 {
+		return nil, nil
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// AdjustPoints adds (or, when negative, takes away) points by hand. It won't take a child below zero.
+func AdjustPoints(db *gorm.DB, childID uint, points int, note string, parentID uint) (*models.PointsLedgerEntry, error) {
+	entry := &models.PointsLedgerEntry{
+		ChildID:     childID,
+		Points:      points,
+		Reason:      models.PointsReasonAdjustment,
+		Note:        note,
+		CreatedByID: &parentID,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		balance, err := GetPointsBalance(tx, childID)
+		if err != nil {
+			return err
+		}
+		if balance.Balance+points < 0 {
+			return ErrInsufficientPoints
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetPointsBalance totals a child's ledger and pending redemptions
+func GetPointsBalance(db *gorm.DB, childID uint) (PointsBalance, error) {
+	var balance PointsBalance
+	if err := db.Model(&models.PointsLedgerEntry{}).Where("child_id = ?", childID).
+		Select("COALESCE(SUM(CASE WHEN points > 0 THEN points ELSE 0 END), 0) AS earned, " +
+			"COALESCE(SUM(CASE WHEN points < 0 THEN -points ELSE 0 END), 0) AS spent").
+		Scan(&balance).Error; err != nil {
+		return balance, err
+	}
+	if err := db.Model(&models.RewardRedemption{}).
+		Where("child_id = ? AND status = ?", childID, models.RedemptionStatusRequested).
+		Select("COALESCE(SUM(cost), 0)").Scan(&balance.Held).Error; err != nil {
+		return balance, err
+	}
+
+	balance.Balance = balance.Earned - balance.Spent
+	balance.Available = balance.Balance - balance.Held
+	return balance, nil
+}
+
+// GetPointsLedger returns a child's most recent ledger entries, newest first
+func GetPointsLedger(db *gorm.DB, childID uint, limit int) ([]models.PointsLedgerEntry, error) {
+	var entries []models.PointsLedgerEntry
+	err := db.Where("child_id = ?", childID).Order("created_at DESC, id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// RequestRedemption asks to spend points on a reward. The cost is held until a parent decides,
+// so a child can't ask for more than they have across several requests.
+func RequestRedemption(db *gorm.DB, childID uint, reward *models.Reward) (*models.RewardRedemption, error) {
+	redemption := &models.RewardRedemption{
+		ChildID:    childID,
+		RewardID:   reward.ID,
+		RewardName: reward.Name,
+		Cost:       reward.Cost,
+		Status:     models.RedemptionStatusRequested,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		balance, err := GetPointsBalance(tx, childID)
+		if err != nil {
+			return err
+		}
+		if balance.Available < reward.Cost {
+			return ErrInsufficientPoints
+		}
+		return tx.Create(redemption).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redemption, nil
+}
+
+// DecideRedemption approves or rejects a requested redemption. Approving spends the points,
+// and fails with ErrInsufficientPoints if the balance has since dropped below the cost.
+func DecideRedemption(db *gorm.DB, redemption *models.RewardRedemption, approve bool, parentID uint, comment string, now time.Time) error {
+	status := models.RedemptionStatusRejected
+	if approve {
+		status = models.RedemptionStatusApproved
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Only the first decision wins if a redemption is approved twice at once
+		result := tx.Model(&models.RewardRedemption{}).
+			Where("id = ? AND status = ?", redemption.ID, models.RedemptionStatusRequested).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"decided_by_id": parentID,
+				"decided_at":    now,
+				"comment":       comment,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRedemptionDecided
+		}
+		if !approve {
+			return nil
+		}
+
+		balance, err := GetPointsBalance(tx, redemption.ChildID)
+		if err != nil {
+			return err
+		}
+		if balance.Balance < redemption.Cost {
+			return ErrInsufficientPoints
+		}
+		redemptionID := redemption.ID
+		return tx.Create(&models.PointsLedgerEntry{
+			ChildID:      redemption.ChildID,
+			Points:       -redemption.Cost,
+			Reason:       models.PointsReasonRedemption,
+			RedemptionID: &redemptionID,
+			Note:         redemption.RewardName,
+			CreatedByID:  &parentID,
+			SourceKey:    fmt.Sprintf("redemption:%d", redemption.ID),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	redemption.Status = status
+	redemption.DecidedByID = &parentID
+	redemption.DecidedAt = &now
+	redemption.Comment = comment
+	return nil
+}
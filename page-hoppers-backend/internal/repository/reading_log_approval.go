@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ErrNotPendingApproval is returned when reviewing a log that isn't waiting for approval
+var ErrNotPendingApproval = errors.New("reading log is not waiting for approval")
+
+// countedLogs limits a reading_logs query to logs that count toward totals (see models.CountsTowardTotals)
+func countedLogs(db *gorm.DB) *gorm.DB {
+	// Saving a whole log writes an unset status as '' rather than NULL, and both mean it never needed review
+	return db.Where("reading_logs.approval_status IS NULL OR reading_logs.approval_status IN ?", []string{"", models.ApprovalApproved})
+}
+
+// countedSessions limits a reading_sessions query to sessions on logs that count toward totals
+func countedSessions(db *gorm.DB) *gorm.DB {
+	uncounted := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.ReadingLog{}).
+		Select("id").Where("approval_status IN ?", []string{models.ApprovalPending, models.ApprovalRejected})
+	return db.Where("reading_sessions.reading_log_id NOT IN (?)", uncounted)
+}
+
+// ChildRequiresLogApproval reports whether the child's parent reviews their logs
+func ChildRequiresLogApproval(db *gorm.DB, childID uint) (bool, error) {
+	var child models.User
+	if err := db.Select("id", "requires_log_approval").First(&child, childID).Error; err != nil {
+		return false, err
+	}
+	return child.RequiresLogApproval, nil
+}
+
+// HoldForApproval puts a log back to waiting for a parent, clearing any earlier review.
+// It stops counting until it is approved.
+func HoldForApproval(db *gorm.DB, log *models.ReadingLog) error {
+	log.ApprovalStatus = models.ApprovalPending
+	log.ReviewedByID = nil
+	log.ReviewedAt = nil
+	log.ReviewComment = ""
+	return db.Model(log).Select("approval_status", "reviewed_by_id", "reviewed_at", "review_comment").Updates(log).Error
+}
+
+// ReviewReadingLog approves or rejects a log waiting for approval, with the parent's optional comment
+func ReviewReadingLog(db *gorm.DB, log *models.ReadingLog, approve bool, parentID uint, comment string, now time.Time) error {
+	status := models.ApprovalRejected
+	if approve {
+		status = models.ApprovalApproved
+	}
+
+	// Only the first review wins if two arrive at once
+	result := db.Model(&models.ReadingLog{}).
+		Where("id = ? AND approval_status = ?", log.ID, models.ApprovalPending).
+		Updates(map[string]interface{}{
+			"approval_status": status,
+			"reviewed_by_id":  parentID,
+			"reviewed_at":     now,
+			"review_comment":  comment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotPendingApproval
+	}
+
+	log.ApprovalStatus = status
+	log.ReviewedByID = &parentID
+	log.ReviewedAt = &now
+	log.ReviewComment = comment
+	return nil
+}
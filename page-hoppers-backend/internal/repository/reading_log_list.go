@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+const (
+	DefaultReadingLogPageSize = 50
+	MaxReadingLogPageSize     = 200
+)
+
+// Sort fields accepted by ListReadingLogs
+const (
+	SortByDate      = "date"
+	SortByTitle     = "title"
+	SortByCreatedAt = "created_at"
+)
+
+// ErrInvalidCursor is returned for cursors that can't be decoded or were issued for a different sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ReadingLogQuery filters, sorts and pages a child's reading logs. Zero values mean "no filter".
+type ReadingLogQuery struct {
+	Statuses []string   // any of these statuses; legacy "started" matches "reading"
+	From     *time.Time // logs dated on or after this day
+	To       *time.Time // logs dated on or before this day
+	Author   string     // case-insensitive substring
+	Title    string     // case-insensitive substring
+	SortBy   string     // SortByDate (default), SortByTitle or SortByCreatedAt
+	Desc     bool
+	Limit    int
+	Cursor   string // NextCursor from the previous page
+}
+
+// ReadingLogPage is one page of results plus the total number of logs matching the filters
+type ReadingLogPage struct {
+	Logs       []models.ReadingLog
+	Total      int64
+	NextCursor string // empty on the last page
+}
+
+// readingLogCursor marks the last row of a page: its sort value and ID as the tie-breaker
+type readingLogCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d"`
+	Value  string `json:"v"`
+	ID     uint   `json:"id"`
+}
+
+// ListReadingLogs returns one page of a child's reading logs using keyset pagination,
+// so later pages stay stable while the child keeps logging books.
+func ListReadingLogs(db *gorm.DB, childID uint, query ReadingLogQuery) (ReadingLogPage, error) {
+	var page ReadingLogPage
+
+	if query.SortBy == "" {
+		query.SortBy = SortByDate
+	}
+	column, ok := map[string]string{
+		SortByDate:      "date",
+		SortByTitle:     "LOWER(title)",
+		SortByCreatedAt: "created_at",
+	}[query.SortBy]
+	if !ok {
+		return page, errors.New("unknown sort field " + query.SortBy)
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultReadingLogPageSize
+	}
+	if query.Limit > MaxReadingLogPageSize {
+		query.Limit = MaxReadingLogPageSize
+	}
+
+	filtered := db.Model(&models.ReadingLog{}).Where("child_id = ?", childID)
+	if len(query.Statuses) > 0 {
+		statuses := make([]string, 0, len(query.Statuses)+1)
+		for _, status := range query.Statuses {
+			status = models.NormalizeReadingStatus(status)
+			statuses = append(statuses, status)
+			if status == models.StatusReading {
+				statuses = append(statuses, models.StatusStarted)
+			}
+		}
+		filtered = filtered.Where("status IN ?", statuses)
+	}
+	if query.From != nil {
+		filtered = filtered.Where("date >= ?", query.From.UTC())
+	}
+	if query.To != nil {
+		// To is a whole day, so everything before the start of the next one
+		filtered = filtered.Where("date < ?", query.To.AddDate(0, 0, 1).UTC())
+	}
+	if query.Author != "" {
+		filtered = filtered.Where(`LOWER(author) LIKE ? ESCAPE '\'`, ContainsPattern(query.Author))
+	}
+	if query.Title != "" {
+		filtered = filtered.Where(`LOWER(title) LIKE ? ESCAPE '\'`, ContainsPattern(query.Title))
+	}
+
+	if err := filtered.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+
+	paged := filtered.Session(&gorm.Session{})
+	if query.Cursor != "" {
+		cursor, err := decodeReadingLogCursor(query.Cursor)
+		if err != nil || cursor.SortBy != query.SortBy || cursor.Desc != query.Desc {
+			return page, ErrInvalidCursor
+		}
+		value, err := cursor.sortValue()
+		if err != nil {
+			return page, ErrInvalidCursor
+		}
+		op := ">"
+		if query.Desc {
+			op = "<"
+		}
+		paged = paged.Where("("+column+" "+op+" ?) OR ("+column+" = ? AND id "+op+" ?)", value, value, cursor.ID)
+	}
+
+	direction := " ASC"
+	if query.Desc {
+		direction = " DESC"
+	}
+	// Fetch one extra row to learn whether there is another page
+	if err := paged.Order(column + direction).Order("id" + direction).
+		Limit(query.Limit + 1).Find(&page.Logs).Error; err != nil {
+		return page, err
+	}
+
+	if len(page.Logs) > query.Limit {
+		page.Logs = page.Logs[:query.Limit]
+		page.NextCursor = newReadingLogCursor(query.SortBy, query.Desc, page.Logs[len(page.Logs)-1]).encode()
+	}
+	return page, nil
+}
+
+func newReadingLogCursor(sortBy string, desc bool, last models.ReadingLog) readingLogCursor {
+	cursor := readingLogCursor{SortBy: sortBy, Desc: desc, ID: last.ID}
+	switch sortBy {
+	case SortByTitle:
+		cursor.Value = strings.ToLower(last.Title)
+	case SortByCreatedAt:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = last.Date.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+func (cursor readingLogCursor) encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// sortValue turns the cursor's value back into something comparable with the sort column
+func (cursor readingLogCursor) sortValue() (interface{}, error) {
+	if cursor.SortBy == SortByTitle {
+		return cursor.Value, nil
+	}
+	return time.Parse(time.RFC3339Nano, cursor.Value)
+}
+
+func decodeReadingLogCursor(encoded string) (readingLogCursor, error) {
+	var cursor readingLogCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ContainsPattern returns a LIKE pattern, for use with ESCAPE '\', that matches lowercase text containing
+// text. A % or _ someone typed matches only itself.
+func ContainsPattern(text string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ErrInvalidTransition is returned when a reading log can't move to the requested status
+var ErrInvalidTransition = errors.New("invalid reading status transition")
+
+// StartReadingLog saves a new log in its initial status and records that status as its first transition.
+func StartReadingLog(db *gorm.DB, log *models.ReadingLog, actorID uint) error {
+	log.Status = models.NormalizeReadingStatus(log.Status)
+	if !models.IsInitialReadingStatus(log.Status) {
+		return ErrInvalidTransition
+	}
+	stampStatusDates(log, log.Date)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Book").Create(log).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ReadingLogTransition{
+			ReadingLogID: log.ID,
+			ToStatus:     log.Status,
+			ChangedAt:    log.Date,
+			ChangedByID:  actorID,
+		}).Error
+	})
+}
+
+// OtherReadingLogForBook returns the child's other live log for the same book as log, or nil if there is none.
+// A child keeps one log per book, so a log mustn't be moved onto, or restored alongside, a book they already have.
+func OtherReadingLogForBook(db *gorm.DB, log *models.ReadingLog) (*models.ReadingLog, error) {
+	if log.BookID == nil {
+		return nil, nil
+	}
+	var other models.ReadingLog
+	err := db.Where("child_id = ? AND book_id = ? AND id <> ?", log.ChildID, *log.BookID, log.ID).First(&other).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &other, nil
+}
+
+// TransitionReadingLog moves a log to a new status at the given time and records the change.
+func TransitionReadingLog(db *gorm.DB, log *models.ReadingLog, to string, at time.Time, actorID uint) error {
+	to = models.NormalizeReadingStatus(to)
+	from := models.NormalizeReadingStatus(log.Status)
+	if !models.CanTransition(from, to) {
+		return ErrInvalidTransition
+	}
+
+	log.Status = to
+	log.Date = at
+	stampStatusDates(log, at)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(log).Select("status", "date", "started_at", "completed_at").Updates(log).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ReadingLogTransition{
+			ReadingLogID: log.ID,
+			FromStatus:   from,
+			ToStatus:     to,
+			ChangedAt:    at,
+			ChangedByID:  actorID,
+		}).Error
+	})
+}
+
+// GetReadingLogTransitions returns a log's status history, oldest first
+func GetReadingLogTransitions(db *gorm.DB, logID uint) ([]models.ReadingLogTransition, error) {
+	var transitions []models.ReadingLogTransition
+	err := db.Where("reading_log_id = ?", logID).Order("changed_at ASC, id ASC").Find(&transitions).Error
+	return transitions, err
+}
+
+// stampStatusDates keeps StartedAt and CompletedAt in step with the status the log just entered
+func stampStatusDates(log *models.ReadingLog, at time.Time) {
+	switch log.Status {
+	case models.StatusReading, models.StatusReReading:
+		if log.StartedAt == nil || log.Status == models.StatusReReading {
+			log.StartedAt = &at
+		}
+		log.CompletedAt = nil
+	case models.StatusCompleted:
+		if log.StartedAt == nil {
+			log.StartedAt = &at
+		}
+		log.CompletedAt = &at
+	}
+}
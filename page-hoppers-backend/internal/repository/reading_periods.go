@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Groupings for a period summary
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week" // weeks start on Monday
+	GroupByMonth = "month"
+	GroupByTerm  = "term"
+)
+
+// MaxSummaryPeriods caps how many buckets one summary request can produce
+const MaxSummaryPeriods = 400
+
+var ErrTooManyPeriods = errors.New("too many periods")
+
+// SchoolTerm starts on the first day of StartMonth and runs until the next term starts
+type SchoolTerm struct {
+	Name       string
+	StartMonth time.Month
+}
+
+// SchoolCalendar lists the terms in school-year order; the school year starts with the first term
+type SchoolCalendar struct {
+	Terms []SchoolTerm
+}
+
+// DefaultSchoolCalendar is a September-to-August year in three terms
+var DefaultSchoolCalendar = SchoolCalendar{Terms: []SchoolTerm{
+	{Name: "autumn", StartMonth: time.September},
+	{Name: "spring", StartMonth: time.January},
+	{Name: "summer", StartMonth: time.April},
+}}
+
+// ParseSchoolCalendar reads "name:month,..." pairs such as "autumn:9,spring:1,summer:4"
+func ParseSchoolCalendar(spec string) (SchoolCalendar, error) {
+	var cal SchoolCalendar
+	for _, part := range strings.Split(spec, ",") {
+		name, monthStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		month, err := strconv.Atoi(monthStr)
+		if !ok || name == "" || err != nil || month < 1 || month > 12 {
+			return cal, fmt.Errorf("invalid school term %q, expected name:month", part)
+		}
+		cal.Terms = append(cal.Terms, SchoolTerm{Name: name, StartMonth: time.Month(month)})
+	}
+	if len(cal.Terms) == 0 {
+		return cal, errors.New("no school terms")
+	}
+	return cal, nil
+}
+
+// Period is a half-open time range [Start, End)
+type Period struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// schoolYearStart returns the start of the school year that t falls in
+func (cal SchoolCalendar) schoolYearStart(t time.Time) time.Time {
+	first := cal.Terms[0].StartMonth
+	year := t.Year()
+	if t.Month() < first {
+		year--
+	}
+	return time.Date(year, first, 1, 0, 0, 0, 0, t.Location())
+}
+
+// terms returns the terms of the school year that t falls in, plus the start of the next school year
+func (cal SchoolCalendar) terms(t time.Time) []Period {
+	yearStart := cal.schoolYearStart(t)
+	label := fmt.Sprintf("%d-%02d", yearStart.Year(), (yearStart.Year()+1)%100)
+
+	periods := make([]Period, 0, len(cal.Terms))
+	for i, term := range cal.Terms {
+		start := termStart(yearStart, term.StartMonth)
+		end := yearStart.AddDate(1, 0, 0)
+		if i+1 < len(cal.Terms) {
+			end = termStart(yearStart, cal.Terms[i+1].StartMonth)
+		}
+		periods = append(periods, Period{Label: label + " " + term.Name, Start: start, End: end})
+	}
+	return periods
+}
+
+// termStart places a term's month within the school year beginning at yearStart
+func termStart(yearStart time.Time, month time.Month) time.Time {
+	year := yearStart.Year()
+	if month < yearStart.Month() {
+		year++
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, yearStart.Location())
+}
+
+// termContaining returns the term that t falls in
+func (cal SchoolCalendar) termContaining(t time.Time) Period {
+	terms := cal.terms(t)
+	for _, term := range terms {
+		if !t.Before(term.Start) && t.Before(term.End) {
+			return term
+		}
+	}
+	return terms[len(terms)-1]
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday is 0
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// NamedPeriod resolves names such as "last_month" or "this_school_year" relative to now
+func NamedPeriod(name string, now time.Time, cal SchoolCalendar) (Period, error) {
+	var start, end time.Time
+	switch name {
+	case "this_week":
+		start = startOfWeek(now)
+		end = start.AddDate(0, 0, 7)
+	case "last_week":
+		end = startOfWeek(now)
+		start = end.AddDate(0, 0, -7)
+	case "this_month":
+		start = startOfMonth(now)
+		end = start.AddDate(0, 1, 0)
+	case "last_month":
+		end = startOfMonth(now)
+		start = end.AddDate(0, -1, 0)
+	case "this_year":
+		start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(1, 0, 0)
+	case "last_year":
+		end = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		start = end.AddDate(-1, 0, 0)
+	case "this_term":
+		term := cal.termContaining(now)
+		start, end = term.Start, term.End
+	case "last_term":
+		current := cal.termContaining(now)
+		previous := cal.termContaining(current.Start.AddDate(0, 0, -1))
+		start, end = previous.Start, previous.End
+	case "this_school_year":
+		start = cal.schoolYearStart(now)
+		end = start.AddDate(1, 0, 0)
+	case "last_school_year":
+		end = cal.schoolYearStart(now)
+		start = end.AddDate(-1, 0, 0)
+	default:
+		return Period{}, fmt.Errorf("unknown period %q", name)
+	}
+	return Period{Label: name, Start: start, End: end}, nil
+}
+
+// SplitPeriods cuts [from, to) into buckets by groupBy. The first and last buckets are
+// clipped to the range, so a range starting mid-month still gets a partial first month.
+func SplitPeriods(from, to time.Time, groupBy string, cal SchoolCalendar) ([]Period, error) {
+	var periods []Period
+	for cursor := from; cursor.Before(to); {
+		if len(periods) == MaxSummaryPeriods {
+			return nil, ErrTooManyPeriods
+		}
+
+		var bucket Period
+		switch groupBy {
+		case GroupByDay:
+			start := startOfDay(cursor)
+			bucket = Period{Label: start.Format("2006-01-02"), Start: start, End: start.AddDate(0, 0, 1)}
+		case GroupByWeek:
+			start := startOfWeek(cursor)
+			bucket = Period{Label: start.Format("2006-01-02"), Start: start, End: start.AddDate(0, 0, 7)}
+		case GroupByMonth:
+			start := startOfMonth(cursor)
+			bucket = Period{Label: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)}
+		case GroupByTerm:
+			bucket = cal.termContaining(cursor)
+		default:
+			return nil, fmt.Errorf("unknown grouping %q", groupBy)
+		}
+
+		if bucket.Start.Before(from) {
+			bucket.Start = from
+		}
+		if bucket.End.After(to) {
+			bucket.End = to
+		}
+		periods = append(periods, bucket)
+		cursor = bucket.End
+	}
+	return periods, nil
+}
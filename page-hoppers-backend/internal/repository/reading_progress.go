@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// ReadingProgress totals a log's reading sessions
+type ReadingProgress struct {
+	PagesRead       int      `json:"pages_read"`
+	MinutesRead     int      `json:"minutes_read"`
+	CurrentPage     int      `json:"current_page"`
+	PercentComplete *float64 `json:"percent_complete,omitempty"` // only known when the log has TotalPages
+}
+
+// GetReadingProgress returns session totals keyed by reading log ID, in one query for all logs
+func GetReadingProgress(db *gorm.DB, logs []models.ReadingLog) (map[uint]ReadingProgress, error) {
+	progress := make(map[uint]ReadingProgress, len(logs))
+	if len(logs) == 0 {
+		return progress, nil
+	}
+
+	ids := make([]uint, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.ID)
+	}
+
+	var rows []struct {
+		ReadingLogID uint
+		PagesRead    int
+		MinutesRead  int
+		CurrentPage  int
+	}
+	if err := db.Model(&models.ReadingSession{}).
+		Select("reading_log_id, "+
+			"COALESCE(SUM("+models.SessionPagesReadSQL+"), 0) AS pages_read, "+
+			"COALESCE(SUM(minutes), 0) AS minutes_read, "+
+			"COALESCE(MAX(end_page), 0) AS current_page").
+		Where("reading_log_id IN ?", ids).
+		Group("reading_log_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		progress[row.ReadingLogID] = ReadingProgress{
+			PagesRead:   row.PagesRead,
+			MinutesRead: row.MinutesRead,
+			CurrentPage: row.CurrentPage,
+		}
+	}
+
+	for _, log := range logs {
+		p := progress[log.ID]
+		p.PercentComplete = PercentComplete(log, p.CurrentPage)
+		progress[log.ID] = p
+	}
+
+	return progress, nil
+}
+
+// PercentComplete works out how far through the book the child is, capped at 100.
+// A completed log is always 100; without TotalPages it returns nil.
+func PercentComplete(log models.ReadingLog, currentPage int) *float64 {
+	if models.NormalizeReadingStatus(log.Status) == models.StatusCompleted {
+		full := 100.0
+		return &full
+	}
+	if log.TotalPages == nil || *log.TotalPages <= 0 {
+		return nil
+	}
+
+	percent := float64(currentPage) / float64(*log.TotalPages) * 100
+	if percent > 100 {
+		percent = 100
+	}
+	return &percent
+}
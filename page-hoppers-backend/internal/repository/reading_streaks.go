@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// MaxStreakGraceDays caps the grace setting so a streak still means something
+const MaxStreakGraceDays = 3
+
+// ReadingStreaks counts reading days. Grace days are missed days a streak survives;
+// they keep a streak alive but don't add to its length.
+type ReadingStreaks struct {
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	DaysReadLast30 int    `json:"days_read_last_30"`
+	LastReadOn     string `json:"last_read_on,omitempty"` // YYYY-MM-DD in the family's time zone
+	GraceDays      int    `json:"grace_days"`
+}
+
+// civilDay is a calendar date, held as midnight UTC so day arithmetic ignores DST
+type civilDay time.Time
+
+func toCivilDay(t time.Time, loc *time.Location) civilDay {
+	t = t.In(loc)
+	return civilDay(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+func daysBetween(a, b civilDay) int {
+	return int(time.Time(b).Sub(time.Time(a)).Hours() / 24)
+}
+
+// readingDays returns the distinct calendar days, in now's location and oldest first, up to and including
+// today, on which the child read: any day with a reading session, a book started or a book completed.
+// Deleted logs and logs waiting for approval don't count, and neither do their sessions.
+func readingDays(db *gorm.DB, childID uint, now time.Time) ([]civilDay, error) {
+	var sessionTimes []time.Time
+	if err := db.Model(&models.ReadingSession{}).
+		Joins("JOIN reading_logs ON reading_logs.id = reading_sessions.reading_log_id AND reading_logs.deleted_at IS NULL").
+		Where("reading_sessions.child_id = ? AND reading_sessions.read_at <= ?", childID, now).
+		Scopes(countedSessions).
+		Pluck("reading_sessions.read_at", &sessionTimes).Error; err != nil {
+		return nil, err
+	}
+
+	var logs []models.ReadingLog
+	if err := db.Select("id", "status", "date", "started_at", "completed_at").
+		Where("child_id = ? AND status <> ?", childID, models.StatusWantToRead).
+		Scopes(countedLogs).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	loc, today := now.Location(), toCivilDay(now, now.Location())
+	seen := map[civilDay]bool{}
+	for _, t := range sessionTimes {
+		seen[toCivilDay(t, loc)] = true
+	}
+	for _, log := range logs {
+		if log.StartedAt != nil {
+			seen[toCivilDay(*log.StartedAt, loc)] = true
+		}
+		if log.CompletedAt != nil {
+			seen[toCivilDay(*log.CompletedAt, loc)] = true
+		}
+		// Logs from before statuses were tracked only have their date
+		if log.StartedAt == nil && log.CompletedAt == nil {
+			seen[toCivilDay(log.Date, loc)] = true
+		}
+	}
+
+	days := make([]civilDay, 0, len(seen))
+	for day := range seen {
+		if !time.Time(day).After(time.Time(today)) {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return time.Time(days[i]).Before(time.Time(days[j])) })
+	return days, nil
+}
+
+// GetReadingStreaks works out the child's streaks as of now, which should be in the family's time zone
+func GetReadingStreaks(db *gorm.DB, childID uint, now time.Time, graceDays int) (*ReadingStreaks, error) {
+	if graceDays < 0 {
+		graceDays = 0
+	}
+	if graceDays > MaxStreakGraceDays {
+		graceDays = MaxStreakGraceDays
+	}
+
+	days, err := readingDays(db, childID, now)
+	if err != nil {
+		return nil, err
+	}
+	return computeStreaks(days, toCivilDay(now, now.Location()), graceDays), nil
+}
+
+func computeStreaks(days []civilDay, today civilDay, graceDays int) *ReadingStreaks {
+	streaks := &ReadingStreaks{GraceDays: graceDays}
+	if len(days) == 0 {
+		return streaks
+	}
+
+	// A gap of up to graceDays missed days keeps the run going
+	maxGap := graceDays + 1
+	run := 0
+	for i, day := range days {
+		if i > 0 && daysBetween(days[i-1], day) <= maxGap {
+			run++
+		} else {
+			run = 1
+		}
+		if run > streaks.LongestStreak {
+			streaks.LongestStreak = run
+		}
+		if daysBetween(day, today) < 30 && !time.Time(day).After(time.Time(today)) {
+			streaks.DaysReadLast30++
+		}
+	}
+
+	last := days[len(days)-1]
+	streaks.LastReadOn = time.Time(last).Format("2006-01-02")
+	// The streak is still alive today if the child hasn't yet missed more than the grace allows
+	if daysBetween(last, today) <= maxGap {
+		streaks.CurrentStreak = run
+	}
+	return streaks
+}
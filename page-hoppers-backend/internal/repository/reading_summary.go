@@ -16,37 +16,56 @@ type ReadingSummary struct {
 	TotalBooksReadThisMonth int                `json:"totalBooksReadThisMonth"`
 	TotalBooksReadThisYear  int                `json:"totalBooksReadThisYear"`
 	TotalCompletedBooks     int                `json:"totalCompletedBooks"`
+	TotalsByStatus          map[string]int     `json:"totalsByStatus"`
+	PendingApproval         int                `json:"pendingApproval"` // logs waiting for the parent to approve them
 }
 
 // db *gorm.DB → a pointer to the GORM database connection.
 // childID uint → the unique ID of the child whose reading summary we’re fetching.
-func GetReadingSummary(db *gorm.DB, childID uint) (*ReadingSummary, error) {
+// now → the current time in the family's time zone; "this month" and "this year" are read in its location.
+// Each log is one book, so every count comes from the log's current status.
+// Logs waiting for a parent's approval aren't counted, only tallied in PendingApproval.
+func GetReadingSummary(db *gorm.DB, childID uint, now time.Time) (*ReadingSummary, error) {
 	var logs []models.ReadingLog
 	if err := db.Where("child_id = ?", childID).Order("date desc").Find(&logs).Error; err != nil {
 		return nil, err
 	}
 
-	summary := &ReadingSummary{}
-	now := time.Now()
+	summary := &ReadingSummary{ChildID: childID, TotalsByStatus: map[string]int{}}
 	currentMonth := now.Month()
 	currentYear := now.Year()
 
-	for _, log := range logs {
-		if log.Status == "started" {
-			summary.TotalUncompletedBooks++
+	for i := range logs {
+		log := &logs[i]
+		if log.ApprovalStatus == models.ApprovalPending {
+			summary.PendingApproval++
+		}
+		if !models.CountsTowardTotals(log) {
+			continue
 		}
-		if summary.CurrentBook == nil && log.Status == "started" {
-			summary.CurrentBook = &log
+		status := models.NormalizeReadingStatus(log.Status)
+		summary.TotalsByStatus[status]++
+
+		if models.IsInProgressStatus(status) {
+			summary.TotalUncompletedBooks++
 		}
-		if summary.LastCompletedBook == nil && log.Status == "completed" {
-			summary.LastCompletedBook = &log
+		if summary.CurrentBook == nil && (status == models.StatusReading || status == models.StatusReReading) {
+			summary.CurrentBook = log
 		}
-		if log.Status == "completed" {
+		if status == models.StatusCompleted {
+			completedAt := log.Date
+			if log.CompletedAt != nil {
+				completedAt = *log.CompletedAt
+			}
+			completedAt = completedAt.In(now.Location())
+			if summary.LastCompletedBook == nil {
+				summary.LastCompletedBook = log
+			}
 			summary.TotalCompletedBooks++
-			if log.Date.Month() == currentMonth && log.Date.Year() == currentYear {
+			if completedAt.Month() == currentMonth && completedAt.Year() == currentYear {
 				summary.TotalBooksReadThisMonth++
 			}
-			if log.Date.Year() == currentYear {
+			if completedAt.Year() == currentYear {
 				summary.TotalBooksReadThisYear++
 			}
 		}
@@ -54,3 +73,72 @@ func GetReadingSummary(db *gorm.DB, childID uint) (*ReadingSummary, error) {
 
 	return summary, nil
 }
+
+// PeriodTotals is what a child read in one period
+type PeriodTotals struct {
+	Period
+	BooksCompleted int `json:"books_completed"`
+	PagesRead      int `json:"pages_read"`
+	MinutesRead    int `json:"minutes_read"`
+}
+
+// GetPeriodTotals totals completed books and reading sessions into each period, leaving out deleted logs
+// and logs waiting for approval, and their sessions. Periods must be in order and not overlap, as SplitPeriods returns them.
+func GetPeriodTotals(db *gorm.DB, childID uint, periods []Period) ([]PeriodTotals, error) {
+	totals := make([]PeriodTotals, len(periods))
+	for i, period := range periods {
+		totals[i].Period = period
+	}
+	if len(periods) == 0 {
+		return totals, nil
+	}
+	// Compare in UTC: SQLite compares times as text, so bounds must share the stored offset
+	from, to := periods[0].Start.UTC(), periods[len(periods)-1].End.UTC()
+
+	// completed_at is only set for logs completed since statuses were tracked, so fall back to date
+	var completed []models.ReadingLog
+	if err := db.Where("child_id = ? AND status = ?", childID, models.StatusCompleted).
+		Where("COALESCE(completed_at, date) >= ? AND COALESCE(completed_at, date) < ?", from, to).
+		Scopes(countedLogs).
+		Find(&completed).Error; err != nil {
+		return nil, err
+	}
+	for _, log := range completed {
+		completedAt := log.Date
+		if log.CompletedAt != nil {
+			completedAt = *log.CompletedAt
+		}
+		if i := periodIndex(periods, completedAt); i >= 0 {
+			totals[i].BooksCompleted++
+		}
+	}
+
+	var sessions []models.ReadingSession
+	if err := db.Select("reading_sessions.*").
+		Joins("JOIN reading_logs ON reading_logs.id = reading_sessions.reading_log_id AND reading_logs.deleted_at IS NULL").
+		Where("reading_sessions.child_id = ? AND reading_sessions.read_at >= ? AND reading_sessions.read_at < ?", childID, from, to).
+		Scopes(countedSessions).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		i := periodIndex(periods, session.ReadAt)
+		if i < 0 {
+			continue
+		}
+		totals[i].MinutesRead += session.Minutes
+		totals[i].PagesRead += models.SessionPagesRead(&session)
+	}
+
+	return totals, nil
+}
+
+// periodIndex finds the period containing t, or -1
+func periodIndex(periods []Period, t time.Time) int {
+	for i, period := range periods {
+		if !t.Before(period.Start) && t.Before(period.End) {
+			return i
+		}
+	}
+	return -1
+}
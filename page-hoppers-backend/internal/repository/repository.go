@@ -4,7 +4,6 @@ import (
 	"log"
 	"os"
 
-	"page-hoppers-backend/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -18,7 +17,7 @@ func InitDB() *gorm.DB {
 	}
 
 	// Auto migrate the schema
-	if err := db.AutoMigrate(&models.User{}, &models.ReadingLog{}); err != nil {
+	if err := Migrate(db); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+	_ "time/tzdata" // so time zones resolve on hosts without a zoneinfo database
+
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+)
+
+// LoadTimeZone validates an IANA time zone name. An empty name means UTC.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// familySettingsUser returns the user whose settings apply to userID: their parent for a child,
+// otherwise the user themselves.
+func familySettingsUser(db *gorm.DB, userID uint) (models.User, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return user, err
+	}
+	if user.ParentID != nil {
+		var parent models.User
+		err := db.First(&parent, *user.ParentID).Error
+		return parent, err
+	}
+	return user, nil
+}
+
+// FamilyLocation returns the time zone a user's dates and periods are read in.
+// Children inherit their parent's zone; families that never set one use UTC.
+func FamilyLocation(db *gorm.DB, userID uint) (*time.Location, error) {
+	settings, err := familySettingsUser(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := LoadTimeZone(settings.TimeZone)
+	if err != nil {
+		// A zone that stops resolving (e.g. a renamed tzdata entry) shouldn't break the family's pages
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// FamilyStreakGraceDays returns how many missed days in a row the family lets a streak survive
+func FamilyStreakGraceDays(db *gorm.DB, userID uint) (int, error) {
+	settings, err := familySettingsUser(db, userID)
+	if err != nil {
+		return 0, err
+	}
+	return settings.StreakGraceDays, nil
+}
+
+// FamilyParentID returns the parent whose family userID belongs to: their parent for a child,
+// otherwise the user themselves.
+func FamilyParentID(db *gorm.DB, userID uint) (uint, error) {
+	settings, err := familySettingsUser(db, userID)
+	if err != nil {
+		return 0, err
+	}
+	return settings.ID, nil
+}
@@ -5,29 +5,99 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 	"github.com/gin-contrib/cors"
 
+	"page-hoppers-backend/internal/books"
 	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/mail"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
 )
 
 type Server struct {
+	DB                *gorm.DB
 	Router            *gin.Engine
 	AuthHandler       *handlers.AuthHandler
 	ReadingLogHandler *handlers.ReadingLogHandler
+	BookHandler       *handlers.BookHandler
+	RewardsHandler    *handlers.RewardsHandler
 }
 
 func NewServer(db *gorm.DB) *Server {
 	authHandler := handlers.NewAuthHandler(db, []byte(os.Getenv("JWT_SECRET")))
-	readingLogHandler := handlers.NewReadingLogHandler(db)
+
+	// MAIL_BACKEND is "log" (the default), "file" (writes .eml files to MAIL_DIR) or "smtp". Only an
+	// ENVIRONMENT of development, the default, may print mail to the log.
+	if err := mail.CheckBackend(os.Getenv("MAIL_BACKEND"), os.Getenv("ENVIRONMENT")); err != nil {
+		log.Fatal("Invalid mail settings:", err)
+	}
+	mailer, err := mail.NewMailer(os.Getenv("MAIL_BACKEND"), mail.Config{
+		From:         os.Getenv("MAIL_FROM"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		Dir:          os.Getenv("MAIL_DIR"),
+	})
+	if err != nil {
+		log.Fatal("Invalid mail settings:", err)
+	}
+	authHandler.Mailer = mailer
+	// APP_URL is the frontend that emailed links open, e.g. "https://pagehoppers.example.com"
+	authHandler.AppURL = os.Getenv("APP_URL")
+	// LEGACY_CHILD_ID_LOGIN=true still lets children log in by database ID; deprecated, for clients without device pairing
+	authHandler.LegacyChildIDLogin = os.Getenv("LEGACY_CHILD_ID_LOGIN") == "true"
+
+	// BOOK_PROVIDERS lists metadata sources highest priority first, e.g. "local,openlibrary,googlebooks".
+	// Set it to "local" to run fully offline from the curated catalog.
+	providerNames := os.Getenv("BOOK_PROVIDERS")
+	if providerNames == "" {
+		providerNames = "local,openlibrary"
+	}
+	bookProviders, err := books.NewRegistryFromNames(db, strings.Split(providerNames, ","), books.ProviderConfig{
+		OpenLibraryURL:    os.Getenv("OPEN_LIBRARY_URL"),
+		GoogleBooksURL:    os.Getenv("GOOGLE_BOOKS_URL"),
+		GoogleBooksAPIKey: os.Getenv("GOOGLE_BOOKS_API_KEY"),
+	})
+	if err != nil {
+		log.Fatal("Invalid BOOK_PROVIDERS:", err)
+	}
+
+	// BOOK_CACHE_TTL takes a Go duration such as "12h"; unset or invalid falls back to the default
+	cacheTTL, _ := time.ParseDuration(os.Getenv("BOOK_CACHE_TTL"))
+	bookSearch := books.NewSearchService(db, bookProviders, cacheTTL)
+	bookHandler := handlers.NewBookHandler(db, bookSearch)
+	readingLogHandler := handlers.NewReadingLogHandler(db, bookSearch)
+
+	// SCHOOL_TERMS sets the terms used to group summaries, e.g. "autumn:9,spring:1,summer:4"
+	if terms := os.Getenv("SCHOOL_TERMS"); terms != "" {
+		calendar, err := repository.ParseSchoolCalendar(terms)
+		if err != nil {
+			log.Fatal("Invalid SCHOOL_TERMS:", err)
+		}
+		readingLogHandler.SchoolCalendar = &calendar
+	}
 
 	r := gin.New() // New router without default logger
 	r.Use(gin.Logger()) // logs method, path, status, latency
 	r.Use(gin.Recovery())
 
+	// TRUSTED_PROXIES lists the load balancers allowed to set X-Forwarded-For, e.g. "10.0.0.0/8".
+	// With none the client address is the connection's own, so it can't be forged to dodge login throttling.
+	var proxies []string
+	if list := os.Getenv("TRUSTED_PROXIES"); list != "" {
+		proxies = strings.Split(list, ",")
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
 	// Add CORS middleware
     r.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"http://localhost:3000"}, // your frontend
@@ -38,9 +108,12 @@ func NewServer(db *gorm.DB) *Server {
     }))
 
 	s := &Server{
+		DB:                db,
 		Router:            r,
 		AuthHandler:       authHandler,
 		ReadingLogHandler: readingLogHandler,
+		BookHandler:       bookHandler,
+		RewardsHandler:    handlers.NewRewardsHandler(db),
 	}
 
 	s.registerRoutes()
@@ -57,27 +130,144 @@ func (s *Server) registerRoutes() {
 	// Public routes
 	s.Router.POST("/api/auth/parent/login", s.logHandler("ParentLogin", s.AuthHandler.ParentLogin))
 	s.Router.POST("/api/auth/parent/register", s.logHandler("ParentRegister", s.AuthHandler.ParentRegister))
+	s.Router.POST("/api/auth/teacher/register", s.logHandler("TeacherRegister", s.AuthHandler.TeacherRegister))
+	s.Router.POST("/api/auth/teacher/login", s.logHandler("TeacherLogin", s.AuthHandler.TeacherLogin))
+	s.Router.POST("/api/auth/admin/login", s.logHandler("AdminLogin", s.AuthHandler.AdminLogin))
 	s.Router.POST("/api/auth/child/login", s.logHandler("ChildLogin", s.AuthHandler.ChildLogin))
+	s.Router.POST("/api/auth/family/children", s.logHandler("GetFamilyLoginChildren", s.AuthHandler.GetFamilyLoginChildren))
+	s.Router.POST("/api/auth/refresh", s.logHandler("Refresh", s.AuthHandler.Refresh))
+	s.Router.POST("/api/auth/verify-email/request", s.logHandler("RequestEmailVerification", s.AuthHandler.RequestEmailVerification))
+	s.Router.POST("/api/auth/verify-email/confirm", s.logHandler("ConfirmEmailVerification", s.AuthHandler.ConfirmEmailVerification))
+	s.Router.POST("/api/auth/password-reset/request", s.logHandler("RequestPasswordReset", s.AuthHandler.RequestPasswordReset))
+	s.Router.POST("/api/auth/password-reset/confirm", s.logHandler("ConfirmPasswordReset", s.AuthHandler.ConfirmPasswordReset))
 
 	// Protected routes (with JWT middleware)
 	protected := s.Router.Group("/api")
 	protected.Use(s.authMiddleware())
 
+	// Every protected route names the roles it serves; handlers then check access to the particular child
+	parentOnly := policy.Require(policy.RoleParent)
+	childOnly := policy.Require(policy.RoleChild)
+	anyRole := policy.Require(policy.RoleParent, policy.RoleChild)
+	teacherOnly := policy.Require(policy.RoleTeacher)
+	adminOnly := policy.Require(policy.RoleAdmin)
+	signedIn := policy.Require(policy.RoleParent, policy.RoleChild, policy.RoleTeacher, policy.RoleAdmin)
+
+	// Sessions
+	protected.POST("/auth/logout", signedIn, s.logHandler("Logout", s.AuthHandler.Logout))
+	protected.GET("/auth/sessions", signedIn, s.logHandler("GetSessions", s.AuthHandler.GetSessions))
+	protected.DELETE("/auth/sessions/:id", signedIn, s.logHandler("RevokeSession", s.AuthHandler.RevokeSession))
+
+	// Organization: every account belongs to one; its admins manage it
+	protected.GET("/organization", signedIn, s.logHandler("GetOrganization", s.AuthHandler.GetOrganization))
+	protected.PATCH("/organization", adminOnly, s.logHandler("UpdateOrganization", s.AuthHandler.UpdateOrganization))
+	protected.GET("/organization/members", adminOnly, s.logHandler("GetOrganizationMembers", s.AuthHandler.GetOrganizationMembers))
+	protected.POST("/organization/admins", adminOnly, s.logHandler("CreateOrganizationAdmin", s.AuthHandler.CreateOrganizationAdmin))
+	protected.GET("/organization/classrooms", adminOnly, s.logHandler("GetOrganizationClassrooms", s.AuthHandler.GetOrganizationClassrooms))
+	protected.GET("/organization/join-codes", adminOnly, s.logHandler("GetOrganizationJoinCodes", s.AuthHandler.GetOrganizationJoinCodes))
+	protected.POST("/organization/join-codes/:role", adminOnly, s.logHandler("RotateOrganizationJoinCode", s.AuthHandler.RotateOrganizationJoinCode))
+
+	// Admin support tools: every request is recorded in the organization's audit log
+	protected.GET("/admin/users", adminOnly, s.logHandler("AdminSearchUsers", s.AuthHandler.AdminSearchUsers))
+	protected.GET("/admin/users/:id/family", adminOnly, s.logHandler("AdminGetFamily", s.AuthHandler.AdminGetFamily))
+	protected.POST("/admin/users/:id/reset-credentials", adminOnly, s.logHandler("AdminResetCredentials", s.AuthHandler.AdminResetCredentials))
+	protected.POST("/admin/users/:id/deactivate", adminOnly, s.logHandler("AdminDeactivateUser", s.AuthHandler.AdminDeactivateUser))
+	protected.POST("/admin/users/:id/reactivate", adminOnly, s.logHandler("AdminReactivateUser", s.AuthHandler.AdminReactivateUser))
+	protected.POST("/admin/users/:id/restore", adminOnly, s.logHandler("AdminRestoreChild", s.AuthHandler.AdminRestoreChild))
+	protected.POST("/admin/users/:id/merge", adminOnly, s.logHandler("AdminMergeUsers", s.AuthHandler.AdminMergeUsers))
+	protected.POST("/admin/users/:id/impersonate", adminOnly, s.logHandler("AdminImpersonateUser", s.AuthHandler.AdminImpersonateUser))
+	protected.GET("/admin/audit", adminOnly, s.logHandler("GetAdminAuditLog", s.AuthHandler.GetAdminAuditLog))
+
 	// Children
-	protected.GET("/children", s.logHandler("GetChildren", s.AuthHandler.GetChildren))
-	protected.POST("/children", s.logHandler("CreateChild", s.AuthHandler.CreateChild))
+	protected.GET("/children", parentOnly, s.logHandler("GetChildren", s.AuthHandler.GetChildren))
+	protected.POST("/children", parentOnly, s.logHandler("CreateChild", s.AuthHandler.CreateChild))
+	protected.PATCH("/children/:id/settings", parentOnly, s.logHandler("UpdateChildSettings", s.AuthHandler.UpdateChildSettings))
+	protected.POST("/children/:id/unlock", parentOnly, s.logHandler("UnlockChild", s.AuthHandler.UnlockChild))
+
+	// Guardians: other adults who share a child
+	protected.GET("/children/:id/guardians", parentOnly, s.logHandler("GetGuardians", s.AuthHandler.GetGuardians))
+	protected.POST("/children/:id/guardians/invites", parentOnly, s.logHandler("InviteGuardian", s.AuthHandler.InviteGuardian))
+	protected.PATCH("/children/:id/guardians/:userId", parentOnly, s.logHandler("UpdateGuardian", s.AuthHandler.UpdateGuardian))
+	protected.DELETE("/children/:id/guardians/:userId", parentOnly, s.logHandler("RemoveGuardian", s.AuthHandler.RemoveGuardian))
+	protected.POST("/guardian-invites/accept", parentOnly, s.logHandler("AcceptGuardianInvite", s.AuthHandler.AcceptGuardianInvite))
+	protected.DELETE("/guardian-invites/:id", parentOnly, s.logHandler("RevokeGuardianInvite", s.AuthHandler.RevokeGuardianInvite))
+
+	// Classrooms: teachers see the children guardians have added, read-only
+	protected.GET("/classrooms", teacherOnly, s.logHandler("GetClassrooms", s.ReadingLogHandler.GetClassrooms))
+	protected.POST("/classrooms", teacherOnly, s.logHandler("CreateClassroom", s.ReadingLogHandler.CreateClassroom))
+	protected.POST("/classrooms/:id/join-code", teacherOnly, s.logHandler("RotateClassroomJoinCode", s.ReadingLogHandler.RotateClassroomJoinCode))
+	protected.GET("/classrooms/:id/students", teacherOnly, s.logHandler("GetClassroomStudents", s.ReadingLogHandler.GetClassroomStudents))
+	protected.GET("/classrooms/:id/summary", teacherOnly, s.logHandler("GetClassroomSummary", s.ReadingLogHandler.GetClassroomSummary))
+	protected.GET("/children/:id/classrooms", parentOnly, s.logHandler("GetChildClassrooms", s.ReadingLogHandler.GetChildClassrooms))
+	protected.POST("/children/:id/classrooms", parentOnly, s.logHandler("JoinClassroom", s.ReadingLogHandler.JoinClassroom))
+	protected.DELETE("/children/:id/classrooms/:classroomId", parentOnly, s.logHandler("LeaveClassroom", s.ReadingLogHandler.LeaveClassroom))
+
+	// Paired family devices
+	protected.GET("/family/devices", parentOnly, s.logHandler("GetFamilyDevices", s.AuthHandler.GetFamilyDevices))
+	protected.POST("/family/devices", parentOnly, s.logHandler("PairDevice", s.AuthHandler.PairDevice))
+	protected.DELETE("/family/devices/:id", parentOnly, s.logHandler("UnpairDevice", s.AuthHandler.UnpairDevice))
+
+	// Notifications
+	protected.GET("/notifications", signedIn, s.logHandler("GetNotifications", s.AuthHandler.GetNotifications))
+	protected.POST("/notifications/:id/read", signedIn, s.logHandler("MarkNotificationRead", s.AuthHandler.MarkNotificationRead))
+
+	// Family settings
+	protected.GET("/parent/settings", parentOnly, s.logHandler("GetParentSettings", s.AuthHandler.GetParentSettings))
+	protected.PATCH("/parent/settings", parentOnly, s.logHandler("UpdateParentSettings", s.AuthHandler.UpdateParentSettings))
 
 	// Reading logs
-	protected.POST("/reading-logs", s.logHandler("CreateReadingLog", s.ReadingLogHandler.CreateReadingLog))
-	protected.GET("/reading-logs", s.logHandler("GetReadingLogs", s.ReadingLogHandler.GetReadingLogs))
-	protected.GET("/children/reading-logs", s.logHandler("GetChildReadingLogs", s.ReadingLogHandler.GetChildReadingLogs))
+	protected.POST("/reading-logs", childOnly, s.logHandler("CreateReadingLog", s.ReadingLogHandler.CreateReadingLog))
+	protected.POST("/reading-logs/by-isbn", childOnly, s.logHandler("CreateReadingLogByISBN", s.ReadingLogHandler.CreateReadingLogByISBN))
+	protected.GET("/reading-logs", childOnly, s.logHandler("GetReadingLogs", s.ReadingLogHandler.GetReadingLogs))
+	protected.GET("/children/reading-logs", parentOnly, s.logHandler("GetChildReadingLogs", s.ReadingLogHandler.GetChildReadingLogs))
+	protected.PATCH("/reading-logs/:id", anyRole, s.logHandler("UpdateReadingLog", s.ReadingLogHandler.UpdateReadingLog))
+	protected.DELETE("/reading-logs/:id", anyRole, s.logHandler("DeleteReadingLog", s.ReadingLogHandler.DeleteReadingLog))
+	protected.GET("/reading-logs/trash", anyRole, s.logHandler("GetDeletedReadingLogs", s.ReadingLogHandler.GetDeletedReadingLogs))
+	protected.POST("/reading-logs/:id/restore", anyRole, s.logHandler("RestoreReadingLog", s.ReadingLogHandler.RestoreReadingLog))
+	protected.POST("/reading-logs/:id/transitions", anyRole, s.logHandler("TransitionReadingLog", s.ReadingLogHandler.TransitionReadingLog))
+	protected.GET("/reading-logs/:id/transitions", anyRole, s.logHandler("GetReadingLogTransitions", s.ReadingLogHandler.GetReadingLogTransitions))
+
+	// Reading log approval
+	protected.GET("/reading-logs/pending", parentOnly, s.logHandler("GetPendingReadingLogs", s.ReadingLogHandler.GetPendingReadingLogs))
+	protected.POST("/reading-logs/:id/approve", parentOnly, s.logHandler("ApproveReadingLog", s.ReadingLogHandler.ApproveReadingLog))
+	protected.POST("/reading-logs/:id/reject", parentOnly, s.logHandler("RejectReadingLog", s.ReadingLogHandler.RejectReadingLog))
+
+	// Reading sessions
+	protected.GET("/reading-logs/:id/sessions", anyRole, s.logHandler("GetReadingSessions", s.ReadingLogHandler.GetReadingSessions))
+	protected.POST("/reading-logs/:id/sessions", anyRole, s.logHandler("CreateReadingSession", s.ReadingLogHandler.CreateReadingSession))
+	protected.PATCH("/reading-logs/:id/sessions/:sessionId", anyRole, s.logHandler("UpdateReadingSession", s.ReadingLogHandler.UpdateReadingSession))
+	protected.DELETE("/reading-logs/:id/sessions/:sessionId", anyRole, s.logHandler("DeleteReadingSession", s.ReadingLogHandler.DeleteReadingSession))
+
+	// Books
+	protected.GET("/books/search", anyRole, s.logHandler("SearchBooks", s.BookHandler.SearchBooks))
 
 	// Reading Summary
-	protected.GET("/children/:id/summary", s.logHandler(
-		"GetReadingSummary",
-		s.ReadingLogHandler.GetReadingSummary,
+	protected.GET("/children/:id/summary", anyRole, s.logHandler("GetReadingSummary", s.ReadingLogHandler.GetReadingSummary))
+	protected.GET("/children/:id/streaks", anyRole, s.logHandler("GetReadingStreaks", s.ReadingLogHandler.GetReadingStreaks))
+
+	// Achievements
+	protected.GET("/achievements", anyRole, s.logHandler("GetAchievementRules", s.ReadingLogHandler.GetAchievementRules))
+	protected.GET("/children/:id/achievements", anyRole, s.logHandler("GetChildAchievements", s.ReadingLogHandler.GetChildAchievements))
+
+	// Goals
+	protected.GET("/children/:id/goals", anyRole, s.logHandler("GetGoals", s.ReadingLogHandler.GetGoals))
+	protected.POST("/children/:id/goals", parentOnly, s.logHandler("CreateGoal", s.ReadingLogHandler.CreateGoal))
+	protected.PATCH("/children/:id/goals/:goalId", parentOnly, s.logHandler("UpdateGoal", s.ReadingLogHandler.UpdateGoal))
+	protected.DELETE("/children/:id/goals/:goalId", parentOnly, s.logHandler("DeleteGoal", s.ReadingLogHandler.DeleteGoal))
 
-))
+	// Points and rewards
+	protected.GET("/point-rules", anyRole, s.logHandler("GetPointRules", s.RewardsHandler.GetPointRules))
+	protected.PUT("/point-rules", parentOnly, s.logHandler("SetPointRules", s.RewardsHandler.SetPointRules))
+	protected.GET("/children/:id/points", anyRole, s.logHandler("GetChildPoints", s.RewardsHandler.GetChildPoints))
+	protected.POST("/children/:id/points/adjustments", parentOnly, s.logHandler("AdjustChildPoints", s.RewardsHandler.AdjustChildPoints))
+	protected.GET("/rewards", anyRole, s.logHandler("GetRewards", s.RewardsHandler.GetRewards))
+	protected.POST("/rewards", parentOnly, s.logHandler("CreateReward", s.RewardsHandler.CreateReward))
+	protected.PATCH("/rewards/:id", parentOnly, s.logHandler("UpdateReward", s.RewardsHandler.UpdateReward))
+	protected.DELETE("/rewards/:id", parentOnly, s.logHandler("DeleteReward", s.RewardsHandler.DeleteReward))
+	protected.POST("/rewards/:id/redeem", childOnly, s.logHandler("RedeemReward", s.RewardsHandler.RedeemReward))
+	protected.GET("/redemptions", anyRole, s.logHandler("GetRedemptions", s.RewardsHandler.GetRedemptions))
+	protected.POST("/redemptions/:id/approve", parentOnly, s.logHandler("ApproveRedemption", s.RewardsHandler.ApproveRedemption))
+	protected.POST("/redemptions/:id/reject", parentOnly, s.logHandler("RejectRedemption", s.RewardsHandler.RejectRedemption))
 }
 
 // logHandler wraps a handler to log entry for easier debugging
@@ -125,12 +315,56 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			userID := uint(claims["user_id"].(float64))
-			role := claims["role"].(string)
-			log.Printf("Authenticated user_id=%d role=%s", userID, role)
+		claims, _ := token.Claims.(jwt.MapClaims)
+		userIDClaim, hasUser := claims["user_id"].(float64)
+		role, hasRole := claims["role"].(string)
+		orgIDClaim, hasOrg := claims["org_id"].(float64)
+		if hasUser && hasRole && hasOrg {
+			userID, orgID := uint(userIDClaim), uint(orgIDClaim)
+
+			// Every token belongs to a session; a signed-out or revoked session's tokens stop working at once
+			sid, _ := claims["sid"].(float64)
+			active, err := repository.IsAuthSessionActive(s.DB, uint(sid), userID, time.Now())
+			if err != nil || !active {
+				log.Printf("Rejected token for user_id=%d: session %d is not active", userID, uint(sid))
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has ended"})
+				return
+			}
+
+			// The tenant comes from the token, but only while the account is still in that organization
+			if current, err := repository.UserOrganizationID(s.DB, userID); err != nil || current != orgID {
+				log.Printf("Rejected token for user_id=%d: not in organization %d", userID, orgID)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has ended"})
+				return
+			}
+
+			// An admin's support token only reads, and only while that admin still runs the organization.
+			// Every request made with one, refused or not, goes in the organization's audit log.
+			if adminID, impersonated := claims["impersonated_by"].(float64); impersonated {
+				detail := c.Request.Method + " " + c.Request.URL.RequestURI()
+				if err := repository.RecordAdminAction(s.DB, orgID, uint(adminID), models.AdminActionViewAsUser, userID, detail); err != nil {
+					log.Printf("Failed to audit %s by admin %d as user_id=%d: %v", detail, uint(adminID), userID, err)
+					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not record admin action"})
+					return
+				}
+				if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+					log.Printf("Refused %s %s for user_id=%d impersonated by admin %d", c.Request.Method, c.Request.URL.Path, userID, uint(adminID))
+					c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Support sessions are read-only"})
+					return
+				}
+				if active, err := repository.IsActiveAdmin(s.DB, uint(adminID), orgID); err != nil || !active {
+					log.Printf("Rejected token for user_id=%d: impersonating admin %d is not active", userID, uint(adminID))
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has ended"})
+					return
+				}
+				c.Set("impersonated_by", uint(adminID))
+			}
+
+			log.Printf("Authenticated user_id=%d role=%s org_id=%d", userID, role, orgID)
 			c.Set("user_id", userID)
 			c.Set("role", role)
+			c.Set("organization_id", orgID)
+			c.Set("session_id", uint(sid))
 		} else {
 			log.Println("Invalid token claims")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
@@ -139,4 +373,4 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// Creates an organization (a school or library) and its first admin, who can then add more admins
+// through the API. For example:
+//
+//	go run ./scripts/create_organization -name "Maple Primary" -slug maple \
+//		-admin-name "Ms Hill" -admin-email hill@maple.example -admin-password changeme
+func main() {
+	name := flag.String("name", "", "organization name")
+	slug := flag.String("slug", "", "organization slug, given when registering")
+	adminName := flag.String("admin-name", "", "first admin's name")
+	adminEmail := flag.String("admin-email", "", "first admin's email")
+	adminPassword := flag.String("admin-password", "", "first admin's starting password")
+	flag.Parse()
+
+	if *name == "" || *slug == "" || *adminName == "" || *adminEmail == "" || *adminPassword == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// Load environment variables from .env file
+	godotenv.Load()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		fmt.Println("DATABASE_URL environment variable not set")
+		os.Exit(1)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		fmt.Printf("Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(*adminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		panic("failed to hash password")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		org, err := repository.CreateOrganization(tx, *name, *slug)
+		if err != nil {
+			return err
+		}
+		admin := models.User{
+			OrganizationID: org.ID,
+			Name:           *adminName,
+			Email:          *adminEmail,
+			Password:       string(hashed),
+			Role:           "admin",
+		}
+		return tx.Create(&admin).Error
+	})
+	if err != nil {
+		fmt.Printf("Failed to create organization: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Organization %q created with slug %q; admin %s can log in at /api/auth/admin/login\n", *name, *slug, *adminEmail)
+}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
 )
 
 func main() {
@@ -32,11 +33,18 @@ func main() {
 		panic("failed to hash password")
 	}
 
+	// Run scripts/migrate.go first so the default organization exists
+	org, err := repository.FindOrganization(db, "")
+	if err != nil {
+		panic("default organization not found; run the migration first")
+	}
+
 	parent := models.User{
-		Name:     "testparent",
-		Email:    "parent@example.com",
-		Password: string(hashed),
-		Role:     "parent",
+		OrganizationID: org.ID,
+		Name:           "testparent",
+		Email:          "parent@example.com",
+		Password:       string(hashed),
+		Role:           "parent",
 	}
 
 	if err := db.Where(models.User{Email: parent.Email}).FirstOrCreate(&parent).Error; err != nil {
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"page-hoppers-backend/internal/books"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// Adds a book to the curated catalog, which the "local" book provider searches with no network access.
+// The catalog is shared by every organization, so it is managed here rather than through the API. For example:
+//
+//	go run ./scripts/curate_book -title "Matilda" -author "Roald Dahl" -isbn 9780142410370 -pages 240
+//	go run ./scripts/curate_book -title "The Bad Beginning" -author "Lemony Snicket" -series "A Series of Unfortunate Events"
+//	go run ./scripts/curate_book -title "Matilda" -isbn 9780142410370 -remove
+func main() {
+	title := flag.String("title", "", "book title")
+	author := flag.String("author", "", "book author")
+	isbn := flag.String("isbn", "", "ISBN-10 or ISBN-13")
+	openLibraryKey := flag.String("open-library-key", "", "Open Library work key, e.g. /works/OL45804W")
+	pages := flag.Int("pages", 0, "page count")
+	series := flag.String("series", "", `series the book belongs to, e.g. "Harry Potter"`)
+	remove := flag.Bool("remove", false, "take the book out of the curated catalog instead")
+	flag.Parse()
+
+	if *title == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	book := models.Book{Title: *title, Author: *author, OpenLibraryKey: *openLibraryKey, PageCount: *pages, Series: *series}
+	if *isbn != "" {
+		isbn13, err := books.ParseISBN(*isbn)
+		if err != nil {
+			fmt.Printf("Invalid ISBN: %v\n", err)
+			os.Exit(1)
+		}
+		book.ISBN = isbn13
+	}
+
+	// Load environment variables from .env file
+	godotenv.Load()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		fmt.Println("DATABASE_URL environment variable not set")
+		os.Exit(1)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		fmt.Printf("Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+
+	curated, err := repository.CurateBook(db, book, !*remove)
+	if err != nil {
+		fmt.Printf("Failed to curate book: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *remove {
+		fmt.Printf("%q (book %d) is no longer in the curated catalog\n", curated.Title, curated.ID)
+		return
+	}
+	fmt.Printf("%q (book %d) is in the curated catalog\n", curated.Title, curated.ID)
+}
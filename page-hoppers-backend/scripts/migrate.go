@@ -5,9 +5,9 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
-	"page-hoppers-backend/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"page-hoppers-backend/internal/repository"
 )
 
 func main() {
@@ -29,13 +29,35 @@ func main() {
 
 	// Auto migrate the schema
 	fmt.Println("Migrating database...")
-	if err := db.AutoMigrate(&models.User{}, &models.ReadingLog{}); err != nil {
+	if err := repository.Migrate(db); err != nil {
 		fmt.Printf("Failed to migrate database: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("Database migration completed successfully!")
 	fmt.Println("Tables created:")
+	fmt.Println("- organizations")
 	fmt.Println("- users")
+	fmt.Println("- admin_audit_entries")
+	fmt.Println("- auth_sessions")
+	fmt.Println("- email_tokens")
+	fmt.Println("- login_throttles")
+	fmt.Println("- notifications")
+	fmt.Println("- family_devices")
+	fmt.Println("- guardianships")
+	fmt.Println("- guardian_invites")
+	fmt.Println("- classrooms")
+	fmt.Println("- classroom_members")
+	fmt.Println("- books")
+	fmt.Println("- book_search_caches")
 	fmt.Println("- reading_logs")
-} 
\ No newline at end of file
+	fmt.Println("- reading_log_transitions")
+	fmt.Println("- reading_sessions")
+	fmt.Println("- goals")
+	fmt.Println("- achievement_rules")
+	fmt.Println("- child_achievements")
+	fmt.Println("- point_rules")
+	fmt.Println("- points_ledger_entries")
+	fmt.Println("- rewards")
+	fmt.Println("- reward_redemptions")
+}
@@ -15,6 +15,7 @@ func TestChildLoginSuccess(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create a parent and a child
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "parentpass")
@@ -23,7 +24,7 @@ func TestChildLoginSuccess(t *testing.T) {
 	// Prepare login payload
 	payload := handlers.ChildLoginRequest{
 		ChildID: child.ID,
-		PIN:    "1234",
+		PIN:     "1234",
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -36,7 +37,7 @@ func TestChildLoginSuccess(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Assertions
 	if w.Code != http.StatusOK {
@@ -63,6 +64,7 @@ func TestChildLoginInvalidPIN(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create a parent and a child
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "parentpass")
@@ -71,7 +73,7 @@ func TestChildLoginInvalidPIN(t *testing.T) {
 	// Prepare login payload with wrong PIN
 	payload := handlers.ChildLoginRequest{
 		ChildID: child.ID,
-		PIN:    "9999", // Wrong PIN
+		PIN:     "9999", // Wrong PIN
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -84,7 +86,7 @@ func TestChildLoginInvalidPIN(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Should get unauthorized status
 	if w.Code != http.StatusUnauthorized {
@@ -97,11 +99,12 @@ func TestChildLoginNonExistentChild(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Prepare login payload with non-existent child ID
 	payload := handlers.ChildLoginRequest{
 		ChildID: 999, // Non-existent child ID
-		PIN:    "1234",
+		PIN:     "1234",
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -114,7 +117,7 @@ func TestChildLoginNonExistentChild(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Should get unauthorized status
 	if w.Code != http.StatusUnauthorized {
@@ -127,6 +130,7 @@ func TestChildLoginMissingFields(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Test with missing ChildID
 	payload1 := map[string]string{
@@ -138,7 +142,7 @@ func TestChildLoginMissingFields(t *testing.T) {
 	req1.Header.Set("Content-Type", "application/json")
 	w1 := httptest.NewRecorder()
 
-	handler.ChildLogin(w1, req1)
+	serve(handler.ChildLogin, w1, req1)
 
 	// Should get unauthorized status (empty childId treated as invalid credentials)
 	if w1.Code != http.StatusUnauthorized {
@@ -155,7 +159,7 @@ func TestChildLoginMissingFields(t *testing.T) {
 	req2.Header.Set("Content-Type", "application/json")
 	w2 := httptest.NewRecorder()
 
-	handler.ChildLogin(w2, req2)
+	serve(handler.ChildLogin, w2, req2)
 
 	// Should get unauthorized status (empty pin treated as invalid credentials)
 	if w2.Code != http.StatusUnauthorized {
@@ -168,6 +172,7 @@ func TestChildLoginParentUser(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create a parent user
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "parentpass")
@@ -175,14 +180,14 @@ func TestChildLoginParentUser(t *testing.T) {
 	// Try to login as child with parent ID
 	payload := handlers.ChildLoginRequest{
 		ChildID: parent.ID,
-		PIN:    "parentpass",
+		PIN:     "parentpass",
 	}
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/child/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Should get unauthorized status because parent role doesn't match child
 	if w.Code != http.StatusUnauthorized {
@@ -195,6 +200,7 @@ func TestChildLoginEmptyPIN(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create a parent and a child
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "parentpass")
@@ -203,14 +209,14 @@ func TestChildLoginEmptyPIN(t *testing.T) {
 	// Prepare login payload with empty PIN
 	payload := handlers.ChildLoginRequest{
 		ChildID: child.ID,
-		PIN:    "", // Empty PIN
+		PIN:     "", // Empty PIN
 	}
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/child/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Should get unauthorized status
 	if w.Code != http.StatusUnauthorized {
@@ -223,13 +229,14 @@ func TestChildLoginMalformedJSON(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create request with malformed JSON
 	req := httptest.NewRequest("POST", "/api/auth/child/login", bytes.NewReader([]byte(`{"childId": 1, "pin": "1234"`))) // Missing closing brace
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ChildLogin(w, req)
+	serve(handler.ChildLogin, w, req)
 
 	// Should get bad request status
 	if w.Code != http.StatusBadRequest {
@@ -242,6 +249,7 @@ func TestChildLoginMultipleChildren(t *testing.T) {
 	// Setup
 	db := setupTestDB()
 	handler := handlers.NewAuthHandler(db, []byte("test-secret"))
+	handler.LegacyChildIDLogin = true // these cover the deprecated login by childId
 
 	// Create a parent and multiple children
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "parentpass")
@@ -251,14 +259,14 @@ func TestChildLoginMultipleChildren(t *testing.T) {
 	// Test login for first child
 	payload1 := handlers.ChildLoginRequest{
 		ChildID: child1.ID,
-		PIN:    "1111",
+		PIN:     "1111",
 	}
 	body1, _ := json.Marshal(payload1)
 	req1 := httptest.NewRequest("POST", "/api/auth/child/login", bytes.NewReader(body1))
 	req1.Header.Set("Content-Type", "application/json")
 	w1 := httptest.NewRecorder()
 
-	handler.ChildLogin(w1, req1)
+	serve(handler.ChildLogin, w1, req1)
 
 	if w1.Code != http.StatusOK {
 		t.Errorf("expected status 200 for child1, got %d", w1.Code)
@@ -267,14 +275,14 @@ func TestChildLoginMultipleChildren(t *testing.T) {
 	// Test login for second child
 	payload2 := handlers.ChildLoginRequest{
 		ChildID: child2.ID,
-		PIN:    "2222",
+		PIN:     "2222",
 	}
 	body2, _ := json.Marshal(payload2)
 	req2 := httptest.NewRequest("POST", "/api/auth/child/login", bytes.NewReader(body2))
 	req2.Header.Set("Content-Type", "application/json")
 	w2 := httptest.NewRecorder()
 
-	handler.ChildLogin(w2, req2)
+	serve(handler.ChildLogin, w2, req2)
 
 	if w2.Code != http.StatusOK {
 		t.Errorf("expected status 200 for child2, got %d", w2.Code)
@@ -288,4 +296,4 @@ func TestChildLoginMultipleChildren(t *testing.T) {
 	if response1.Token == response2.Token {
 		t.Error("expected different tokens for different children")
 	}
-} 
\ No newline at end of file
+}
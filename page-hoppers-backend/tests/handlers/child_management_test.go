@@ -2,14 +2,13 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"page-hoppers-backend/internal/models"
 	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
 )
 
 // TestCreateChildSuccess tests creating a child for a parent
@@ -23,9 +22,9 @@ func TestCreateChildSuccess(t *testing.T) {
 
 	// Prepare child creation payload
 	payload := handlers.CreateChildRequest{
-		Name:     "Child One",
-		Age:      8,
-		PIN:      "1234",
+		Name: "Child One",
+		Age:  8,
+		PIN:  "1234",
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -35,12 +34,10 @@ func TestCreateChildSuccess(t *testing.T) {
 	// Create request and set parent context
 	req := httptest.NewRequest("POST", "/api/children", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
-	ctx := context.WithValue(req.Context(), "user_id", parent.ID)
-	req = req.WithContext(ctx)
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.CreateChild(w, req)
+	serveAs(db, parent.ID, "parent", "/api/children", handler.CreateChild, w, req)
 
 	// Assertions
 	if w.Code != http.StatusOK {
@@ -52,8 +49,8 @@ func TestCreateChildSuccess(t *testing.T) {
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
-	if response.Username != payload.Username {
-		t.Errorf("expected username %s, got %s", payload.Username, response.Username)
+	if response.Name != payload.Name {
+		t.Errorf("expected name %s, got %s", payload.Name, response.Name)
 	}
 	if response.ID == 0 {
 		t.Error("expected non-zero child ID")
@@ -81,48 +78,42 @@ func TestCreateChildValidation(t *testing.T) {
 	// Create a parent user
 	parent := createTestParent(db, "Test Parent", "parent@example.com", "testpassword123")
 
-	// Missing username
+	// Missing name
 	payload := handlers.CreateChildRequest{
-		Name: "Child One", Age: 8, PIN: "1234",
+		Age: 8, PIN: "1234",
 	}
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/children", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
-	ctx := context.WithValue(req.Context(), "user_id", parent.ID)
-	req = req.WithContext(ctx)
 	w := httptest.NewRecorder()
-	handler.CreateChild(w, req)
+	serveAs(db, parent.ID, "parent", "/api/children", handler.CreateChild, w, req)
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected status 400, got %d", w.Code)
 	}
 
 	// Missing PIN
 	payload2 := handlers.CreateChildRequest{
-		Username: "child2", Name: "Child Two", Age: 8,
+		Name: "Child Two", Age: 8,
 	}
 	body2, _ := json.Marshal(payload2)
 	req2 := httptest.NewRequest("POST", "/api/children", bytes.NewReader(body2))
 	req2.Header.Set("Content-Type", "application/json")
-	ctx2 := context.WithValue(req2.Context(), "user_id", parent.ID)
-	req2 = req2.WithContext(ctx2)
 	w2 := httptest.NewRecorder()
-	handler.CreateChild(w2, req2)
+	serveAs(db, parent.ID, "parent", "/api/children", handler.CreateChild, w2, req2)
 	if w2.Code != http.StatusBadRequest {
 		t.Errorf("expected status 400, got %d", w2.Code)
 	}
 
 	// Age <= 0
 	payload3 := handlers.CreateChildRequest{
-		Username: "child3", Name: "Child Three", Age: 0, PIN: "1234",
+		Name: "Child Three", Age: 0, PIN: "1234",
 	}
 	body3, _ := json.Marshal(payload3)
 	req3 := httptest.NewRequest("POST", "/api/children", bytes.NewReader(body3))
 	req3.Header.Set("Content-Type", "application/json")
-	ctx3 := context.WithValue(req3.Context(), "user_id", parent.ID)
-	req3 = req3.WithContext(ctx3)
 	w3 := httptest.NewRecorder()
-	handler.CreateChild(w3, req3)
+	serveAs(db, parent.ID, "parent", "/api/children", handler.CreateChild, w3, req3)
 	if w3.Code != http.StatusBadRequest {
 		t.Errorf("expected status 400, got %d", w3.Code)
 	}
-} 
\ No newline at end of file
+}
@@ -7,8 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"page-hoppers-backend/internal/models"
 	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
 )
 
 // TestParentRegister tests the parent registration endpoint
@@ -23,7 +23,7 @@ func TestParentRegister(t *testing.T) {
 		Email:    "parent@example.com",
 		Password: "testpassword123",
 	}
-	
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		t.Fatalf("failed to marshal payload: %v", err)
@@ -35,7 +35,7 @@ func TestParentRegister(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.ParentRegister(w, req)
+	serve(handler.ParentRegister, w, req)
 
 	// Assertions
 	if w.Code != http.StatusCreated {
@@ -62,8 +62,8 @@ func TestParentRegister(t *testing.T) {
 		t.Errorf("expected role 'parent', got %s", user.Role)
 	}
 
-	if user.Username != payload.Name {
-		t.Errorf("expected username %s, got %s", payload.Name, user.Username)
+	if user.Name != payload.Name {
+		t.Errorf("expected name %s, got %s", payload.Name, user.Name)
 	}
 }
 
@@ -79,12 +79,12 @@ func TestParentRegisterDuplicateEmail(t *testing.T) {
 		Email:    "parent@example.com",
 		Password: "testpassword123",
 	}
-	
+
 	body1, _ := json.Marshal(payload1)
 	req1 := httptest.NewRequest("POST", "/api/auth/parent/register", bytes.NewReader(body1))
 	req1.Header.Set("Content-Type", "application/json")
 	w1 := httptest.NewRecorder()
-	handler.ParentRegister(w1, req1)
+	serve(handler.ParentRegister, w1, req1)
 
 	// Try to create second user with same email
 	payload2 := handlers.ParentRegisterRequest{
@@ -92,12 +92,12 @@ func TestParentRegisterDuplicateEmail(t *testing.T) {
 		Email:    "parent@example.com", // Same email
 		Password: "testpassword456",
 	}
-	
+
 	body2, _ := json.Marshal(payload2)
 	req2 := httptest.NewRequest("POST", "/api/auth/parent/register", bytes.NewReader(body2))
 	req2.Header.Set("Content-Type", "application/json")
 	w2 := httptest.NewRecorder()
-	handler.ParentRegister(w2, req2)
+	serve(handler.ParentRegister, w2, req2)
 
 	// Should get conflict status
 	if w2.Code != http.StatusConflict {
@@ -117,13 +117,13 @@ func TestParentRegisterMissingFields(t *testing.T) {
 		"password": "testpassword123",
 		// Missing email
 	}
-	
+
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/parent/register", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ParentRegister(w, req)
+	serve(handler.ParentRegister, w, req)
 
 	// Should get bad request status
 	if w.Code != http.StatusBadRequest {
@@ -145,7 +145,7 @@ func TestParentLogin(t *testing.T) {
 		Email:    "parent@example.com",
 		Password: "testpassword123",
 	}
-	
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		t.Fatalf("failed to marshal payload: %v", err)
@@ -157,7 +157,7 @@ func TestParentLogin(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// Call handler
-	handler.ParentLogin(w, req)
+	serve(handler.ParentLogin, w, req)
 
 	// Assertions
 	if w.Code != http.StatusOK {
@@ -194,13 +194,13 @@ func TestParentLoginInvalidCredentials(t *testing.T) {
 		Email:    "parent@example.com",
 		Password: "wrongpassword",
 	}
-	
+
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/parent/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ParentLogin(w, req)
+	serve(handler.ParentLogin, w, req)
 
 	// Should get unauthorized status
 	if w.Code != http.StatusUnauthorized {
@@ -219,13 +219,13 @@ func TestParentLoginNonExistentEmail(t *testing.T) {
 		Email:    "nonexistent@example.com",
 		Password: "testpassword123",
 	}
-	
+
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/parent/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ParentLogin(w, req)
+	serve(handler.ParentLogin, w, req)
 
 	// Should get unauthorized status
 	if w.Code != http.StatusUnauthorized {
@@ -244,13 +244,13 @@ func TestParentLoginMissingFields(t *testing.T) {
 		"password": "testpassword123",
 		// Missing email
 	}
-	
+
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/parent/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ParentLogin(w, req)
+	serve(handler.ParentLogin, w, req)
 
 	// Should get unauthorized status (empty email treated as invalid credentials)
 	if w.Code != http.StatusUnauthorized {
@@ -262,13 +262,13 @@ func TestParentLoginMissingFields(t *testing.T) {
 		"email": "parent@example.com",
 		// Missing password
 	}
-	
+
 	body2, _ := json.Marshal(payload2)
 	req2 := httptest.NewRequest("POST", "/api/auth/parent/login", bytes.NewReader(body2))
 	req2.Header.Set("Content-Type", "application/json")
 	w2 := httptest.NewRecorder()
 
-	handler.ParentLogin(w2, req2)
+	serve(handler.ParentLogin, w2, req2)
 
 	// Should get unauthorized status (empty password treated as invalid credentials)
 	if w2.Code != http.StatusUnauthorized {
@@ -284,7 +284,7 @@ func TestParentLoginChildUser(t *testing.T) {
 
 	// Create a child user with email (unusual but possible)
 	child := &models.User{
-		Username: "Test Child",
+		Name:     "Test Child",
 		Email:    "child@example.com",
 		Password: hashPassword("testpassword123"),
 		Role:     "child",
@@ -296,16 +296,16 @@ func TestParentLoginChildUser(t *testing.T) {
 		Email:    "child@example.com",
 		Password: "testpassword123",
 	}
-	
+
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest("POST", "/api/auth/parent/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.ParentLogin(w, req)
+	serve(handler.ParentLogin, w, req)
 
 	// Should get unauthorized status because child role doesn't match parent
 	if w.Code != http.StatusUnauthorized {
 		t.Errorf("expected status 401, got %d", w.Code)
 	}
-} 
\ No newline at end of file
+}
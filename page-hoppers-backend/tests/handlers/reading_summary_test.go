@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+)
+
+// TestReadingSummaryCrossFamilyAccess checks who may read a child's summary
+func TestReadingSummaryCrossFamilyAccess(t *testing.T) {
+	// Setup: two families, one with two children, and a grandparent invited as a viewer
+	db := setupTestDB()
+	handler := &handlers.ReadingLogHandler{DB: db}
+
+	bob := createTestParent(db, "Bob", "bob@example.com", "password123")
+	charlie := createTestChild(db, "Charlie", 8, bob.ID, "5678")
+	sam := createTestChild(db, "Sam", 6, bob.ID, "1234")
+	eve := createTestParent(db, "Eve", "eve@example.com", "password123")
+	mallory := createTestChild(db, "Mallory", 9, eve.ID, "4321")
+	gran := createTestParent(db, "Gran", "gran@example.com", "password123")
+	db.Create(&models.Guardianship{ChildID: charlie.ID, GuardianID: gran.ID, Role: models.GuardianViewer})
+
+	cases := []struct {
+		name    string
+		userID  uint
+		role    string
+		childID uint
+		want    int
+	}{
+		{"parent reads own child", bob.ID, "parent", charlie.ID, http.StatusOK},
+		{"parent reads another family's child", eve.ID, "parent", charlie.ID, http.StatusNotFound},
+		{"other parent reads own child", eve.ID, "parent", mallory.ID, http.StatusOK},
+		{"viewer guardian reads child", gran.ID, "parent", charlie.ID, http.StatusOK},
+		{"viewer reads child they weren't invited to", gran.ID, "parent", sam.ID, http.StatusNotFound},
+		{"child reads themselves", charlie.ID, "child", charlie.ID, http.StatusOK},
+		{"child reads sibling", charlie.ID, "child", sam.ID, http.StatusNotFound},
+		{"child reads another family's child", mallory.ID, "child", charlie.ID, http.StatusNotFound},
+		{"child ID used with the parent role", charlie.ID, "parent", charlie.ID, http.StatusNotFound},
+		{"parent ID asked for as a child", bob.ID, "parent", bob.ID, http.StatusNotFound},
+		{"child that doesn't exist", bob.ID, "parent", 999, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/children/%d/summary", tc.childID), nil)
+			w := httptest.NewRecorder()
+			serveAs(db, tc.userID, tc.role, "/api/children/:id/summary", handler.GetReadingSummary, w, req)
+
+			if w.Code != tc.want {
+				t.Errorf("expected status %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestReadingSummaryRequiresAuthentication tests that a request without a user is refused
+func TestReadingSummaryRequiresAuthentication(t *testing.T) {
+	// Setup
+	db := setupTestDB()
+	handler := &handlers.ReadingLogHandler{DB: db}
+	parent := createTestParent(db, "Bob", "bob@example.com", "password123")
+	child := createTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/children/%d/summary", child.ID), nil)
+	w := httptest.NewRecorder()
+	serve(handler.GetReadingSummary, w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", w.Code)
+	}
+}
+
+// TestReadingSummaryDoesNotLeakOtherFamilies tests that a refused summary carries none of the child's data
+func TestReadingSummaryDoesNotLeakOtherFamilies(t *testing.T) {
+	// Setup
+	db := setupTestDB()
+	handler := &handlers.ReadingLogHandler{DB: db}
+	bob := createTestParent(db, "Bob", "bob@example.com", "password123")
+	charlie := createTestChild(db, "Charlie", 8, bob.ID, "5678")
+	eve := createTestParent(db, "Eve", "eve@example.com", "password123")
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/children/%d/summary", charlie.ID), nil)
+	w := httptest.NewRecorder()
+	serveAs(db, eve.ID, "parent", "/api/children/:id/summary", handler.GetReadingSummary, w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+	if body := w.Body.String(); body != `{"error":"Child not found"}` {
+		t.Errorf("unexpected body for another family's child: %s", body)
+	}
+}
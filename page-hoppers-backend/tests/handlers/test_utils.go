@@ -1,57 +1,53 @@
-// package handlers
-
-// import (
-// 	"page-hoppers-backend/internal/models"
-// 	"golang.org/x/crypto/bcrypt"
-// 	"gorm.io/driver/sqlite"
-// 	"gorm.io/gorm"
-// )
-
-// // setupTestDB creates an in-memory SQLite database for testing
-// func setupTestDB() *gorm.DB {
-// 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-// 	if err != nil {
-// 		panic("failed to connect to test database")
-// 	}
-	
-// 	// Auto migrate the schema
-// 	if err := db.AutoMigrate(&models.User{}, &models.ReadingLog{}); err != nil {
-// 		panic("failed to migrate test database")
-// 	}
-	
-// 	return db
-// }
-
-// // hashPassword creates a bcrypt hash for testing
-// func hashPassword(password string) string {
-// 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-// 	if err != nil {
-// 		panic("failed to hash password")
-// 	}
-// 	return string(hashedPassword)
-// }
-
-// // createTestParent creates a test parent user in the database
-// func createTestParent(db *gorm.DB, name, email, password string) *models.User {
-// 	parent := &models.User{
-// 		Name:     name,
-// 		Email:    email,
-// 		Password: hashPassword(password),
-// 		Role:     "parent",
-// 	}
-// 	db.Create(parent)
-// 	return parent
-// }
-
-// // createTestChild creates a test child user in the database
-// func createTestChild(db *gorm.DB, name string, age int, parentID uint, pin string) *models.User {
-// 	child := &models.User{
-// 		Name:     name,
-// 		Age:      age,
-// 		PIN:      hashPassword(pin),
-// 		Role:     "child",
-// 		ParentID: &parentID,
-// 	}
-// 	db.Create(child)
-// 	return child
-// } 
\ No newline at end of file
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// setupTestDB creates an in-memory SQLite database with every table migrated
+func setupTestDB() *gorm.DB {
+	return tests.SetupTestDB()
+}
+
+// hashPassword creates a bcrypt hash for testing
+func hashPassword(password string) string {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		panic("failed to hash password")
+	}
+	return string(hashedPassword)
+}
+
+// createTestParent creates a test parent user in the database
+func createTestParent(db *gorm.DB, name, email, password string) *models.User {
+	return tests.CreateTestParent(db, name, email, password)
+}
+
+// createTestChild creates a test child user in the database
+func createTestChild(db *gorm.DB, name string, age int, parentID uint, pin string) *models.User {
+	return tests.CreateTestChild(db, name, age, parentID, pin)
+}
+
+// serve runs a single handler for req, as an unauthenticated request
+func serve(handler gin.HandlerFunc, w *httptest.ResponseRecorder, req *http.Request) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(req.Method, req.URL.Path, handler)
+	router.ServeHTTP(w, req)
+}
+
+// serveAs runs a handler mounted at route for req, as if the JWT middleware had authenticated userID
+func serveAs(db *gorm.DB, userID uint, role, route string, handler gin.HandlerFunc, w *httptest.ResponseRecorder, req *http.Request) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(req.Method, route, tests.AuthAs(db, userID, role), handler)
+	router.ServeHTTP(w, req)
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,9 +10,11 @@ import (
 
 	"page-hoppers-backend/internal/handlers"
 	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -22,42 +25,112 @@ func SetupTestDB() *gorm.DB {
 	if err != nil {
 		panic("failed to connect to test database")
 	}
+	// One in-memory database, shared by the handlers' background work
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
 
 	// Auto migrate the schema
-	if err := db.AutoMigrate(&models.User{}, &models.ReadingLog{}); err != nil {
+	if err := repository.Migrate(db); err != nil {
 		panic("failed to migrate test database")
 	}
 
 	return db
 }
 
+// hashSecret bcrypts a test password or PIN at the lowest cost, so logins work without slowing tests down
+func hashSecret(secret string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.MinCost)
+	if err != nil {
+		panic("failed to hash test secret")
+	}
+	return string(hashed)
+}
+
+// defaultOrganizationID is where the helpers put adults, as registering without an organization does
+func defaultOrganizationID(db *gorm.DB) uint {
+	org, err := repository.FindOrganization(db, "")
+	if err != nil {
+		panic("test database has no default organization")
+	}
+	return org.ID
+}
+
 // CreateTestParent creates a test parent user in the database
 func CreateTestParent(db *gorm.DB, name, email, password string) *models.User {
 	parent := &models.User{
-		Name:     name,
-		Email:    email,
-		Password: password, // Note: In real tests, this should be hashed
-		Role:     "parent",
+		OrganizationID: defaultOrganizationID(db),
+		Name:           name,
+		Email:          email,
+		Password:       hashSecret(password),
+		Role:           "parent",
 	}
 
 	db.Create(parent)
 	return parent
 }
 
-// CreateTestChild creates a test child user in the database
+// CreateTestTeacher creates a test teacher user in the database
+func CreateTestTeacher(db *gorm.DB, name, email, password string) *models.User {
+	teacher := &models.User{
+		OrganizationID: defaultOrganizationID(db),
+		Name:           name,
+		Email:          email,
+		Password:       hashSecret(password),
+		Role:           "teacher",
+	}
+
+	db.Create(teacher)
+	return teacher
+}
+
+// CreateTestAdmin creates a test organization admin in the database
+func CreateTestAdmin(db *gorm.DB, name, email, password string) *models.User {
+	admin := &models.User{
+		OrganizationID: defaultOrganizationID(db),
+		Name:           name,
+		Email:          email,
+		Password:       hashSecret(password),
+		Role:           "admin",
+	}
+
+	db.Create(admin)
+	return admin
+}
+
+// CreateTestChild creates a test child user in the database, in their parent's organization
 func CreateTestChild(db *gorm.DB, name string, age int, parentID uint, pin string) *models.User {
+	orgID, _ := repository.UserOrganizationID(db, parentID)
 	child := &models.User{
-		Name:     name,
-		Age:      age,
-		PIN:      pin, // Note: In real tests, this should be hashed
-		Role:     "child",
-		ParentID: &parentID,
+		OrganizationID: orgID,
+		Name:           name,
+		Age:            age,
+		PIN:            hashSecret(pin),
+		Role:           "child",
+		ParentID:       &parentID,
 	}
 
 	db.Create(child)
 	return child
 }
 
+// CreateTestOrganization creates another tenant. The other helpers put users in the default organization;
+// MoveToOrganization moves them.
+func CreateTestOrganization(db *gorm.DB, name, slug string) *models.Organization {
+	org, err := repository.CreateOrganization(db, name, slug)
+	if err != nil {
+		panic("failed to create test organization")
+	}
+	return org
+}
+
+// MoveToOrganization puts test users into the organization
+func MoveToOrganization(db *gorm.DB, orgID uint, users ...*models.User) {
+	for _, user := range users {
+		db.Model(user).Update("organization_id", orgID)
+	}
+}
+
 type SummaryTestSetup struct {
 	DB     *gorm.DB
 	Router *gin.Engine
@@ -65,7 +138,8 @@ type SummaryTestSetup struct {
 	Child  *models.User
 }
 
-// SetupSummaryTest sets up a test DB, parent, child, and router with /children/:id/summary route
+// SetupSummaryTest sets up a test DB, parent, child, and router with /children/:id/summary route,
+// with requests made as the parent
 func SetupSummaryTest() *SummaryTestSetup {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -75,7 +149,7 @@ func SetupSummaryTest() *SummaryTestSetup {
 	child := CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
 
 	handler := handlers.ReadingLogHandler{DB: db}
-	router.GET("/children/:id/summary", handler.GetReadingSummary)
+	router.GET("/children/:id/summary", AuthAs(db, parent.ID, "parent"), handler.GetReadingSummary)
 
 	return &SummaryTestSetup{
 		DB:     db,
@@ -100,3 +174,32 @@ func ParseSummary(t *testing.T, resp *httptest.ResponseRecorder) map[string]inte
 	assert.NoError(t, err, "failed to parse reading summary JSON")
 	return summary
 }
+
+// AuthAs stands in for the JWT middleware and marks every request as coming from the given user,
+// in the organization they belong to
+func AuthAs(db *gorm.DB, userID uint, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID, _ := repository.UserOrganizationID(db, userID)
+		c.Set("user_id", userID)
+		c.Set("role", role)
+		c.Set("organization_id", orgID)
+		c.Next()
+	}
+}
+
+// PerformJSON sends a request with an optional JSON body through the router and returns the response
+func PerformJSON(router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
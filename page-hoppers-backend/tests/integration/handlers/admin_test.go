@@ -0,0 +1,235 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+func TestAdmin_SearchAndFamilyTree(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob Smith", "bob@example.com", "password123")
+	charlie := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "1234")
+	dana := tests.CreateTestChild(srv.DB, "Dana", 6, parent.ID, "4321")
+	srv.DB.Delete(dana)
+	gran := tests.CreateTestParent(srv.DB, "Gran Smith", "gran@example.com", "password123")
+	srv.DB.Create(&models.Guardianship{ChildID: charlie.ID, GuardianID: gran.ID, Role: models.GuardianViewer})
+	tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+
+	resp := perform("GET", "/api/admin/users?q=smith&role=parent", admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var users []handlers.AdminUser
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &users))
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, parent.ID, users[0].ID)
+		assert.Equal(t, gran.ID, users[1].ID)
+	}
+
+	// Asking about a child shows the whole family, the deleted child included
+	resp = perform("GET", fmt.Sprintf("/api/admin/users/%d/family", charlie.ID), admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var tree handlers.FamilyTree
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tree))
+	assert.Equal(t, parent.ID, tree.Parent.ID)
+	if assert.Len(t, tree.Children, 2) {
+		assert.Equal(t, "Charlie", tree.Children[0].Name)
+		assert.Nil(t, tree.Children[0].DeletedAt)
+		assert.Len(t, tree.Children[0].Guardians, 2)
+		assert.Equal(t, "Dana", tree.Children[1].Name)
+		assert.NotNil(t, tree.Children[1].DeletedAt)
+	}
+
+	// Both reads are in the audit log
+	resp = perform("GET", "/api/admin/audit", admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var entries []models.AdminAuditEntry
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entries))
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, models.AdminActionViewFamily, entries[0].Action)
+		assert.Equal(t, charlie.ID, *entries[0].TargetUserID)
+		assert.Equal(t, models.AdminActionSearchUsers, entries[1].Action)
+		assert.Equal(t, "q=smith&role=parent", entries[1].Detail)
+	}
+}
+
+func TestAdmin_ResetCredentials(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "1234")
+	tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+	childTokens := login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "1234"))
+
+	// A child's PIN is set directly; their devices are signed out and any lockout is cleared
+	path := fmt.Sprintf("/api/admin/users/%d/reset-credentials", child.ID)
+	assert.Equal(t, http.StatusBadRequest, perform("POST", path, admin, gin.H{}).Code)
+	srv.DB.Create(&models.LoginThrottle{Key: repository.ChildThrottleKey(child.ID), Failures: 9, LastFailureAt: time.Now(),
+		LockedUntil: func() *time.Time { t := time.Now().Add(time.Hour); return &t }()})
+	assert.Equal(t, http.StatusOK, perform("POST", path, admin, gin.H{"pin": "9999"}).Code)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/reading-logs", childTokens.Token, nil).Code)
+	login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "9999"))
+
+	// An adult is emailed a reset link rather than given a password
+	resp := perform("POST", fmt.Sprintf("/api/admin/users/%d/reset-credentials", parent.ID), admin, gin.H{})
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	resp = perform("POST", "/api/auth/password-reset/confirm", "", gin.H{"token": mailer.lastToken(t), "password": "newpassword1"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "newpassword1"})
+}
+
+func TestAdmin_DeactivateAndReactivate(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	adminUser := tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+	tokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	resp := perform("POST", fmt.Sprintf("/api/admin/users/%d/deactivate", parent.ID), admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "deactivated_at")
+
+	// Signed out everywhere and unable to sign in or refresh
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", tokens.Token, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, perform("POST", "/api/auth/refresh", "", gin.H{"refresh_token": tokens.RefreshToken}).Code)
+	resp = perform("POST", "/api/auth/parent/login", "", gin.H{"email": "bob@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "deactivated")
+	// A wrong password still looks like any other wrong password
+	resp = perform("POST", "/api/auth/parent/login", "", gin.H{"email": "bob@example.com", "password": "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = perform("POST", fmt.Sprintf("/api/admin/users/%d/reactivate", parent.ID), admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	// An admin can't lock themselves out
+	resp = perform("POST", fmt.Sprintf("/api/admin/users/%d/deactivate", adminUser.ID), admin, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestAdmin_ImpersonationIsReadOnly(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "1234")
+	adminUser := tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	other := tests.CreateTestAdmin(srv.DB, "Edna", "edna@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+
+	resp := perform("POST", fmt.Sprintf("/api/admin/users/%d/impersonate", parent.ID), admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var support handlers.ImpersonationResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &support))
+	assert.True(t, support.ReadOnly)
+
+	// The admin sees what the parent sees, and the parent can see the support session in their devices
+	resp = perform("GET", "/api/children", support.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Charlie")
+	resp = perform("GET", "/api/auth/sessions", support.Token, nil)
+	assert.Contains(t, resp.Body.String(), "Support (read-only)")
+
+	// Nothing can be changed
+	resp = perform("POST", "/api/children", support.Token, gin.H{"name": "Theo", "age": 7, "pin": "4321"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "read-only")
+	var count int64
+	srv.DB.Model(&models.User{}).Where("name = ?", "Theo").Count(&count)
+	assert.Zero(t, count)
+
+	// Every request made as the parent is in the audit log, the refused one too
+	entries, err := repository.AdminAuditLog(srv.DB, adminUser.OrganizationID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 4) {
+		assert.Equal(t, models.AdminActionViewAsUser, entries[0].Action)
+		assert.Equal(t, adminUser.ID, entries[0].AdminID)
+		assert.Equal(t, parent.ID, *entries[0].TargetUserID)
+		assert.Equal(t, "POST /api/children", entries[0].Detail)
+		assert.Equal(t, "GET /api/auth/sessions", entries[1].Detail)
+		assert.Equal(t, "GET /api/children", entries[2].Detail)
+		assert.Equal(t, models.AdminActionImpersonate, entries[3].Action)
+	}
+
+	// Admins aren't impersonated
+	resp = perform("POST", fmt.Sprintf("/api/admin/users/%d/impersonate", other.ID), admin, nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	// The token stops working once its admin is deactivated
+	assert.NoError(t, repository.DeactivateUser(srv.DB, adminUser.ID, time.Now()))
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", support.Token, nil).Code)
+}
+
+func TestAdmin_RestoreChildAndMergeParents(t *testing.T) {
+	srv, perform := newTestServer(t)
+	keep := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	duplicate := tests.CreateTestParent(srv.DB, "Bob", "bob.smith@example.com", "password123")
+	charlie := tests.CreateTestChild(srv.DB, "Charlie", 8, keep.ID, "1234")
+	dana := tests.CreateTestChild(srv.DB, "Dana", 6, duplicate.ID, "4321")
+	srv.DB.Delete(charlie)
+	srv.DB.Create(&models.Reward{ParentID: duplicate.ID, Name: "Ice cream", Cost: 20})
+	tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+
+	// Restoring brings a deleted child back; anyone else is refused
+	resp := perform("POST", fmt.Sprintf("/api/admin/users/%d/restore", charlie.ID), admin, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = perform("POST", fmt.Sprintf("/api/admin/users/%d/restore", charlie.ID), admin, nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	// Merging moves the duplicate's family over and switches it off
+	path := fmt.Sprintf("/api/admin/users/%d/merge", duplicate.ID)
+	assert.Equal(t, http.StatusBadRequest, perform("POST", path, admin, gin.H{}).Code)
+	assert.Equal(t, http.StatusBadRequest, perform("POST", path, admin, gin.H{"into_user_id": duplicate.ID}).Code)
+	assert.Equal(t, http.StatusNotFound, perform("POST", path, admin, gin.H{"into_user_id": 999}).Code)
+	resp = perform("POST", path, admin, gin.H{"into_user_id": keep.ID})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	tokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	resp = perform("GET", "/api/children", tokens.Token, nil)
+	assert.Contains(t, resp.Body.String(), "Charlie")
+	assert.Contains(t, resp.Body.String(), "Dana")
+	srv.DB.First(dana, dana.ID)
+	assert.Equal(t, keep.ID, *dana.ParentID)
+	resp = perform("GET", "/api/rewards", tokens.Token, nil)
+	assert.Contains(t, resp.Body.String(), "Ice cream")
+	resp = perform("POST", "/api/auth/parent/login", "", gin.H{"email": "bob.smith@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	var audited int64
+	srv.DB.Model(&models.AdminAuditEntry{}).Where("action = ? AND target_user_id = ? AND detail = ?",
+		models.AdminActionMergeAccounts, duplicate.ID, fmt.Sprintf("into user %d", keep.ID)).Count(&audited)
+	assert.Equal(t, int64(1), audited)
+}
+
+func TestAdmin_OrganizationChangesAreAudited(t *testing.T) {
+	srv, perform := newTestServer(t)
+	adminUser := tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	admin := login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+
+	resp := perform("PATCH", "/api/organization", admin, gin.H{"name": "Springfield Elementary"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = perform("POST", "/api/organization/admins", admin, gin.H{"name": "Edna", "email": "edna@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	entries, err := repository.AdminAuditLog(srv.DB, adminUser.OrganizationID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, models.AdminActionCreateAdmin, entries[0].Action)
+		assert.Equal(t, "edna@example.com", entries[0].Detail)
+		assert.Equal(t, models.AdminActionRename, entries[1].Action)
+		assert.Equal(t, `"Page Hoppers" to "Springfield Elementary"`, entries[1].Detail)
+		assert.Equal(t, adminUser.ID, entries[1].AdminID)
+	}
+}
@@ -0,0 +1,141 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/internal/server"
+	"page-hoppers-backend/tests"
+)
+
+// newTestServer builds the real router, auth middleware included, with local-only book search
+func newTestServer(t *testing.T) (*server.Server, func(method, path, token string, body interface{}) *httptest.ResponseRecorder) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("BOOK_PROVIDERS", "local")
+	gin.SetMode(gin.TestMode)
+	srv := server.NewServer(tests.SetupTestDB())
+
+	perform := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		router := srv.Router
+		if token == "" {
+			return tests.PerformJSON(router, method, path, body)
+		}
+		return tests.PerformJSON(withBearer(router, token), method, path, body)
+	}
+	return srv, perform
+}
+
+// withBearer adds an Authorization header to every request
+func withBearer(next http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func login(t *testing.T, perform func(string, string, string, interface{}) *httptest.ResponseRecorder, path string, body gin.H) handlers.LoginResponse {
+	resp := perform("POST", path, "", body)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var tokens handlers.LoginResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tokens))
+	assert.NotEmpty(t, tokens.RefreshToken)
+	return tokens
+}
+
+// childLoginBody pairs a device with the child's family and names the child from it, as a shared tablet would
+func childLoginBody(t *testing.T, db *gorm.DB, child *models.User, pin string) gin.H {
+	_, deviceToken, err := repository.CreateFamilyDevice(db, *child.ParentID, "Test tablet", time.Now())
+	assert.NoError(t, err)
+	children, err := repository.FamilyLoginChildren(db, *child.ParentID)
+	assert.NoError(t, err)
+	for _, listed := range children {
+		if listed.ID == child.ID {
+			return gin.H{"device_token": deviceToken, "child_key": listed.LoginKey, "pin": pin}
+		}
+	}
+	t.Fatalf("child %d isn't on their family's login list", child.ID)
+	return nil
+}
+
+func TestSessions_RefreshRotatesAndLogoutRevokes(t *testing.T) {
+	srv, perform := newTestServer(t)
+	tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+
+	first := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusOK, perform("GET", "/api/children", first.Token, nil).Code)
+
+	resp := perform("POST", "/api/auth/refresh", "", gin.H{"refresh_token": first.RefreshToken})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var second handlers.LoginResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &second))
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	assert.Equal(t, http.StatusOK, perform("GET", "/api/children", second.Token, nil).Code)
+
+	resp = perform("POST", "/api/auth/logout", second.Token, nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", second.Token, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", first.Token, nil).Code,
+		"every access token from the session stops working")
+	resp = perform("POST", "/api/auth/refresh", "", gin.H{"refresh_token": second.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestSessions_ReusedRefreshTokenEndsSession(t *testing.T) {
+	srv, perform := newTestServer(t)
+	tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	first := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	resp := perform("POST", "/api/auth/refresh", "", gin.H{"refresh_token": first.RefreshToken})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var second handlers.LoginResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &second))
+
+	resp = perform("POST", "/api/auth/refresh", "", gin.H{"refresh_token": first.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", second.Token, nil).Code)
+}
+
+func TestSessions_ParentRevokesChildDevice(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(srv.DB, "Dana", "dana@example.com", "password123")
+
+	parentTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	childTokens := login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "5678"))
+	otherTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": other.Email, "password": "password123"})
+
+	resp := perform("GET", "/api/auth/sessions", parentTokens.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var sessions []handlers.SessionResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &sessions))
+	assert.Len(t, sessions, 2, "the parent's own device and the child's")
+
+	var childSession handlers.SessionResponse
+	for _, session := range sessions {
+		if session.UserID == child.ID {
+			childSession = session
+		} else {
+			assert.True(t, session.Current)
+		}
+	}
+	assert.Equal(t, "Charlie", childSession.UserName)
+
+	path := fmt.Sprintf("/api/auth/sessions/%d", childSession.ID)
+	assert.Equal(t, http.StatusNotFound, perform("DELETE", path, otherTokens.Token, nil).Code)
+	assert.Equal(t, http.StatusNoContent, perform("DELETE", path, parentTokens.Token, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/reading-logs", childTokens.Token, nil).Code)
+	assert.Equal(t, http.StatusOK, perform("GET", "/api/children", parentTokens.Token, nil).Code)
+}
@@ -0,0 +1,125 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+func TestClassrooms_ConsentedChildShowsInClassSummary(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	charlie := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	sam := tests.CreateTestChild(srv.DB, "Sam", 6, parent.ID, "1234")
+	now := time.Now()
+	srv.DB.Create(&models.ReadingLog{ChildID: charlie.ID, Title: "Holes", Status: models.StatusCompleted, Date: now, CompletedAt: &now})
+	srv.DB.Create(&models.ReadingLog{ChildID: sam.ID, Title: "Matilda", Status: models.StatusCompleted, Date: now, CompletedAt: &now})
+
+	resp := perform("POST", "/api/auth/teacher/register", "", gin.H{"name": "Ms Frizzle", "email": "frizzle@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	teacher := login(t, perform, "/api/auth/teacher/login", gin.H{"email": "frizzle@example.com", "password": "password123"})
+	// A teacher can't sign in as a parent
+	assert.Equal(t, http.StatusUnauthorized, perform("POST", "/api/auth/parent/login", "", gin.H{"email": "frizzle@example.com", "password": "password123"}).Code)
+
+	resp = perform("POST", "/api/classrooms", teacher.Token, gin.H{"name": "Class 3B"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var classroom models.Classroom
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &classroom))
+	assert.Len(t, classroom.JoinCode, 8)
+
+	// The parent consents for Charlie only, typing the code loosely
+	bob := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	typed := fmt.Sprintf("%s-%s", classroom.JoinCode[:4], classroom.JoinCode[4:])
+	resp = perform("POST", fmt.Sprintf("/api/children/%d/classrooms", charlie.ID), bob.Token, gin.H{"join_code": " " + typed + " "})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	resp = perform("GET", fmt.Sprintf("/api/children/%d/classrooms", charlie.ID), bob.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"teacher_name":"Ms Frizzle"`)
+
+	resp = perform("GET", fmt.Sprintf("/api/classrooms/%d/summary?period=this_year", classroom.ID), teacher.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var summary struct {
+		Students []handlers.StudentSummary `json:"students"`
+		Total    handlers.ClassroomTotal   `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &summary))
+	if assert.Len(t, summary.Students, 1) {
+		assert.Equal(t, charlie.ID, summary.Students[0].ChildID)
+		assert.Equal(t, 1, summary.Students[0].Range.BooksCompleted)
+		assert.Equal(t, 1, summary.Students[0].TotalCompletedBooks)
+	}
+	assert.Equal(t, 1, summary.Total.Students)
+	assert.Equal(t, 1, summary.Total.BooksCompleted)
+
+	// Teachers only see children through their classes, and can't change anything
+	assert.Equal(t, http.StatusForbidden, perform("GET", fmt.Sprintf("/api/children/%d/summary", charlie.ID), teacher.Token, nil).Code)
+	assert.Equal(t, http.StatusForbidden, perform("GET", "/api/children", teacher.Token, nil).Code)
+
+	// Withdrawing consent takes Charlie out of the class at once
+	resp = perform("DELETE", fmt.Sprintf("/api/children/%d/classrooms/%d", charlie.ID, classroom.ID), bob.Token, nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	resp = perform("GET", fmt.Sprintf("/api/classrooms/%d/students", classroom.ID), teacher.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[]`, resp.Body.String())
+}
+
+func TestClassrooms_OnlyTheTeacherAndManagingGuardiansHaveAccess(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	owner := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, owner.ID, "5678")
+	tests.CreateTestParent(srv.DB, "Gran", "gran@example.com", "password123")
+	tests.CreateTestParent(srv.DB, "Eve", "eve@example.com", "password123")
+	tests.CreateTestTeacher(srv.DB, "Ms Frizzle", "frizzle@example.com", "password123")
+	tests.CreateTestTeacher(srv.DB, "Mr Snape", "snape@example.com", "password123")
+
+	frizzle := login(t, perform, "/api/auth/teacher/login", gin.H{"email": "frizzle@example.com", "password": "password123"})
+	snape := login(t, perform, "/api/auth/teacher/login", gin.H{"email": "snape@example.com", "password": "password123"})
+	ownerTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	eve := login(t, perform, "/api/auth/parent/login", gin.H{"email": "eve@example.com", "password": "password123"})
+	viewer := inviteGuardian(t, perform, mailer, ownerTokens.Token, child.ID, "gran@example.com", models.GuardianViewer)
+
+	resp := perform("POST", "/api/classrooms", frizzle.Token, gin.H{"name": "Class 3B"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var classroom models.Classroom
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &classroom))
+
+	joinPath := fmt.Sprintf("/api/children/%d/classrooms", child.ID)
+	assert.Equal(t, http.StatusForbidden, perform("POST", joinPath, viewer, gin.H{"join_code": classroom.JoinCode}).Code)
+	assert.Equal(t, http.StatusNotFound, perform("POST", joinPath, eve.Token, gin.H{"join_code": classroom.JoinCode}).Code)
+	assert.Equal(t, http.StatusNotFound, perform("POST", joinPath, ownerTokens.Token, gin.H{"join_code": "NOPE2345"}).Code)
+	assert.Equal(t, http.StatusCreated, perform("POST", joinPath, ownerTokens.Token, gin.H{"join_code": classroom.JoinCode}).Code)
+	// Consenting twice is fine
+	assert.Equal(t, http.StatusCreated, perform("POST", joinPath, ownerTokens.Token, gin.H{"join_code": classroom.JoinCode}).Code)
+	// Viewers can see which classes the child is in
+	assert.Equal(t, http.StatusOK, perform("GET", joinPath, viewer, nil).Code)
+
+	// Another teacher can't see the class
+	for _, path := range []string{"/students", "/summary"} {
+		assert.Equal(t, http.StatusNotFound, perform("GET", fmt.Sprintf("/api/classrooms/%d%s", classroom.ID, path), snape.Token, nil).Code)
+	}
+	resp = perform("GET", "/api/classrooms", snape.Token, nil)
+	assert.JSONEq(t, `[]`, resp.Body.String())
+
+	// A new join code stops the old one working but keeps the class
+	resp = perform("POST", fmt.Sprintf("/api/classrooms/%d/join-code", classroom.ID), frizzle.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var rotated models.Classroom
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rotated))
+	assert.NotEqual(t, classroom.JoinCode, rotated.JoinCode)
+	assert.Equal(t, http.StatusNotFound, perform("POST", joinPath, ownerTokens.Token, gin.H{"join_code": classroom.JoinCode}).Code)
+	resp = perform("GET", fmt.Sprintf("/api/classrooms/%d/students", classroom.ID), frizzle.Token, nil)
+	var students []handlers.ClassroomStudent
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &students))
+	assert.Len(t, students, 1)
+}
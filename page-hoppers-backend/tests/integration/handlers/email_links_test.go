@@ -0,0 +1,140 @@
+package integration_handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/mail"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// recordingMailer keeps sent messages so tests can follow their links
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+// lastToken returns the token from the link in the newest message
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !assert.NotEmpty(t, m.sent) {
+		return ""
+	}
+	match := linkToken.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	if !assert.Len(t, match, 2) {
+		return ""
+	}
+	token, err := url.QueryUnescape(match[1])
+	assert.NoError(t, err)
+	return token
+}
+
+func TestEmailVerification_RegisterThenConfirm(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	srv.AuthHandler.AppURL = "https://app.example.com/"
+
+	resp := perform("POST", "/api/auth/parent/register", "", gin.H{"name": "Bob", "email": "bob@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	if assert.Len(t, mailer.sent, 1) {
+		assert.Equal(t, "bob@example.com", mailer.sent[0].To)
+		assert.Contains(t, mailer.sent[0].Body, "https://app.example.com/verify-email?token=")
+	}
+	token := mailer.lastToken(t)
+
+	resp = perform("POST", "/api/auth/password-reset/confirm", "", gin.H{"token": token, "password": "hijacked"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "a verification link can't reset a password")
+
+	resp = perform("POST", "/api/auth/verify-email/confirm", "", gin.H{"token": token})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var parent models.User
+	srv.DB.Where("email = ?", "bob@example.com").First(&parent)
+	assert.NotNil(t, parent.EmailVerifiedAt)
+
+	resp = perform("POST", "/api/auth/verify-email/confirm", "", gin.H{"token": token})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "links work once")
+
+	resp = perform("POST", "/api/auth/verify-email/request", "", gin.H{"email": "bob@example.com"})
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	srv.AuthHandler.Wait()
+	assert.Len(t, mailer.sent, 1, "verified addresses aren't sent another email")
+}
+
+func TestPasswordReset_ChangesPasswordAndSignsOut(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	before := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	resp := perform("POST", "/api/auth/password-reset/request", "", gin.H{"email": "nobody@example.com"})
+	assert.Equal(t, http.StatusAccepted, resp.Code, "unknown addresses look the same")
+	srv.AuthHandler.Wait()
+	assert.Empty(t, mailer.sent)
+
+	resp = perform("POST", "/api/auth/password-reset/request", "", gin.H{"email": "bob@example.com"})
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	srv.AuthHandler.Wait()
+	token := mailer.lastToken(t)
+
+	resp = perform("POST", "/api/auth/password-reset/confirm", "", gin.H{"token": token + "x", "password": "new-password"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "tampered tokens fail the signature check")
+
+	resp = perform("POST", "/api/auth/password-reset/confirm", "", gin.H{"token": token, "password": "new-password"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", before.Token, nil).Code, "old devices are signed out")
+	resp = perform("POST", "/api/auth/parent/login", "", gin.H{"email": "bob@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "new-password"})
+
+	resp = perform("POST", "/api/auth/password-reset/confirm", "", gin.H{"token": token, "password": "again"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "links work once")
+}
+
+// stalledMailer holds every message until release is closed, like a slow mail server
+type stalledMailer struct {
+	recordingMailer
+	release chan struct{}
+}
+
+func (m *stalledMailer) Send(ctx context.Context, msg mail.Message) error {
+	<-m.release
+	return m.recordingMailer.Send(ctx, msg)
+}
+
+func TestEmailLinks_AnswerBeforeTheEmailIsSent(t *testing.T) {
+	for _, path := range []string{"/api/auth/password-reset/request", "/api/auth/verify-email/request"} {
+		srv, perform := newTestServer(t)
+		mailer := &stalledMailer{release: make(chan struct{})}
+		srv.AuthHandler.Mailer = mailer
+		tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+
+		// A slow mail server doesn't hold up the answer, so it can't give away which addresses have accounts
+		resp := perform("POST", path, "", gin.H{"email": "bob@example.com"})
+		assert.Equal(t, http.StatusAccepted, resp.Code, path)
+
+		close(mailer.release)
+		srv.AuthHandler.Wait()
+		assert.NotEmpty(t, mailer.lastToken(t), path)
+	}
+}
@@ -0,0 +1,97 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/tests"
+)
+
+func TestFamilyDevice_ChildLogsInWithoutDatabaseID(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(srv.DB, "Dana", "dana@example.com", "password123")
+	tests.CreateTestChild(srv.DB, "Eve", 7, other.ID, "1111")
+
+	parentTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	resp := perform("PATCH", fmt.Sprintf("/api/children/%d/settings", child.ID), parentTokens.Token, gin.H{"avatar": "🦊"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = perform("POST", "/api/family/devices", parentTokens.Token, gin.H{"name": "Kitchen tablet"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var paired handlers.PairDeviceResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &paired))
+	assert.NotEmpty(t, paired.DeviceToken)
+
+	resp = perform("POST", "/api/auth/family/children", "", gin.H{"device_token": paired.DeviceToken})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), `"id"`)
+	var children []handlers.FamilyLoginChild
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &children))
+	if assert.Len(t, children, 1, "only this family's children") {
+		assert.Equal(t, "Charlie", children[0].Name)
+		assert.Equal(t, "🦊", children[0].Avatar)
+	}
+
+	resp = perform("POST", "/api/auth/child/login", "", gin.H{"device_token": paired.DeviceToken, "child_key": children[0].Key, "pin": "0000"})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	login(t, perform, "/api/auth/child/login", gin.H{"device_token": paired.DeviceToken, "child_key": children[0].Key, "pin": "5678"})
+
+	// Unpairing stops the device listing or logging in children
+	resp = perform("DELETE", fmt.Sprintf("/api/family/devices/%d", paired.Device.ID), parentTokens.Token, nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	resp = perform("POST", "/api/auth/family/children", "", gin.H{"device_token": paired.DeviceToken})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp = perform("POST", "/api/auth/child/login", "", gin.H{"device_token": paired.DeviceToken, "child_key": children[0].Key, "pin": "5678"})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestFamilyDevice_OnlyParentsPair(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(srv.DB, "Dana", "dana@example.com", "password123")
+
+	childTokens := login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "5678"))
+	resp := perform("POST", "/api/family/devices", childTokens.Token, gin.H{"name": "Tablet"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	parentTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	resp = perform("POST", "/api/family/devices", parentTokens.Token, gin.H{"name": " "})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = perform("POST", "/api/family/devices", parentTokens.Token, gin.H{"name": "Tablet"})
+	var paired handlers.PairDeviceResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &paired))
+
+	otherTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": other.Email, "password": "password123"})
+	resp = perform("DELETE", fmt.Sprintf("/api/family/devices/%d", paired.Device.ID), otherTokens.Token, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = perform("GET", "/api/family/devices", parentTokens.Token, nil)
+	assert.Contains(t, resp.Body.String(), "Tablet")
+	assert.NotContains(t, resp.Body.String(), paired.DeviceToken)
+}
+
+func TestChildLogin_ByDatabaseIDOnlyWithTheLegacyFlag(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		if legacy {
+			t.Setenv("LEGACY_CHILD_ID_LOGIN", "true")
+		}
+		srv, perform := newTestServer(t)
+		parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+		child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+
+		resp := perform("POST", "/api/auth/child/login", "", gin.H{"childId": child.ID, "pin": "5678"})
+		if legacy {
+			assert.Equal(t, http.StatusOK, resp.Code, "deprecated, but still allowed for older clients")
+		} else {
+			assert.Equal(t, http.StatusBadRequest, resp.Code, "children log in from a paired device")
+		}
+	}
+}
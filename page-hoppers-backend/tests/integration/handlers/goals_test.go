@@ -0,0 +1,85 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+// newGoalRouter wires the goal routes for a single authenticated user
+func newGoalRouter(db *gorm.DB, userID uint, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, userID, role))
+
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.GET("/children/:id/goals", handler.GetGoals)
+	router.POST("/children/:id/goals", handler.CreateGoal)
+	router.PATCH("/children/:id/goals/:goalId", handler.UpdateGoal)
+	router.DELETE("/children/:id/goals/:goalId", handler.DeleteGoal)
+	return router
+}
+
+func TestGoals_ParentManagesChildGoals(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	path := fmt.Sprintf("/children/%d/goals", child.ID)
+
+	router := newGoalRouter(db, parent.ID, "parent")
+	resp := tests.PerformJSON(router, "POST", path, gin.H{"metric": "books", "period": "fortnight", "target": 4})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"metric": "books", "period": "month"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "target is required")
+
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"metric": "books", "period": "month", "target": 4})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var goal models.Goal
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &goal))
+	assert.Equal(t, parent.ID, goal.SetByID)
+
+	resp = tests.PerformJSON(router, "PATCH", fmt.Sprintf("%s/%d", path, goal.ID), gin.H{"target": 1})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The child sees progress but can't change the goal
+	childRouter := newGoalRouter(db, child.ID, "child")
+	resp = tests.PerformJSON(childRouter, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var progress []repository.GoalProgress
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &progress))
+	assert.Len(t, progress, 1)
+	assert.Equal(t, 1, progress[0].Target)
+	assert.Equal(t, 0, progress[0].Current)
+
+	resp = tests.PerformJSON(childRouter, "POST", path, gin.H{"metric": "books", "period": "month", "target": 0})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	resp = tests.PerformJSON(childRouter, "DELETE", fmt.Sprintf("%s/%d", path, goal.ID), nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = tests.PerformJSON(router, "DELETE", fmt.Sprintf("%s/%d", path, goal.ID), nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestGoals_OtherFamilyIsNotFound(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+	goal := models.Goal{ChildID: child.ID, SetByID: parent.ID, Metric: "books", Period: "month", Target: 3}
+	db.Create(&goal)
+
+	router := newGoalRouter(db, other.ID, "parent")
+	path := fmt.Sprintf("/children/%d/goals", child.ID)
+	assert.Equal(t, http.StatusNotFound, tests.PerformJSON(router, "GET", path, nil).Code)
+	assert.Equal(t, http.StatusNotFound, tests.PerformJSON(router, "PATCH", fmt.Sprintf("%s/%d", path, goal.ID), gin.H{"target": 9}).Code)
+}
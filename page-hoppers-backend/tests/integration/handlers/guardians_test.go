@@ -0,0 +1,104 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// inviteGuardian has the owner invite email as role and the invitee accept, returning the invitee's token
+func inviteGuardian(t *testing.T, perform func(string, string, string, interface{}) *httptest.ResponseRecorder,
+	mailer *recordingMailer, ownerToken string, childID uint, email, role string) string {
+	resp := perform("POST", fmt.Sprintf("/api/children/%d/guardians/invites", childID), ownerToken, gin.H{"email": email, "role": role})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	invitee := login(t, perform, "/api/auth/parent/login", gin.H{"email": email, "password": "password123"})
+	resp = perform("POST", "/api/guardian-invites/accept", invitee.Token, gin.H{"token": mailer.lastToken(t)})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	return invitee.Token
+}
+
+func TestGuardians_CoParentManagesChild(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	owner := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, owner.ID, "5678")
+	tests.CreateTestParent(srv.DB, "Alex", "alex@example.com", "password123")
+	ownerTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	coParent := inviteGuardian(t, perform, mailer, ownerTokens.Token, child.ID, "alex@example.com", models.GuardianCoParent)
+
+	resp := perform("GET", "/api/children", coParent, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var children []handlers.GuardedChild
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &children))
+	if assert.Len(t, children, 1) {
+		assert.Equal(t, child.ID, children[0].ID)
+		assert.Equal(t, models.GuardianCoParent, children[0].GuardianRole)
+	}
+
+	resp = perform("GET", fmt.Sprintf("/api/children/reading-logs?child_id=%d", child.ID), coParent, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = perform("POST", fmt.Sprintf("/api/children/%d/goals", child.ID), coParent, gin.H{"metric": "books", "period": "month", "target": 3})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	// Co-parents can't manage guardians
+	resp = perform("POST", fmt.Sprintf("/api/children/%d/guardians/invites", child.ID), coParent, gin.H{"email": "x@example.com", "role": "viewer"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestGuardians_ViewerIsReadOnly(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	owner := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, owner.ID, "5678")
+	gran := tests.CreateTestParent(srv.DB, "Gran", "gran@example.com", "password123")
+	tests.CreateTestParent(srv.DB, "Dana", "dana@example.com", "password123")
+	ownerTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+
+	// Someone else can't use an invite sent to gran
+	resp := perform("POST", fmt.Sprintf("/api/children/%d/guardians/invites", child.ID), ownerTokens.Token, gin.H{"email": "gran@example.com", "role": "viewer"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	dana := login(t, perform, "/api/auth/parent/login", gin.H{"email": "dana@example.com", "password": "password123"})
+	resp = perform("POST", "/api/guardian-invites/accept", dana.Token, gin.H{"token": mailer.lastToken(t)})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Equal(t, http.StatusNotFound, perform("GET", fmt.Sprintf("/api/children/%d/goals", child.ID), dana.Token, nil).Code)
+
+	viewer := inviteGuardian(t, perform, mailer, ownerTokens.Token, child.ID, "gran@example.com", models.GuardianViewer)
+
+	goalsPath := fmt.Sprintf("/api/children/%d/goals", child.ID)
+	assert.Equal(t, http.StatusOK, perform("GET", goalsPath, viewer, nil).Code)
+	assert.Equal(t, http.StatusOK, perform("GET", fmt.Sprintf("/api/children/%d/streaks", child.ID), viewer, nil).Code)
+	resp = perform("POST", goalsPath, viewer, gin.H{"metric": "books", "period": "month", "target": 3})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	resp = perform("PATCH", fmt.Sprintf("/api/children/%d/settings", child.ID), viewer, gin.H{"requires_log_approval": true})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = perform("GET", fmt.Sprintf("/api/children/%d/guardians", child.ID), ownerTokens.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var guardians handlers.GuardiansResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &guardians))
+	assert.Len(t, guardians.Guardians, 2)
+
+	// Promote, then remove
+	guardianPath := fmt.Sprintf("/api/children/%d/guardians/%d", child.ID, gran.ID)
+	resp = perform("PATCH", guardianPath, ownerTokens.Token, gin.H{"role": "co_parent"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, http.StatusCreated, perform("POST", goalsPath, viewer, gin.H{"metric": "books", "period": "month", "target": 3}).Code)
+
+	assert.Equal(t, http.StatusNotFound, perform("DELETE", fmt.Sprintf("/api/children/%d/guardians/%d", child.ID, owner.ID), ownerTokens.Token, nil).Code,
+		"the owner can't be removed")
+	assert.Equal(t, http.StatusNoContent, perform("DELETE", guardianPath, ownerTokens.Token, nil).Code)
+	assert.Equal(t, http.StatusNotFound, perform("GET", goalsPath, viewer, nil).Code)
+}
@@ -0,0 +1,110 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+func TestChildLogin_WrongPINsBackOff(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+
+	for i := 0; i < repository.ChildPINPolicy.FreeAttempts; i++ {
+		resp := perform("POST", "/api/auth/child/login", "", childLoginBody(t, srv.DB, child, "0000"))
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.Contains(t, resp.Body.String(), "Invalid credentials")
+	}
+
+	resp := perform("POST", "/api/auth/child/login", "", childLoginBody(t, srv.DB, child, "5678"))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code, "even the right PIN has to wait")
+	assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+
+	unknown := childLoginBody(t, srv.DB, child, "0000")
+	unknown["child_key"] = "no-such-child"
+	resp = perform("POST", "/api/auth/child/login", "", unknown)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Invalid credentials", "unknown children look like wrong PINs")
+}
+
+func TestChildLogin_LockoutNotifiesParentWhoCanUnlock(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(srv.DB, "Dana", "dana@example.com", "password123")
+
+	// One failure short of a lockout, long enough ago that the backoff has passed
+	policy := repository.ChildPINPolicy
+	assert.NoError(t, srv.DB.Create(&models.LoginThrottle{
+		Key:           repository.ChildThrottleKey(child.ID),
+		Failures:      policy.LockoutAfter - 1,
+		LastFailureAt: time.Now().Add(-policy.MaxDelay).UTC(),
+	}).Error)
+
+	resp := perform("POST", "/api/auth/child/login", "", childLoginBody(t, srv.DB, child, "0000"))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = perform("POST", "/api/auth/child/login", "", childLoginBody(t, srv.DB, child, "5678"))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	var locked struct {
+		Locked     bool `json:"locked"`
+		RetryAfter int  `json:"retry_after"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &locked))
+	assert.True(t, locked.Locked)
+	assert.InDelta(t, policy.LockoutFor.Seconds(), locked.RetryAfter, 5)
+
+	parentTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	resp = perform("GET", "/api/notifications?unread=true", parentTokens.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var notifications []models.Notification
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &notifications))
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, models.NotificationChildLockedOut, notifications[0].Kind)
+		assert.Contains(t, notifications[0].Message, "Charlie")
+		readPath := fmt.Sprintf("/api/notifications/%d/read", notifications[0].ID)
+		assert.Equal(t, http.StatusNoContent, perform("POST", readPath, parentTokens.Token, nil).Code)
+	}
+	resp = perform("GET", "/api/notifications?unread=true", parentTokens.Token, nil)
+	assert.JSONEq(t, "[]", resp.Body.String())
+
+	unlockPath := fmt.Sprintf("/api/children/%d/unlock", child.ID)
+	otherTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": other.Email, "password": "password123"})
+	assert.Equal(t, http.StatusNotFound, perform("POST", unlockPath, otherTokens.Token, nil).Code)
+	assert.Equal(t, http.StatusNoContent, perform("POST", unlockPath, parentTokens.Token, nil).Code)
+
+	login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "5678"))
+}
+
+func TestChildLogin_ForwardedForDoesNotEscapeAddressLockout(t *testing.T) {
+	srv, _ := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+
+	lockedUntil := time.Now().Add(time.Hour).UTC()
+	assert.NoError(t, srv.DB.Create(&models.LoginThrottle{
+		Key:           repository.IPThrottleKey("192.0.2.1"),
+		Failures:      repository.LoginIPPolicy.LockoutAfter,
+		LastFailureAt: time.Now().UTC(),
+		LockedUntil:   &lockedUntil,
+	}).Error)
+
+	// No proxy is trusted, so a made-up X-Forwarded-For is ignored and the connection's address stays locked
+	spoofed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = "192.0.2.1:40000"
+		r.Header.Set("X-Forwarded-For", "203.0.113.7")
+		srv.Router.ServeHTTP(w, r)
+	})
+	resp := tests.PerformJSON(spoofed, "POST", "/api/auth/child/login", childLoginBody(t, srv.DB, child, "5678"))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+}
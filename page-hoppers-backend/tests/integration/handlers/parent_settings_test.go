@@ -0,0 +1,47 @@
+package integration_handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+func TestParentTimeZone_AppliesToChildLogs(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	auth := handlers.NewAuthHandler(db, []byte("secret"))
+
+	gin.SetMode(gin.TestMode)
+	parentRouter := gin.New()
+	parentRouter.Use(tests.AuthAs(db, parent.ID, "parent"))
+	parentRouter.PATCH("/parent/settings", auth.UpdateParentSettings)
+
+	resp := tests.PerformJSON(parentRouter, "PATCH", "/parent/settings", gin.H{"time_zone": "Not/A_Zone"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = tests.PerformJSON(parentRouter, "PATCH", "/parent/settings", gin.H{"time_zone": "America/Chicago"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	childRouter := gin.New()
+	childRouter.Use(tests.AuthAs(db, child.ID, "child"))
+	childRouter.POST("/reading-logs", handlers.NewReadingLogHandler(db, nil).CreateReadingLog)
+	childRouter.PATCH("/parent/settings", auth.UpdateParentSettings)
+
+	resp = tests.PerformJSON(childRouter, "PATCH", "/parent/settings", gin.H{"time_zone": "UTC"})
+	assert.Equal(t, http.StatusForbidden, resp.Code, "children can't change family settings")
+
+	resp = tests.PerformJSON(childRouter, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "completed", "date": "2025-01-31"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var log models.ReadingLog
+	assert.NoError(t, db.Where("child_id = ?", child.ID).First(&log).Error)
+	chicago, _ := time.LoadLocation("America/Chicago")
+	assert.True(t, log.Date.Equal(time.Date(2025, time.January, 31, 0, 0, 0, 0, chicago)), "dates are midnight in the family's zone, got %s", log.Date)
+}
@@ -0,0 +1,211 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/tests"
+)
+
+const (
+	parentRole  = policy.RoleParent
+	childRole   = policy.RoleChild
+	teacherRole = policy.RoleTeacher
+	adminRole   = policy.RoleAdmin
+)
+
+var (
+	anyRole  = []string{parentRole, childRole}
+	signedIn = []string{parentRole, childRole, teacherRole, adminRole}
+)
+
+// routeRoles lists every protected route with the roles allowed past its role check
+var routeRoles = []struct {
+	method, path string
+	roles        []string
+}{
+	{"POST", "/api/auth/logout", signedIn},
+	{"GET", "/api/auth/sessions", signedIn},
+	{"DELETE", "/api/auth/sessions/:id", signedIn},
+	{"GET", "/api/organization", signedIn},
+	{"PATCH", "/api/organization", []string{adminRole}},
+	{"GET", "/api/organization/members", []string{adminRole}},
+	{"POST", "/api/organization/admins", []string{adminRole}},
+	{"GET", "/api/organization/classrooms", []string{adminRole}},
+	{"GET", "/api/organization/join-codes", []string{adminRole}},
+	{"POST", "/api/organization/join-codes/:role", []string{adminRole}},
+	{"GET", "/api/admin/users", []string{adminRole}},
+	{"GET", "/api/admin/users/:id/family", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/reset-credentials", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/deactivate", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/reactivate", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/restore", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/merge", []string{adminRole}},
+	{"POST", "/api/admin/users/:id/impersonate", []string{adminRole}},
+	{"GET", "/api/admin/audit", []string{adminRole}},
+	{"GET", "/api/children", []string{parentRole}},
+	{"POST", "/api/children", []string{parentRole}},
+	{"PATCH", "/api/children/:id/settings", []string{parentRole}},
+	{"POST", "/api/children/:id/unlock", []string{parentRole}},
+	{"GET", "/api/children/:id/guardians", []string{parentRole}},
+	{"POST", "/api/children/:id/guardians/invites", []string{parentRole}},
+	{"PATCH", "/api/children/:id/guardians/:userId", []string{parentRole}},
+	{"DELETE", "/api/children/:id/guardians/:userId", []string{parentRole}},
+	{"POST", "/api/guardian-invites/accept", []string{parentRole}},
+	{"DELETE", "/api/guardian-invites/:id", []string{parentRole}},
+	{"GET", "/api/classrooms", []string{teacherRole}},
+	{"POST", "/api/classrooms", []string{teacherRole}},
+	{"POST", "/api/classrooms/:id/join-code", []string{teacherRole}},
+	{"GET", "/api/classrooms/:id/students", []string{teacherRole}},
+	{"GET", "/api/classrooms/:id/summary", []string{teacherRole}},
+	{"GET", "/api/children/:id/classrooms", []string{parentRole}},
+	{"POST", "/api/children/:id/classrooms", []string{parentRole}},
+	{"DELETE", "/api/children/:id/classrooms/:classroomId", []string{parentRole}},
+	{"GET", "/api/family/devices", []string{parentRole}},
+	{"POST", "/api/family/devices", []string{parentRole}},
+	{"DELETE", "/api/family/devices/:id", []string{parentRole}},
+	{"GET", "/api/notifications", signedIn},
+	{"POST", "/api/notifications/:id/read", signedIn},
+	{"GET", "/api/parent/settings", []string{parentRole}},
+	{"PATCH", "/api/parent/settings", []string{parentRole}},
+	{"POST", "/api/reading-logs", []string{childRole}},
+	{"POST", "/api/reading-logs/by-isbn", []string{childRole}},
+	{"GET", "/api/reading-logs", []string{childRole}},
+	{"GET", "/api/children/reading-logs", []string{parentRole}},
+	{"PATCH", "/api/reading-logs/:id", anyRole},
+	{"DELETE", "/api/reading-logs/:id", anyRole},
+	{"GET", "/api/reading-logs/trash", anyRole},
+	{"POST", "/api/reading-logs/:id/restore", anyRole},
+	{"POST", "/api/reading-logs/:id/transitions", anyRole},
+	{"GET", "/api/reading-logs/:id/transitions", anyRole},
+	{"GET", "/api/reading-logs/pending", []string{parentRole}},
+	{"POST", "/api/reading-logs/:id/approve", []string{parentRole}},
+	{"POST", "/api/reading-logs/:id/reject", []string{parentRole}},
+	{"GET", "/api/reading-logs/:id/sessions", anyRole},
+	{"POST", "/api/reading-logs/:id/sessions", anyRole},
+	{"PATCH", "/api/reading-logs/:id/sessions/:sessionId", anyRole},
+	{"DELETE", "/api/reading-logs/:id/sessions/:sessionId", anyRole},
+	{"GET", "/api/books/search", anyRole},
+	{"GET", "/api/children/:id/summary", anyRole},
+	{"GET", "/api/children/:id/streaks", anyRole},
+	{"GET", "/api/achievements", anyRole},
+	{"GET", "/api/children/:id/achievements", anyRole},
+	{"GET", "/api/children/:id/goals", anyRole},
+	{"POST", "/api/children/:id/goals", []string{parentRole}},
+	{"PATCH", "/api/children/:id/goals/:goalId", []string{parentRole}},
+	{"DELETE", "/api/children/:id/goals/:goalId", []string{parentRole}},
+	{"GET", "/api/point-rules", anyRole},
+	{"PUT", "/api/point-rules", []string{parentRole}},
+	{"GET", "/api/children/:id/points", anyRole},
+	{"POST", "/api/children/:id/points/adjustments", []string{parentRole}},
+	{"GET", "/api/rewards", anyRole},
+	{"POST", "/api/rewards", []string{parentRole}},
+	{"PATCH", "/api/rewards/:id", []string{parentRole}},
+	{"DELETE", "/api/rewards/:id", []string{parentRole}},
+	{"POST", "/api/rewards/:id/redeem", []string{childRole}},
+	{"GET", "/api/redemptions", anyRole},
+	{"POST", "/api/redemptions/:id/approve", []string{parentRole}},
+	{"POST", "/api/redemptions/:id/reject", []string{parentRole}},
+}
+
+// publicRoutes need no token, so they have no role check
+var publicRoutes = map[string]bool{
+	"POST /api/auth/parent/login":           true,
+	"POST /api/auth/parent/register":        true,
+	"POST /api/auth/teacher/register":       true,
+	"POST /api/auth/teacher/login":          true,
+	"POST /api/auth/admin/login":            true,
+	"POST /api/auth/child/login":            true,
+	"POST /api/auth/family/children":        true,
+	"POST /api/auth/refresh":                true,
+	"POST /api/auth/verify-email/request":   true,
+	"POST /api/auth/verify-email/confirm":   true,
+	"POST /api/auth/password-reset/request": true,
+	"POST /api/auth/password-reset/confirm": true,
+}
+
+// concretePath fills route parameters with an ID that exists in no family
+func concretePath(path string) string {
+	out := []byte{}
+	for i := 0; i < len(path); i++ {
+		if path[i] == ':' {
+			for i < len(path) && path[i] != '/' {
+				i++
+			}
+			out = append(out, "999"...)
+			if i < len(path) {
+				out = append(out, '/')
+			}
+			continue
+		}
+		out = append(out, path[i])
+	}
+	return string(out)
+}
+
+func TestPolicy_EveryProtectedRouteDeclaresRoles(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	covered := map[string]bool{}
+	for _, route := range routeRoles {
+		covered[route.method+" "+route.path] = true
+	}
+	for _, route := range srv.Router.Routes() {
+		key := route.Method + " " + route.Path
+		if !publicRoutes[key] {
+			assert.True(t, covered[key], "%s is missing from the role matrix", key)
+		}
+		delete(covered, key)
+	}
+	assert.Empty(t, covered, "matrix lists routes the server doesn't register")
+}
+
+func TestPolicy_RouteRoleMatrix(t *testing.T) {
+	srv, perform := newTestServer(t)
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	tokens := map[string]string{
+		parentRole: login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"}).Token,
+		childRole:  login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "5678")).Token,
+	}
+	tests.CreateTestTeacher(srv.DB, "Ms Frizzle", "frizzle@example.com", "password123")
+	tokens[teacherRole] = login(t, perform, "/api/auth/teacher/login", gin.H{"email": "frizzle@example.com", "password": "password123"}).Token
+	tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	tokens[adminRole] = login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token
+
+	// Log out last so the tokens stay valid for the other routes
+	routes := append(routeRoles[1:len(routeRoles):len(routeRoles)], routeRoles[0])
+	assert.Equal(t, "/api/auth/logout", routes[len(routes)-1].path)
+
+	for _, route := range routes {
+		allowed := map[string]bool{}
+		for _, role := range route.roles {
+			allowed[role] = true
+		}
+
+		for _, role := range signedIn {
+			resp := perform(route.method, concretePath(route.path), tokens[role], gin.H{})
+			var body struct {
+				Error string `json:"error"`
+			}
+			_ = json.Unmarshal(resp.Body.Bytes(), &body)
+			deniedByRole := resp.Code == http.StatusForbidden && body.Error == policy.ErrWrongRole
+			assert.Equal(t, !allowed[role], deniedByRole, "%s %s as %s: %d %s", route.method, route.path, role, resp.Code, body.Error)
+			assert.NotEqual(t, http.StatusUnauthorized, resp.Code, "%s %s as %s", route.method, route.path, role)
+		}
+	}
+}
+
+func TestPolicy_TokenWithoutClaimsIsRejected(t *testing.T) {
+	_, perform := newTestServer(t)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+	resp := perform("GET", "/api/children", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
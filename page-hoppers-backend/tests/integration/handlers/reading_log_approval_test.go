@@ -0,0 +1,236 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+// newApprovalRouter wires the routes a child logs through and a parent reviews through
+func newApprovalRouter(db *gorm.DB, userID uint, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, userID, role))
+
+	auth := handlers.NewAuthHandler(db, []byte("test-secret"))
+	router.PATCH("/children/:id/settings", auth.UpdateChildSettings)
+
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.POST("/reading-logs", handler.CreateReadingLog)
+	router.PATCH("/reading-logs/:id", handler.UpdateReadingLog)
+	router.POST("/reading-logs/:id/transitions", handler.TransitionReadingLog)
+	router.POST("/reading-logs/:id/sessions", handler.CreateReadingSession)
+	router.PATCH("/reading-logs/:id/sessions/:sessionId", handler.UpdateReadingSession)
+	router.GET("/reading-logs/pending", handler.GetPendingReadingLogs)
+	router.POST("/reading-logs/:id/approve", handler.ApproveReadingLog)
+	router.POST("/reading-logs/:id/reject", handler.RejectReadingLog)
+	router.GET("/children/:id/summary", handler.GetReadingSummary)
+	return router
+}
+
+func TestReadingLogApproval_ParentApprovesBeforeItCounts(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	parentRouter := newApprovalRouter(db, parent.ID, "parent")
+	childRouter := newApprovalRouter(db, child.ID, "child")
+	summaryPath := fmt.Sprintf("/children/%d/summary?period=this_year", child.ID)
+
+	resp := tests.PerformJSON(parentRouter, "PATCH", fmt.Sprintf("/children/%d/settings", child.ID), gin.H{"requires_log_approval": true})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = tests.PerformJSON(childRouter, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "completed", "date": "2025-01-09"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var logged handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &logged))
+	assert.Equal(t, models.ApprovalPending, logged.ApprovalStatus)
+	assert.Zero(t, logged.PointsEarned)
+	assert.Empty(t, logged.NewAchievements)
+
+	resp = tests.PerformJSON(parentRouter, "GET", summaryPath, nil)
+	summary := tests.ParseSummary(t, resp)
+	assert.Equal(t, float64(0), summary["totalCompletedBooks"])
+	assert.Equal(t, float64(1), summary["pendingApproval"])
+
+	resp = tests.PerformJSON(parentRouter, "GET", "/reading-logs/pending", nil)
+	var pending []handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &pending))
+	assert.Len(t, pending, 1)
+
+	approvePath := fmt.Sprintf("/reading-logs/%d/approve", logged.ID)
+	resp = tests.PerformJSON(childRouter, "POST", approvePath, nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code, "children can't approve their own logs")
+
+	resp = tests.PerformJSON(parentRouter, "POST", approvePath, gin.H{"comment": "well done"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var approved handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &approved))
+	assert.Equal(t, models.ApprovalApproved, approved.ApprovalStatus)
+	assert.Equal(t, "well done", approved.ReviewComment)
+	assert.Equal(t, 10, approved.PointsEarned)
+	assert.Len(t, approved.NewAchievements, 1)
+
+	resp = tests.PerformJSON(parentRouter, "GET", summaryPath, nil)
+	summary = tests.ParseSummary(t, resp)
+	assert.Equal(t, float64(1), summary["totalCompletedBooks"])
+	assert.Equal(t, float64(0), summary["pendingApproval"])
+
+	// Moving the log again sends it back for approval
+	resp = tests.PerformJSON(childRouter, "POST", fmt.Sprintf("/reading-logs/%d/transitions", logged.ID), gin.H{"status": "re_reading"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &logged))
+	assert.Equal(t, models.ApprovalPending, logged.ApprovalStatus)
+
+	resp = tests.PerformJSON(parentRouter, "POST", fmt.Sprintf("/reading-logs/%d/reject", logged.ID), gin.H{"comment": "you finished it yesterday"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(parentRouter, "POST", fmt.Sprintf("/reading-logs/%d/reject", logged.ID), nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestReadingLogApproval_ChildChangesToAnApprovedLogWaitAgain(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	db.Model(child).Update("requires_log_approval", true)
+	log := models.ReadingLog{ChildID: child.ID, Title: "Holes", Status: models.StatusReading, Date: time.Now(), ApprovalStatus: models.ApprovalApproved}
+	db.Create(&log)
+	parentRouter := newApprovalRouter(db, parent.ID, "parent")
+	childRouter := newApprovalRouter(db, child.ID, "child")
+	sessionsPath := fmt.Sprintf("/reading-logs/%d/sessions", log.ID)
+	approvePath := fmt.Sprintf("/reading-logs/%d/approve", log.ID)
+	balance := func() int {
+		points, err := repository.GetPointsBalance(db, child.ID)
+		assert.NoError(t, err)
+		return points.Balance
+	}
+
+	resp := tests.PerformJSON(childRouter, "POST", sessionsPath, gin.H{"minutes": 30})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var session models.ReadingSession
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+	db.First(&log, log.ID)
+	assert.Equal(t, models.ApprovalPending, log.ApprovalStatus, "a new session sends the log back for approval")
+	assert.Zero(t, balance(), "and earns nothing until then")
+
+	sessionPath := fmt.Sprintf("%s/%d", sessionsPath, session.ID)
+	resp = tests.PerformJSON(childRouter, "PATCH", sessionPath, gin.H{"minutes": 50})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, http.StatusOK, tests.PerformJSON(parentRouter, "POST", approvePath, nil).Code)
+	assert.Equal(t, 5, balance(), "approval pays the session as it is now")
+
+	resp = tests.PerformJSON(childRouter, "PATCH", sessionPath, gin.H{"minutes": 300})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	db.First(&log, log.ID)
+	assert.Equal(t, models.ApprovalPending, log.ApprovalStatus, "editing a session sends it back too")
+	assert.Zero(t, balance(), "the inflated session isn't paid while it waits")
+	assert.Equal(t, http.StatusOK, tests.PerformJSON(parentRouter, "POST", approvePath, nil).Code)
+
+	resp = tests.PerformJSON(childRouter, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"title": "Holes (again)"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	db.First(&log, log.ID)
+	assert.Equal(t, models.ApprovalPending, log.ApprovalStatus, "so does editing the log")
+	assert.Equal(t, http.StatusOK, tests.PerformJSON(parentRouter, "POST", approvePath, nil).Code)
+
+	resp = tests.PerformJSON(parentRouter, "PATCH", sessionPath, gin.H{"minutes": 20})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	db.First(&log, log.ID)
+	assert.Equal(t, models.ApprovalApproved, log.ApprovalStatus, "a parent's own edits don't need approving")
+	assert.Equal(t, 2, balance())
+}
+
+func TestReadingLogApproval_OffByDefault(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	resp := tests.PerformJSON(newApprovalRouter(db, child.ID, "child"), "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "completed", "date": "2025-01-09"})
+	var logged handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &logged))
+	assert.Empty(t, logged.ApprovalStatus)
+
+	resp = tests.PerformJSON(newApprovalRouter(db, parent.ID, "parent"), "POST", fmt.Sprintf("/reading-logs/%d/approve", logged.ID), nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestReadingLogApproval_EditingALogThatNeverNeededApprovalKeepsItCounted(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	router := newApprovalRouter(db, child.ID, "child")
+
+	today := time.Now().Format("2006-01-02")
+	resp := tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "completed", "date": today})
+	var logged handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &logged))
+
+	resp = tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", logged.ID), gin.H{"total_pages": 100})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	summary, err := repository.GetReadingSummary(db, child.ID, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.TotalCompletedBooks)
+	streaks, err := repository.GetReadingStreaks(db, child.ID, time.Now(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, streaks.CurrentStreak)
+}
+
+func TestReadingLogApproval_OtherFamilyIsNotFound(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+	log := models.ReadingLog{ChildID: child.ID, Title: "Holes", Status: models.StatusReading, ApprovalStatus: models.ApprovalPending}
+	db.Create(&log)
+
+	router := newApprovalRouter(db, other.ID, "parent")
+	resp := tests.PerformJSON(router, "POST", fmt.Sprintf("/reading-logs/%d/approve", log.ID), nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = tests.PerformJSON(router, "PATCH", fmt.Sprintf("/children/%d/settings", child.ID), gin.H{"requires_log_approval": false})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = tests.PerformJSON(router, "GET", "/reading-logs/pending", nil)
+	var pending []handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &pending))
+	assert.Empty(t, pending)
+}
+
+func TestReadingLogApproval_TransitionIsUndoneIfItCanNotBeHeld(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	db.Model(child).Update("requires_log_approval", true)
+	log := models.ReadingLog{ChildID: child.ID, Title: "Holes", Status: models.StatusReading, Date: time.Now(), ApprovalStatus: models.ApprovalApproved}
+	db.Create(&log)
+	childRouter := newApprovalRouter(db, child.ID, "child")
+
+	// Fail only the write that holds the log for approval
+	assert.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_hold", func(tx *gorm.DB) {
+		for _, column := range tx.Statement.Selects {
+			if column == "approval_status" {
+				tx.AddError(fmt.Errorf("hold failed"))
+			}
+		}
+	}))
+
+	resp := tests.PerformJSON(childRouter, "POST", fmt.Sprintf("/reading-logs/%d/transitions", log.ID), gin.H{"status": "completed"})
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+
+	var saved models.ReadingLog
+	db.First(&saved, log.ID)
+	assert.Equal(t, models.StatusReading, saved.Status, "the move isn't kept without its hold")
+	assert.Equal(t, models.ApprovalApproved, saved.ApprovalStatus)
+	var transitions int64
+	db.Model(&models.ReadingLogTransition{}).Where("reading_log_id = ?", log.ID).Count(&transitions)
+	assert.Zero(t, transitions)
+}
@@ -0,0 +1,200 @@
+package integration_handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+// newCRUDRouter wires the reading log edit routes for a single authenticated user
+func newCRUDRouter(db *gorm.DB, userID uint, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, userID, role))
+
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.PATCH("/reading-logs/:id", handler.UpdateReadingLog)
+	router.DELETE("/reading-logs/:id", handler.DeleteReadingLog)
+	router.GET("/reading-logs/trash", handler.GetDeletedReadingLogs)
+	router.POST("/reading-logs/:id/restore", handler.RestoreReadingLog)
+	return router
+}
+
+func createLog(db *gorm.DB, childID uint, title string) *models.ReadingLog {
+	log := &models.ReadingLog{ChildID: childID, Title: title, Status: models.StatusReading, Date: time.Now()}
+	db.Create(log)
+	return log
+}
+
+func TestUpdateReadingLog_ChildEditsOwnLog(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	log := createLog(db, child.ID, "Matlida")
+
+	router := newCRUDRouter(db, child.ID, "child")
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"title": "Matilda"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var updated models.ReadingLog
+	db.First(&updated, log.ID)
+	assert.Equal(t, "Matilda", updated.Title)
+	assert.Equal(t, models.StatusReading, updated.Status, "fields not in the request are unchanged")
+}
+
+func TestUpdateReadingLog_ParentEditsChildLog(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	log := createLog(db, child.ID, "The BFG")
+
+	router := newCRUDRouter(db, parent.ID, "parent")
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"author": "Roald Dahl"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var updated models.ReadingLog
+	db.First(&updated, log.ID)
+	assert.Equal(t, "Roald Dahl", updated.Author)
+}
+
+func TestUpdateReadingLog_RetitlingAKeyedLogMovesItToAnotherBook(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	coverID := 42
+	book, _ := repository.FindOrCreateBook(db, models.Book{Title: "The BFG", Author: "Roald Dahl", OpenLibraryKey: "/works/OL45804W", CoverID: &coverID})
+	log := &models.ReadingLog{ChildID: child.ID, Status: models.StatusReading, Date: time.Now()}
+	repository.LinkReadingLogToBook(log, book)
+	db.Create(log)
+
+	router := newCRUDRouter(db, child.ID, "child")
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"title": "Matilda"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var updated models.ReadingLog
+	db.First(&updated, log.ID)
+	assert.Equal(t, "Matilda", updated.Title)
+	assert.Equal(t, "Roald Dahl", updated.Author)
+	assert.NotEqual(t, book.ID, *updated.BookID)
+	assert.Empty(t, updated.OpenLibraryKey, "the old key named the old book")
+	assert.Nil(t, updated.CoverID)
+
+	db.First(book, book.ID)
+	assert.Equal(t, "The BFG", book.Title, "the shared catalog entry isn't renamed")
+}
+
+func TestUpdateReadingLog_ChangingOnlyTheCoverKeepsTheBook(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	coverID := 42
+	book, _ := repository.FindOrCreateBook(db, models.Book{Title: "The BFG", Author: "Roald Dahl", OpenLibraryKey: "/works/OL45804W", CoverID: &coverID})
+	log := &models.ReadingLog{ChildID: child.ID, Status: models.StatusReading, Date: time.Now()}
+	repository.LinkReadingLogToBook(log, book)
+	db.Create(log)
+
+	router := newCRUDRouter(db, child.ID, "child")
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"cover_id": 7, "title": "The BFG"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var updated models.ReadingLog
+	db.First(&updated, log.ID)
+	if assert.NotNil(t, updated.CoverID) {
+		assert.Equal(t, 7, *updated.CoverID, "the cover sent isn't overwritten by the book's")
+	}
+	assert.Equal(t, book.ID, *updated.BookID)
+	assert.Equal(t, "/works/OL45804W", updated.OpenLibraryKey)
+}
+
+func TestUpdateReadingLog_OtherFamilyIsNotFound(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	otherParent := tests.CreateTestParent(db, "Alice", "alice@example.com", "password123")
+	otherChild := tests.CreateTestChild(db, "Dora", 7, otherParent.ID, "1111")
+	log := createLog(db, child.ID, "The BFG")
+
+	for name, router := range map[string]*gin.Engine{
+		"other parent": newCRUDRouter(db, otherParent.ID, "parent"),
+		"other child":  newCRUDRouter(db, otherChild.ID, "child"),
+	} {
+		resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", log.ID), gin.H{"title": "Hacked"})
+		assert.Equal(t, http.StatusNotFound, resp.Code, name)
+
+		resp = tests.PerformJSON(router, "DELETE", fmt.Sprintf("/reading-logs/%d", log.ID), nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code, name)
+	}
+
+	var unchanged models.ReadingLog
+	assert.NoError(t, db.First(&unchanged, log.ID).Error)
+	assert.Equal(t, "The BFG", unchanged.Title)
+}
+
+func TestDeleteAndRestoreReadingLog(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	log := createLog(db, child.ID, "Matilda")
+
+	childRouter := newCRUDRouter(db, child.ID, "child")
+	resp := tests.PerformJSON(childRouter, "DELETE", fmt.Sprintf("/reading-logs/%d", log.ID), nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	var count int64
+	db.Model(&models.ReadingLog{}).Where("child_id = ?", child.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "deleted log is hidden from normal queries")
+
+	parentRouter := newCRUDRouter(db, parent.ID, "parent")
+	resp = tests.PerformJSON(parentRouter, "GET", fmt.Sprintf("/reading-logs/trash?child_id=%d", child.ID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Matilda")
+
+	resp = tests.PerformJSON(parentRouter, "POST", fmt.Sprintf("/reading-logs/%d/restore", log.ID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	db.Model(&models.ReadingLog{}).Where("child_id = ?", child.ID).Count(&count)
+	assert.Equal(t, int64(1), count, "restored log is visible again")
+
+	resp = tests.PerformJSON(parentRouter, "POST", fmt.Sprintf("/reading-logs/%d/restore", log.ID), nil)
+	assert.Equal(t, http.StatusConflict, resp.Code, "restoring a live log is rejected")
+}
+
+func TestReadingLog_KeepsOneLogPerBook(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	linkedLog := func(title string) *models.ReadingLog {
+		log := createLog(db, child.ID, title)
+		book, err := repository.FindOrCreateBook(db, models.Book{Title: title})
+		assert.NoError(t, err)
+		repository.LinkReadingLogToBook(log, book)
+		db.Omit("Book").Save(log)
+		return log
+	}
+	matilda, holes := linkedLog("Matilda"), linkedLog("Holes")
+	router := newCRUDRouter(db, child.ID, "child")
+
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/reading-logs/%d", holes.ID), gin.H{"title": "Matilda"})
+	assert.Equal(t, http.StatusConflict, resp.Code, "retitling onto a book the child already has would duplicate it")
+	assert.Contains(t, resp.Body.String(), fmt.Sprintf(`"reading_log_id":%d`, matilda.ID))
+	db.First(holes, holes.ID)
+	assert.Equal(t, "Holes", holes.Title)
+
+	resp = tests.PerformJSON(router, "DELETE", fmt.Sprintf("/reading-logs/%d", matilda.ID), nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	again := linkedLog("Matilda")
+
+	resp = tests.PerformJSON(router, "POST", fmt.Sprintf("/reading-logs/%d/restore", matilda.ID), nil)
+	assert.Equal(t, http.StatusConflict, resp.Code, "the book was logged again while this one was in the trash")
+	assert.Contains(t, resp.Body.String(), fmt.Sprintf(`"reading_log_id":%d`, again.ID))
+}
@@ -0,0 +1,107 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/books"
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// newISBNRouter wires both create endpoints against a fake Open Library that knows one ISBN
+func newISBNRouter(t *testing.T, db *gorm.DB, childID uint) (*gin.Engine, *int32) {
+	var lookups int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("isbn") != "9780142410370" {
+			w.Write([]byte(`{"numFound": 0, "docs": []}`))
+			return
+		}
+		w.Write([]byte(`{"numFound": 1, "docs": [
+			{"key": "/works/OL45804W", "title": "Matilda", "author_name": ["Roald Dahl"], "cover_i": 8314135, "isbn": ["9780142410370", "0142410373"], "number_of_pages_median": 240}
+		]}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	search := books.NewSearchService(db, books.NewOpenLibraryProvider(upstream.URL), time.Hour)
+	handler := handlers.NewReadingLogHandler(db, search)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, childID, "child"))
+	router.POST("/reading-logs", handler.CreateReadingLog)
+	router.POST("/reading-logs/by-isbn", handler.CreateReadingLogByISBN)
+	return router, &lookups
+}
+
+func TestCreateReadingLogByISBN_ResolvesMetadata(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	router, lookups := newISBNRouter(t, db, child.ID)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs/by-isbn", gin.H{"isbn": "0-14-241037-3", "status": "reading", "date": "2025-04-01"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "Matilda", body.Title)
+	assert.Equal(t, "Roald Dahl", body.Author)
+	assert.Equal(t, "9780142410370", body.ISBN13)
+	assert.Equal(t, "0142410373", body.ISBN10)
+	assert.Equal(t, 8314135, *body.CoverID)
+	assert.Equal(t, 240, *body.TotalPages, "page count comes from the provider")
+
+	// A second scan of the same book hits the catalog, not the provider, and moves the same log along
+	resp = tests.PerformJSON(router, "POST", "/reading-logs/by-isbn", gin.H{"isbn": "9780142410370", "status": "completed", "date": "2025-04-09"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(lookups))
+
+	var count int64
+	db.Model(&models.ReadingLog{}).Where("child_id = ?", child.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCreateReadingLogByISBN_Errors(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	router, lookups := newISBNRouter(t, db, child.ID)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs/by-isbn", gin.H{"isbn": "0142410374", "status": "reading", "date": "2025-04-01"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "bad check digit")
+	assert.Equal(t, int32(0), atomic.LoadInt32(lookups), "invalid ISBNs never reach the provider")
+
+	resp = tests.PerformJSON(router, "POST", "/reading-logs/by-isbn", gin.H{"isbn": "9780804429573", "status": "reading", "date": "2025-04-01"})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestCreateReadingLog_ValidatesISBNFields(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	router, _ := newISBNRouter(t, db, child.ID)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "reading", "date": "2025-04-01", "isbn13": "9780142410371"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "reading", "date": "2025-04-01", "isbn10": "0142410373", "isbn13": "9780804429573"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "ISBN-10 and ISBN-13 must be the same book")
+
+	resp = tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "reading", "date": "2025-04-01", "isbn10": "0142410373"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var book models.Book
+	assert.NoError(t, db.Where("isbn = ?", "9780142410370").First(&book).Error, "ISBN-10 is stored as ISBN-13")
+}
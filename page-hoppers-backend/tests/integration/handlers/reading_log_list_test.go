@@ -0,0 +1,55 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+func TestGetChildReadingLogs_PagesAndFilters(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"Matilda", "The BFG", "Wonder"} {
+		db.Create(&models.ReadingLog{ChildID: child.ID, Title: title, Status: models.StatusCompleted, Date: day.AddDate(0, 0, i)})
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, parent.ID, "parent"))
+	router.GET("/children/reading-logs", handlers.NewReadingLogHandler(db, nil).GetChildReadingLogs)
+
+	base := fmt.Sprintf("/children/reading-logs?child_id=%d", child.ID)
+	resp := tests.PerformJSON(router, "GET", base+"&sort=title&limit=2", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var page handlers.ReadingLogListResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page))
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "Matilda", page.Items[0].Title)
+	assert.NotEmpty(t, page.NextCursor)
+
+	resp = tests.PerformJSON(router, "GET", base+"&sort=title&limit=2&cursor="+page.NextCursor, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	page = handlers.ReadingLogListResponse{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Wonder", page.Items[0].Title)
+	assert.Empty(t, page.NextCursor, "last page")
+
+	for _, query := range []string{"&status=finished", "&from=May", "&from=2025-05-03&to=2025-05-01", "&sort=author", "&order=up", "&limit=0", "&cursor=bogus"} {
+		resp = tests.PerformJSON(router, "GET", base+query, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
@@ -0,0 +1,117 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+func TestReadingLogLifecycle(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, child.ID, "child"))
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.POST("/reading-logs", handler.CreateReadingLog)
+	router.POST("/reading-logs/:id/transitions", handler.TransitionReadingLog)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "want_to_read", "date": "2025-01-02"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var log models.ReadingLog
+	assert.NoError(t, db.Where("child_id = ?", child.ID).First(&log).Error)
+
+	path := fmt.Sprintf("/reading-logs/%d/transitions", log.ID)
+	for _, step := range []struct {
+		status string
+		code   int
+	}{
+		{models.StatusReading, http.StatusOK},
+		{models.StatusPaused, http.StatusOK},
+		{models.StatusWantToRead, http.StatusConflict},
+		{models.StatusReading, http.StatusOK},
+		{models.StatusCompleted, http.StatusOK},
+		{models.StatusPaused, http.StatusConflict},
+		{models.StatusReReading, http.StatusOK},
+	} {
+		resp := tests.PerformJSON(router, "POST", path, gin.H{"status": step.status, "date": "2025-01-10"})
+		assert.Equal(t, step.code, resp.Code, "moving to %s", step.status)
+	}
+
+	transitions, err := repository.GetReadingLogTransitions(db, log.ID)
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 6, "creation plus five accepted transitions")
+	assert.Equal(t, models.StatusCompleted, transitions[4].ToStatus)
+	assert.Equal(t, models.StatusReReading, transitions[5].ToStatus)
+
+	var count int64
+	db.Model(&models.ReadingLog{}).Where("child_id = ?", child.ID).Count(&count)
+	assert.Equal(t, int64(1), count, "one log row for the book")
+}
+
+func TestCreateReadingLog_LegacyStartedThenCompletedKeepsOneRow(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, child.ID, "child"))
+	router.POST("/reading-logs", handlers.NewReadingLogHandler(db, nil).CreateReadingLog)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "The BFG", "status": "started", "date": "2025-03-01"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "the bfg", "status": "completed", "date": "2025-03-09"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "The BFG", "status": "completed", "date": "2025-03-10"})
+	assert.Equal(t, http.StatusConflict, resp.Code, "a completed book can't be completed again without re-reading")
+
+	var logs []models.ReadingLog
+	db.Where("child_id = ?", child.ID).Find(&logs)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, models.StatusCompleted, logs[0].Status)
+	assert.NotNil(t, logs[0].StartedAt)
+	assert.NotNil(t, logs[0].CompletedAt)
+}
+
+func TestCreateReadingLog_ReturnsNewAchievements(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, child.ID, "child"))
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.POST("/reading-logs", handler.CreateReadingLog)
+	router.GET("/children/:id/achievements", handler.GetChildAchievements)
+
+	resp := tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "reading", "date": "2025-01-02"})
+	var body handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Empty(t, body.NewAchievements)
+
+	resp = tests.PerformJSON(router, "POST", "/reading-logs", gin.H{"title": "Matilda", "status": "completed", "date": "2025-01-09"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Len(t, body.NewAchievements, 1)
+	assert.Equal(t, "first_book", body.NewAchievements[0].Rule.Key)
+
+	resp = tests.PerformJSON(router, "GET", fmt.Sprintf("/children/%d/achievements", child.ID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var earned []models.ChildAchievement
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &earned))
+	assert.Len(t, earned, 1)
+}
@@ -0,0 +1,120 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+func TestReadingSessions_TrackProgress(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+
+	totalPages := 200
+	log := &models.ReadingLog{ChildID: child.ID, Title: "Matilda", Status: models.StatusReading, Date: time.Now(), TotalPages: &totalPages}
+	db.Create(log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, child.ID, "child"))
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.GET("/reading-logs/:id/sessions", handler.GetReadingSessions)
+	router.POST("/reading-logs/:id/sessions", handler.CreateReadingSession)
+	router.PATCH("/reading-logs/:id/sessions/:sessionId", handler.UpdateReadingSession)
+	router.DELETE("/reading-logs/:id/sessions/:sessionId", handler.DeleteReadingSession)
+
+	path := fmt.Sprintf("/reading-logs/%d/sessions", log.ID)
+
+	resp := tests.PerformJSON(router, "POST", path, gin.H{"start_page": 0, "end_page": 30, "minutes": 20, "read_at": "2025-02-01"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"start_page": 30, "end_page": 50, "minutes": 15})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var second models.ReadingSession
+	json.Unmarshal(resp.Body.Bytes(), &second)
+	// Pages are bookmarks: picking up at 50 and stopping there again reads no pages
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"start_page": 50, "end_page": 50, "minutes": 5})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"start_page": 60, "end_page": 40})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "end before start is rejected")
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"start_page": 190, "end_page": 250})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "pages past the end of the book are rejected")
+
+	resp = tests.PerformJSON(router, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var listed handlers.ReadingSessionsResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &listed))
+	assert.Len(t, listed.Sessions, 3)
+	assert.Equal(t, 50, listed.Progress.PagesRead)
+	assert.Equal(t, 40, listed.Progress.MinutesRead)
+	assert.Equal(t, 50, listed.Progress.CurrentPage)
+	assert.NotNil(t, listed.Progress.PercentComplete)
+	assert.InDelta(t, 25.0, *listed.Progress.PercentComplete, 0.001)
+
+	resp = tests.PerformJSON(router, "PATCH", fmt.Sprintf("%s/%d", path, second.ID), gin.H{"minutes": 25})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(router, "DELETE", fmt.Sprintf("%s/%d", path, second.ID), nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	var remaining int64
+	db.Model(&models.ReadingSession{}).Where("reading_log_id = ?", log.ID).Count(&remaining)
+	assert.Equal(t, int64(2), remaining)
+}
+
+func TestReadingSessions_LimitedAndRepriced(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	log := &models.ReadingLog{ChildID: child.ID, Title: "Matilda", Status: models.StatusReading, Date: time.Now()}
+	db.Create(log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, child.ID, "child"))
+	handler := handlers.NewReadingLogHandler(db, nil)
+	router.POST("/reading-logs/:id/sessions", handler.CreateReadingSession)
+	router.PATCH("/reading-logs/:id/sessions/:sessionId", handler.UpdateReadingSession)
+	router.DELETE("/reading-logs/:id/sessions/:sessionId", handler.DeleteReadingSession)
+	path := fmt.Sprintf("/reading-logs/%d/sessions", log.ID)
+
+	// Nothing one sitting couldn't be, so points can't be minted
+	resp := tests.PerformJSON(router, "POST", path, gin.H{"minutes": 1000000})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"start_page": 0, "end_page": 5000})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"minutes": 20, "read_at": time.Now().AddDate(0, 0, 2).Format("2006-01-02")})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "sessions can't be in the future")
+
+	balance := func() int {
+		b, err := repository.GetPointsBalance(db, child.ID)
+		assert.NoError(t, err)
+		return b.Balance
+	}
+
+	resp = tests.PerformJSON(router, "POST", path, gin.H{"minutes": 60})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var session models.ReadingSession
+	json.Unmarshal(resp.Body.Bytes(), &session)
+	assert.Equal(t, 6, balance(), "1 point per 10 minutes by default")
+
+	// Editing the session changes what it earned, and deleting it takes the points back
+	resp = tests.PerformJSON(router, "PATCH", fmt.Sprintf("%s/%d", path, session.ID), gin.H{"minutes": 20})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2, balance())
+	resp = tests.PerformJSON(router, "DELETE", fmt.Sprintf("%s/%d", path, session.ID), nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, 0, balance())
+}
@@ -0,0 +1,63 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+func TestGetReadingStreaks_UsesFamilyGraceDays(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	otherParent := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+
+	// Read yesterday and three days ago, missing the day between
+	today := time.Now().UTC()
+	log := models.ReadingLog{ChildID: child.ID, Title: "Matilda", Status: models.StatusWantToRead, Date: today.AddDate(0, 0, -5)}
+	db.Create(&log)
+	for _, daysAgo := range []int{1, 3} {
+		db.Create(&models.ReadingSession{ChildID: child.ID, ReadingLogID: log.ID, Minutes: 15, ReadAt: today.AddDate(0, 0, -daysAgo)})
+	}
+
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewReadingLogHandler(db, nil)
+	auth := handlers.NewAuthHandler(db, []byte("secret"))
+	router := gin.New()
+	router.Use(tests.AuthAs(db, parent.ID, "parent"))
+	router.GET("/children/:id/streaks", handler.GetReadingStreaks)
+	router.PATCH("/parent/settings", auth.UpdateParentSettings)
+
+	path := fmt.Sprintf("/children/%d/streaks", child.ID)
+	var streaks repository.ReadingStreaks
+	resp := tests.PerformJSON(router, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &streaks))
+	assert.Equal(t, 1, streaks.CurrentStreak)
+	assert.Equal(t, 2, streaks.DaysReadLast30)
+
+	resp = tests.PerformJSON(router, "PATCH", "/parent/settings", gin.H{"streak_grace_days": 9})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = tests.PerformJSON(router, "PATCH", "/parent/settings", gin.H{"streak_grace_days": 1})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = tests.PerformJSON(router, "GET", path, nil)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &streaks))
+	assert.Equal(t, 2, streaks.CurrentStreak, "the grace day bridges the gap")
+
+	otherRouter := gin.New()
+	otherRouter.Use(tests.AuthAs(db, otherParent.ID, "parent"))
+	otherRouter.GET("/children/:id/streaks", handler.GetReadingStreaks)
+	resp = tests.PerformJSON(otherRouter, "GET", path, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code, "other families can't see the child's streaks")
+}
@@ -0,0 +1,220 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/tests"
+)
+
+// newRewardsRouter wires the points, reward and reading log routes for a single authenticated user
+func newRewardsRouter(db *gorm.DB, userID uint, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tests.AuthAs(db, userID, role))
+
+	readingLogs := handlers.NewReadingLogHandler(db, nil)
+	router.POST("/reading-logs", readingLogs.CreateReadingLog)
+	router.DELETE("/reading-logs/:id", readingLogs.DeleteReadingLog)
+
+	handler := handlers.NewRewardsHandler(db)
+	router.GET("/point-rules", handler.GetPointRules)
+	router.PUT("/point-rules", handler.SetPointRules)
+	router.GET("/children/:id/points", handler.GetChildPoints)
+	router.POST("/children/:id/points/adjustments", handler.AdjustChildPoints)
+	router.GET("/rewards", handler.GetRewards)
+	router.POST("/rewards", handler.CreateReward)
+	router.PATCH("/rewards/:id", handler.UpdateReward)
+	router.POST("/rewards/:id/redeem", handler.RedeemReward)
+	router.GET("/redemptions", handler.GetRedemptions)
+	router.POST("/redemptions/:id/approve", handler.ApproveRedemption)
+	router.POST("/redemptions/:id/reject", handler.RejectRedemption)
+	return router
+}
+
+func TestRewards_EarnRequestAndApprove(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	parentRouter := newRewardsRouter(db, parent.ID, "parent")
+	childRouter := newRewardsRouter(db, child.ID, "child")
+
+	resp := tests.PerformJSON(parentRouter, "PUT", "/point-rules", []gin.H{{"event": "book_completed", "points": 30}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = tests.PerformJSON(childRouter, "POST", "/rewards", gin.H{"name": "Sweets", "cost": 1})
+	assert.Equal(t, http.StatusForbidden, resp.Code, "children can't create rewards")
+	resp = tests.PerformJSON(parentRouter, "POST", "/rewards", gin.H{"name": "30 min screen time", "cost": 50})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var reward models.Reward
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reward))
+	redeemPath := fmt.Sprintf("/rewards/%d/redeem", reward.ID)
+
+	resp = tests.PerformJSON(childRouter, "POST", redeemPath, nil)
+	assert.Equal(t, http.StatusConflict, resp.Code, "no points yet")
+
+	for _, title := range []string{"Matilda", "Holes"} {
+		resp = tests.PerformJSON(childRouter, "POST", "/reading-logs", gin.H{"title": title, "status": "completed", "date": "2025-01-09"})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var log handlers.ReadingLogResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &log))
+		assert.Equal(t, 30, log.PointsEarned)
+	}
+
+	resp = tests.PerformJSON(childRouter, "POST", redeemPath, nil)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var redemption models.RewardRedemption
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &redemption))
+	assert.Equal(t, "30 min screen time", redemption.RewardName)
+
+	resp = tests.PerformJSON(parentRouter, "GET", "/redemptions?status=requested", nil)
+	var pending []models.RewardRedemption
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &pending))
+	assert.Len(t, pending, 1)
+
+	approvePath := fmt.Sprintf("/redemptions/%d/approve", redemption.ID)
+	resp = tests.PerformJSON(childRouter, "POST", approvePath, nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code, "children can't approve their own requests")
+	resp = tests.PerformJSON(parentRouter, "POST", approvePath, gin.H{"comment": "after homework"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(parentRouter, "POST", approvePath, nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = tests.PerformJSON(childRouter, "GET", fmt.Sprintf("/children/%d/points", child.ID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var points handlers.PointsResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &points))
+	assert.Equal(t, 60, points.Earned)
+	assert.Equal(t, 10, points.Balance)
+	assert.Len(t, points.Entries, 3)
+}
+
+func TestRewards_DeletingAndLoggingABookAgainDoesNotPayTwice(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	childRouter := newRewardsRouter(db, child.ID, "child")
+
+	for i := 0; i < 3; i++ {
+		resp := tests.PerformJSON(childRouter, "POST", "/reading-logs", gin.H{"title": "Matilda", "author": "Roald Dahl", "status": "completed", "date": "2025-01-09"})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var log handlers.ReadingLogResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &log))
+		if i == 0 {
+			assert.Equal(t, 10, log.PointsEarned)
+		} else {
+			assert.Zero(t, log.PointsEarned, "the book already earned its completion")
+		}
+
+		resp = tests.PerformJSON(childRouter, "DELETE", fmt.Sprintf("/reading-logs/%d", log.ID), nil)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	}
+
+	points, err := repository.GetPointsBalance(db, child.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, points.Balance)
+}
+
+func TestRewards_OtherFamilyIsNotFound(t *testing.T) {
+	db := tests.SetupTestDB()
+	parent := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, parent.ID, "5678")
+	other := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+	reward := models.Reward{ParentID: parent.ID, Name: "Ice cream", Cost: 10}
+	db.Create(&reward)
+	repository.AdjustPoints(db, child.ID, 20, "", parent.ID)
+	redemption, _ := repository.RequestRedemption(db, child.ID, &reward)
+
+	router := newRewardsRouter(db, other.ID, "parent")
+	resp := tests.PerformJSON(router, "PATCH", fmt.Sprintf("/rewards/%d", reward.ID), gin.H{"cost": 1})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = tests.PerformJSON(router, "POST", fmt.Sprintf("/redemptions/%d/reject", redemption.ID), nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = tests.PerformJSON(router, "POST", fmt.Sprintf("/children/%d/points/adjustments", child.ID), gin.H{"points": 100})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = tests.PerformJSON(router, "GET", "/redemptions", nil)
+	var listed []models.RewardRedemption
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &listed))
+	assert.Empty(t, listed)
+	resp = tests.PerformJSON(router, "GET", "/rewards", nil)
+	var rewards []models.Reward
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rewards))
+	assert.Empty(t, rewards)
+}
+
+func TestRewards_CoParentRunsTheFamilysRewards(t *testing.T) {
+	db := tests.SetupTestDB()
+	owner := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, owner.ID, "5678")
+	coParent := tests.CreateTestParent(db, "Alex", "alex@example.com", "password123")
+	viewer := tests.CreateTestParent(db, "Gran", "gran@example.com", "password123")
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: coParent.ID, Role: models.GuardianCoParent})
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: viewer.ID, Role: models.GuardianViewer})
+	ownerReward := models.Reward{ParentID: owner.ID, Name: "Ice cream", Cost: 10}
+	db.Create(&ownerReward)
+
+	coParentRouter := newRewardsRouter(db, coParent.ID, "parent")
+	resp := tests.PerformJSON(coParentRouter, "GET", "/rewards", nil)
+	var rewards []models.Reward
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rewards))
+	assert.Len(t, rewards, 1, "co-parents see the owner's rewards")
+
+	resp = tests.PerformJSON(coParentRouter, "PATCH", fmt.Sprintf("/rewards/%d", ownerReward.ID), gin.H{"cost": 15})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(coParentRouter, "POST", "/rewards", gin.H{"name": "Cinema trip", "cost": 100})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var created models.Reward
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	assert.Equal(t, owner.ID, created.ParentID, "rewards belong to the family, not the co-parent")
+
+	resp = tests.PerformJSON(coParentRouter, "PUT", "/point-rules", []gin.H{{"event": "book_completed", "points": 25}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	resp = tests.PerformJSON(newRewardsRouter(db, child.ID, "child"), "POST", "/reading-logs",
+		gin.H{"title": "Matilda", "status": "completed", "date": "2025-01-09"})
+	var log handlers.ReadingLogResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &log))
+	assert.Equal(t, 25, log.PointsEarned, "the co-parent's rules apply to the child")
+
+	viewerRouter := newRewardsRouter(db, viewer.ID, "parent")
+	resp = tests.PerformJSON(viewerRouter, "GET", "/point-rules", nil)
+	var rules []models.PointRule
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rules))
+	if assert.Len(t, rules, 1) {
+		assert.Equal(t, 25, rules[0].Points)
+	}
+	resp = tests.PerformJSON(viewerRouter, "PATCH", fmt.Sprintf("/rewards/%d", ownerReward.ID), gin.H{"cost": 1})
+	assert.Equal(t, http.StatusNotFound, resp.Code, "viewers can't change rewards")
+	resp = tests.PerformJSON(viewerRouter, "PUT", fmt.Sprintf("/point-rules?child_id=%d", child.ID), []gin.H{})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestRewards_AdultInTwoFamiliesChoosesOne(t *testing.T) {
+	db := tests.SetupTestDB()
+	first := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	second := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+	charlie := tests.CreateTestChild(db, "Charlie", 8, first.ID, "5678")
+	nell := tests.CreateTestChild(db, "Nell", 6, second.ID, "1234")
+	coParent := tests.CreateTestParent(db, "Alex", "alex@example.com", "password123")
+	db.Create(&models.Guardianship{ChildID: charlie.ID, GuardianID: coParent.ID, Role: models.GuardianCoParent})
+	db.Create(&models.Guardianship{ChildID: nell.ID, GuardianID: coParent.ID, Role: models.GuardianCoParent})
+
+	router := newRewardsRouter(db, coParent.ID, "parent")
+	resp := tests.PerformJSON(router, "POST", "/rewards", gin.H{"name": "Sweets", "cost": 5})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = tests.PerformJSON(router, "POST", fmt.Sprintf("/rewards?child_id=%d", nell.ID), gin.H{"name": "Sweets", "cost": 5})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var reward models.Reward
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reward))
+	assert.Equal(t, second.ID, reward.ParentID)
+}
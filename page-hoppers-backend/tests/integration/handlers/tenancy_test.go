@@ -0,0 +1,332 @@
+package integration_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/handlers"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/internal/repository"
+	"page-hoppers-backend/internal/server"
+	"page-hoppers-backend/tests"
+)
+
+// betaMarker is in the name or title of everything the second organization owns, so a response that
+// contains it has leaked across tenants
+const betaMarker = "Beta"
+
+// betaTenant is a second organization with one of everything a route can name by ID
+type betaTenant struct {
+	org                    *models.Organization
+	parent, child, teacher *models.User
+	classroom              *models.Classroom
+	log                    models.ReadingLog
+	session                models.ReadingSession
+	goal                   models.Goal
+	reward                 models.Reward
+	redemption             models.RewardRedemption
+	notification           models.Notification
+	device                 *models.FamilyDevice
+	authSession            models.AuthSession
+	invite                 models.GuardianInvite
+	inviteToken            string
+}
+
+// newBetaTenant sets up the second organization. Its parent invites gran@example.com, who is in the
+// default organization, to be a viewer.
+func newBetaTenant(t *testing.T, srv *server.Server, perform func(string, string, string, interface{}) *httptest.ResponseRecorder,
+	mailer *recordingMailer) *betaTenant {
+	db := srv.DB
+	now := time.Now()
+	beta := &betaTenant{org: tests.CreateTestOrganization(db, "Beta School", "beta")}
+
+	beta.parent = tests.CreateTestParent(db, "Beta Parent", "beta-parent@example.com", "password123")
+	beta.teacher = tests.CreateTestTeacher(db, "Beta Teacher", "beta-teacher@example.com", "password123")
+	tests.MoveToOrganization(db, beta.org.ID, beta.parent, beta.teacher)
+	beta.child = tests.CreateTestChild(db, "Beta Child", 8, beta.parent.ID, "5678")
+
+	var err error
+	beta.classroom, err = repository.CreateClassroom(db, beta.teacher.ID, "Beta Class")
+	assert.NoError(t, err)
+	_, err = repository.JoinClassroom(db, beta.classroom.JoinCode, beta.child.ID, beta.parent.ID, now)
+	assert.NoError(t, err)
+
+	beta.log = models.ReadingLog{ChildID: beta.child.ID, Title: "Beta Book", Status: models.StatusReading, Date: now}
+	db.Create(&beta.log)
+	beta.session = models.ReadingSession{ReadingLogID: beta.log.ID, ChildID: beta.child.ID, Minutes: 10, ReadAt: now}
+	db.Create(&beta.session)
+	beta.goal = models.Goal{ChildID: beta.child.ID, SetByID: beta.parent.ID, Metric: "books", Period: "month", Target: 2}
+	db.Create(&beta.goal)
+	beta.reward = models.Reward{ParentID: beta.parent.ID, Name: "Beta Reward", Cost: 5}
+	db.Create(&beta.reward)
+	beta.redemption = models.RewardRedemption{ChildID: beta.child.ID, RewardID: beta.reward.ID, RewardName: beta.reward.Name,
+		Cost: 5, Status: models.RedemptionStatusRequested}
+	db.Create(&beta.redemption)
+	beta.notification = models.Notification{UserID: beta.parent.ID, Kind: models.NotificationChildLockedOut, Message: "Beta Child was locked out"}
+	db.Create(&beta.notification)
+	beta.device, _, err = repository.CreateFamilyDevice(db, beta.parent.ID, "Beta Tablet", now)
+	assert.NoError(t, err)
+
+	tokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "beta-parent@example.com", "password": "password123"})
+	db.Where("user_id = ?", beta.parent.ID).First(&beta.authSession)
+	resp := perform("POST", fmt.Sprintf("/api/children/%d/guardians/invites", beta.child.ID), tokens.Token,
+		gin.H{"email": "gran@example.com", "role": models.GuardianViewer})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	beta.inviteToken = mailer.lastToken(t)
+	db.Where("child_id = ?", beta.child.ID).First(&beta.invite)
+	return beta
+}
+
+// path fills a route's parameters with the IDs of the beta resources they name
+func (beta *betaTenant) path(route string) string {
+	byCollection := map[string]uint{
+		"children":         beta.child.ID,
+		"reading-logs":     beta.log.ID,
+		"classrooms":       beta.classroom.ID,
+		"rewards":          beta.reward.ID,
+		"redemptions":      beta.redemption.ID,
+		"sessions":         beta.authSession.ID,
+		"devices":          beta.device.ID,
+		"notifications":    beta.notification.ID,
+		"guardian-invites": beta.invite.ID,
+		"users":            beta.parent.ID,
+	}
+	byParam := map[string]uint{
+		":sessionId":   beta.session.ID,
+		":goalId":      beta.goal.ID,
+		":userId":      beta.parent.ID,
+		":classroomId": beta.classroom.ID,
+	}
+
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		id, ok := byParam[segment]
+		if !ok {
+			id = byCollection[segments[i-1]]
+		}
+		segments[i] = fmt.Sprint(id)
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestTenancy_NoRouteReachesAnotherOrganization(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	beta := newBetaTenant(t, srv, perform, mailer)
+
+	// The default organization has a family, a teacher with a class, and an admin
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "1234")
+	teacher := tests.CreateTestTeacher(srv.DB, "Ms Frizzle", "frizzle@example.com", "password123")
+	_, err := repository.CreateClassroom(srv.DB, teacher.ID, "Class 3B")
+	assert.NoError(t, err)
+	tests.CreateTestAdmin(srv.DB, "Principal Skinner", "skinner@example.com", "password123")
+	tokens := map[string]string{
+		parentRole:  login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"}).Token,
+		childRole:   login(t, perform, "/api/auth/child/login", childLoginBody(t, srv.DB, child, "1234")).Token,
+		teacherRole: login(t, perform, "/api/auth/teacher/login", gin.H{"email": "frizzle@example.com", "password": "password123"}).Token,
+		adminRole:   login(t, perform, "/api/auth/admin/login", gin.H{"email": "skinner@example.com", "password": "password123"}).Token,
+	}
+
+	// Every body and query names beta's resources too, for handlers that take IDs from there
+	body := gin.H{
+		"child_id":     beta.child.ID,
+		"childId":      beta.child.ID,
+		"reward_id":    beta.reward.ID,
+		"join_code":    beta.classroom.JoinCode,
+		"token":        beta.inviteToken,
+		"into_user_id": beta.parent.ID,
+	}
+
+	// Log out last so the tokens stay valid for the other routes
+	routes := append(routeRoles[1:len(routeRoles):len(routeRoles)], routeRoles[0])
+	for _, route := range routes {
+		path := beta.path(route.path)
+		if route.method == "GET" {
+			path += fmt.Sprintf("?child_id=%d", beta.child.ID)
+		}
+
+		for _, role := range signedIn {
+			resp := perform(route.method, path, tokens[role], body)
+			assert.NotContains(t, resp.Body.String(), betaMarker, "%s %s as %s", route.method, path, role)
+			if strings.Contains(route.path, ":") {
+				assert.False(t, resp.Code >= 200 && resp.Code < 300, "%s %s as %s: %d", route.method, path, role, resp.Code)
+			}
+		}
+	}
+
+	// Nothing beta owns was changed
+	var log models.ReadingLog
+	assert.NoError(t, srv.DB.First(&log, beta.log.ID).Error)
+	assert.Equal(t, models.StatusReading, log.Status)
+	assert.NoError(t, srv.DB.First(&models.ReadingSession{}, beta.session.ID).Error)
+	var goal models.Goal
+	assert.NoError(t, srv.DB.First(&goal, beta.goal.ID).Error)
+	assert.Equal(t, 2, goal.Target)
+	var reward models.Reward
+	assert.NoError(t, srv.DB.First(&reward, beta.reward.ID).Error)
+	assert.False(t, reward.Disabled)
+	var redemption models.RewardRedemption
+	srv.DB.First(&redemption, beta.redemption.ID)
+	assert.Equal(t, models.RedemptionStatusRequested, redemption.Status)
+	var classroom models.Classroom
+	srv.DB.First(&classroom, beta.classroom.ID)
+	assert.Equal(t, beta.classroom.JoinCode, classroom.JoinCode)
+	students, _ := repository.ClassroomStudents(srv.DB, beta.classroom.ID)
+	assert.Len(t, students, 1)
+	var notification models.Notification
+	srv.DB.First(&notification, beta.notification.ID)
+	assert.Nil(t, notification.ReadAt)
+	var device models.FamilyDevice
+	srv.DB.First(&device, beta.device.ID)
+	assert.Nil(t, device.RevokedAt)
+	var authSession models.AuthSession
+	srv.DB.First(&authSession, beta.authSession.ID)
+	assert.Nil(t, authSession.RevokedAt)
+	var invite models.GuardianInvite
+	srv.DB.First(&invite, beta.invite.ID)
+	assert.Nil(t, invite.AcceptedAt)
+	assert.Nil(t, invite.RevokedAt)
+	var org models.Organization
+	srv.DB.First(&org, beta.org.ID)
+	assert.Equal(t, "Beta School", org.Name)
+}
+
+func TestTenancy_LinksDontCrossOrganizations(t *testing.T) {
+	srv, perform := newTestServer(t)
+	mailer := &recordingMailer{}
+	srv.AuthHandler.Mailer = mailer
+	beta := newBetaTenant(t, srv, perform, mailer)
+
+	gran := tests.CreateTestParent(srv.DB, "Gran", "gran@example.com", "password123")
+	child := tests.CreateTestChild(srv.DB, "Charlie", 8, gran.ID, "1234")
+	granTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "gran@example.com", "password": "password123"})
+
+	// The invite was sent to Gran's address, but she's in another organization
+	resp := perform("POST", "/api/guardian-invites/accept", granTokens.Token, gin.H{"token": beta.inviteToken})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "another organization")
+	role, _ := repository.GuardianRole(srv.DB, gran.ID, beta.child.ID)
+	assert.Empty(t, role)
+
+	// Beta's join code doesn't exist as far as the default organization is concerned
+	resp = perform("POST", fmt.Sprintf("/api/children/%d/classrooms", child.ID), granTokens.Token, gin.H{"join_code": beta.classroom.JoinCode})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Registering names the organization and its join code; an unknown organization is refused
+	code, err := repository.RotateOrganizationJoinCode(srv.DB, beta.org, policy.RoleParent)
+	assert.NoError(t, err)
+	resp = perform("POST", "/api/auth/parent/register", "", gin.H{"name": "Nia", "email": "nia@example.com", "password": "password123",
+		"organization": "beta", "join_code": code})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var nia models.User
+	srv.DB.Where("email = ?", "nia@example.com").First(&nia)
+	assert.Equal(t, beta.org.ID, nia.OrganizationID)
+	resp = perform("POST", "/api/auth/parent/register", "", gin.H{"name": "Oz", "email": "oz@example.com", "password": "password123", "organization": "nowhere"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Children are created in their parent's organization
+	niaTokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "nia@example.com", "password": "password123"})
+	resp = perform("POST", "/api/children", niaTokens.Token, gin.H{"name": "Theo", "age": 7, "pin": "4321"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var theo models.User
+	srv.DB.Where("name = ?", "Theo").First(&theo)
+	assert.Equal(t, beta.org.ID, theo.OrganizationID)
+}
+
+func TestTenancy_TokenIsBoundToItsOrganization(t *testing.T) {
+	srv, perform := newTestServer(t)
+	beta := tests.CreateTestOrganization(srv.DB, "Beta School", "beta")
+	parent := tests.CreateTestParent(srv.DB, "Bob", "bob@example.com", "password123")
+	tests.CreateTestChild(srv.DB, "Charlie", 8, parent.ID, "5678")
+	tokens := login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusOK, perform("GET", "/api/children", tokens.Token, nil).Code)
+
+	// A token claiming another organization is refused, even on a live session
+	var session models.AuthSession
+	srv.DB.Where("user_id = ?", parent.ID).First(&session)
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": parent.ID,
+		"role":    parentRole,
+		"org_id":  beta.ID,
+		"sid":     session.ID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", forged, nil).Code)
+
+	// Moving an account to another organization ends its tokens; after signing in again it sees only that one
+	tests.MoveToOrganization(srv.DB, beta.ID, parent)
+	assert.Equal(t, http.StatusUnauthorized, perform("GET", "/api/children", tokens.Token, nil).Code)
+	tokens = login(t, perform, "/api/auth/parent/login", gin.H{"email": "bob@example.com", "password": "password123"})
+	resp := perform("GET", "/api/children", tokens.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[]`, resp.Body.String())
+}
+
+func TestTenancy_RegisteringNeedsTheOrganizationsJoinCode(t *testing.T) {
+	srv, perform := newTestServer(t)
+	beta := tests.CreateTestOrganization(srv.DB, "Beta School", "beta")
+	admin := tests.CreateTestAdmin(srv.DB, "Beta Admin", "beta-admin@example.com", "password123")
+	tests.MoveToOrganization(srv.DB, beta.ID, admin)
+	adminTokens := login(t, perform, "/api/auth/admin/login", gin.H{"email": "beta-admin@example.com", "password": "password123"})
+	register := func(path, email, code string) int {
+		return perform("POST", path, "", gin.H{"name": "Someone", "email": email, "password": "password123",
+			"organization": "beta", "join_code": code}).Code
+	}
+
+	// Until its admins issue codes, nobody can register into the organization
+	assert.Equal(t, http.StatusForbidden, register("/api/auth/teacher/register", "t1@example.com", ""))
+	assert.Equal(t, http.StatusForbidden, register("/api/auth/parent/register", "p1@example.com", ""))
+
+	resp := perform("POST", "/api/organization/join-codes/parent", adminTokens.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var codes handlers.OrganizationJoinCodes
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &codes))
+	assert.NotEmpty(t, codes.Parent)
+	assert.Empty(t, codes.Teacher)
+
+	// A family's code doesn't make a teacher, and a wrong code makes nobody
+	assert.Equal(t, http.StatusForbidden, register("/api/auth/teacher/register", "t1@example.com", codes.Parent))
+	assert.Equal(t, http.StatusForbidden, register("/api/auth/parent/register", "p1@example.com", "WRONGCOD"))
+	assert.Equal(t, http.StatusCreated, register("/api/auth/parent/register", "p1@example.com", strings.ToLower(codes.Parent)))
+	var parent models.User
+	srv.DB.Where("email = ?", "p1@example.com").First(&parent)
+	assert.Equal(t, beta.ID, parent.OrganizationID)
+
+	resp = perform("POST", "/api/organization/join-codes/teacher", adminTokens.Token, nil)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &codes))
+	assert.Equal(t, http.StatusCreated, register("/api/auth/teacher/register", "t1@example.com", codes.Teacher))
+
+	// Issuing a new code retires the old one, and is audited
+	old := codes.Parent
+	resp = perform("POST", "/api/organization/join-codes/parent", adminTokens.Token, nil)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &codes))
+	assert.NotEqual(t, old, codes.Parent)
+	assert.Equal(t, http.StatusForbidden, register("/api/auth/parent/register", "p2@example.com", old))
+	entries, _ := repository.AdminAuditLog(srv.DB, beta.ID, 10)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, models.AdminActionRotateJoinCode, entries[0].Action)
+	assert.Equal(t, policy.RoleParent, entries[0].Detail)
+
+	resp = perform("POST", "/api/organization/join-codes/admin", adminTokens.Token, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// The default organization stays open to anyone
+	resp = perform("POST", "/api/auth/teacher/register", "", gin.H{"name": "Open", "email": "open@example.com", "password": "password123"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+}
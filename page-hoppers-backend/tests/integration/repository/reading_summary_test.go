@@ -1,7 +1,9 @@
 package integration_respository_test
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -288,3 +290,36 @@ func TestGetReadingSummary_TwoStartedBooks(t *testing.T) {
 	assert.Equal(t, 0, totalCompletedBooks)
 	assert.Equal(t, 2, totalUncompletedBooks)
 }
+
+// Summaries over a custom range, grouped by week
+func TestGetReadingSummary_CustomRange(t *testing.T) {
+	setup := tests.SetupSummaryTest()
+
+	for _, day := range []int{6, 8, 20} {
+		setup.DB.Create(&models.ReadingLog{
+			ChildID: setup.Child.ID,
+			Title:   "Book " + strconv.Itoa(day),
+			Status:  "completed",
+			Date:    time.Date(2025, time.January, day, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	path := fmt.Sprintf("/children/%d/summary?from=2025-01-06&to=2025-01-19&group_by=week", setup.Child.ID)
+	resp := tests.PerformJSON(setup.Router, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	summary := tests.ParseSummary(t, resp)
+	readingRange := summary["range"].(map[string]interface{})
+	assert.Equal(t, "2025-01-19", readingRange["to"])
+	assert.Equal(t, float64(2), readingRange["books_completed"], "the book on the 20th is outside the range")
+
+	periods := summary["periods"].([]interface{})
+	assert.Len(t, periods, 2)
+	assert.Equal(t, float64(2), periods[0].(map[string]interface{})["books_completed"])
+	assert.Equal(t, float64(0), periods[1].(map[string]interface{})["books_completed"])
+
+	for _, query := range []string{"?from=2025-01-06", "?period=last_decade", "?group_by=fortnight", "?period=last_month&from=2025-01-01&to=2025-01-31"} {
+		resp = tests.PerformJSON(setup.Router, "GET", fmt.Sprintf("/children/%d/summary%s", setup.Child.ID, query), nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
@@ -0,0 +1,64 @@
+package unit_books_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/books"
+)
+
+func TestISBN_Checksums(t *testing.T) {
+	cases := []struct {
+		isbn    string
+		valid10 bool
+		valid13 bool
+	}{
+		{"0142410373", true, false},
+		{"0-14-241037-3", true, false},
+		{"080442957X", true, false},
+		{"080442957x", true, false},
+		{"0142410374", false, false},
+		{"9780142410370", false, true},
+		{"978-0-14-241037-0", false, true},
+		{"9780142410371", false, false},
+		{"9790000000001", false, true},
+		{"1234567890123", false, false},
+		{"", false, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.valid10, books.IsValidISBN10(tc.isbn), "ISBN-10 check for %q", tc.isbn)
+		assert.Equal(t, tc.valid13, books.IsValidISBN13(tc.isbn), "ISBN-13 check for %q", tc.isbn)
+	}
+}
+
+func TestISBN_Conversion(t *testing.T) {
+	isbn13, err := books.ISBN10To13("0-14-241037-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "9780142410370", isbn13)
+
+	isbn10, err := books.ISBN13To10("9780142410370")
+	assert.NoError(t, err)
+	assert.Equal(t, "0142410373", isbn10)
+
+	isbn10, err = books.ISBN13To10("9780804429573")
+	assert.NoError(t, err)
+	assert.Equal(t, "080442957X", isbn10, "a check digit of 10 is written as X")
+
+	_, err = books.ISBN13To10("9790000000001")
+	assert.ErrorIs(t, err, books.ErrInvalidISBN, "979 ISBNs have no ISBN-10 form")
+
+	_, err = books.ISBN10To13("0142410374")
+	assert.ErrorIs(t, err, books.ErrInvalidISBN)
+}
+
+func TestParseISBN(t *testing.T) {
+	for _, input := range []string{"0142410373", "978-0-14-241037-0", " 978 0142410370 "} {
+		isbn, err := books.ParseISBN(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, "9780142410370", isbn, input)
+	}
+
+	_, err := books.ParseISBN("not an isbn")
+	assert.ErrorIs(t, err, books.ErrInvalidISBN)
+}
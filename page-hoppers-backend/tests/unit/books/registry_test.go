@@ -0,0 +1,123 @@
+package unit_books_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/books"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// stubProvider returns fixed results or a fixed error
+type stubProvider struct {
+	name    string
+	results []books.SearchResult
+	err     error
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Search(ctx context.Context, query string, limit int) ([]books.SearchResult, error) {
+	return p.results, p.err
+}
+
+func (p *stubProvider) LookupISBN(ctx context.Context, isbn13 string) (*books.SearchResult, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	for _, result := range p.results {
+		for _, isbn := range result.ISBNs {
+			if parsed, err := books.ParseISBN(isbn); err == nil && parsed == isbn13 {
+				match := result
+				return &match, nil
+			}
+		}
+	}
+	return nil, books.ErrNotFound
+}
+
+func TestRegistry_MergesByISBNInPriorityOrder(t *testing.T) {
+	coverID := 8314135
+	registry := books.NewRegistry(
+		&stubProvider{name: "local", results: []books.SearchResult{
+			{Title: "Matilda", ISBNs: []string{"978-0-14-241037-0"}},
+		}},
+		&stubProvider{name: "openlibrary", results: []books.SearchResult{
+			{Title: "Matilda (Puffin)", Authors: []string{"Roald Dahl"}, CoverID: &coverID, OpenLibraryKey: "/works/OL45804W", ISBNs: []string{"9780142410370"}},
+			{Title: "The BFG", Authors: []string{"Roald Dahl"}},
+		}},
+		&stubProvider{name: "googlebooks", results: []books.SearchResult{
+			{Title: "Matilda", PageCount: 240, ISBNs: []string{"9780142410370", "0142410373"}},
+		}},
+	)
+
+	results, err := registry.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	matilda := results[0]
+	assert.Equal(t, "Matilda", matilda.Title)
+	assert.Equal(t, "local", matilda.Sources["title"], "the highest priority provider wins the title")
+	assert.Equal(t, "openlibrary", matilda.Sources["authors"])
+	assert.Equal(t, "openlibrary", matilda.Sources["cover_id"])
+	assert.Equal(t, 240, matilda.PageCount)
+	assert.Equal(t, "googlebooks", matilda.Sources["page_count"])
+	assert.ElementsMatch(t, []string{"9780142410370", "0142410373"}, matilda.ISBNs)
+}
+
+func TestRegistry_SkipsFailingProviders(t *testing.T) {
+	registry := books.NewRegistry(
+		&stubProvider{name: "openlibrary", err: books.ErrUpstreamUnavailable},
+		&stubProvider{name: "local", results: []books.SearchResult{{Title: "Matilda"}}},
+	)
+
+	results, err := registry.Search(context.Background(), "matilda", 10)
+	assert.Len(t, results, 1)
+	var partial *books.PartialError
+	if assert.ErrorAs(t, err, &partial, "the local answer alone is only part of one") {
+		assert.Equal(t, []string{"openlibrary"}, partial.Failed)
+		assert.ErrorIs(t, err, books.ErrUpstreamUnavailable)
+	}
+
+	registry = books.NewRegistry(&stubProvider{name: "openlibrary", err: books.ErrUpstreamUnavailable})
+	_, err = registry.Search(context.Background(), "matilda", 10)
+	assert.True(t, errors.Is(err, books.ErrUpstreamUnavailable))
+}
+
+func TestLocalCatalogProvider_OnlyCuratedBooks(t *testing.T) {
+	db := tests.SetupTestDB()
+	db.Create(&models.Book{Title: "Matilda", Author: "Roald Dahl", ISBN: "9780142410370", Series: "Roald Dahl Classics", Curated: true})
+	db.Create(&models.Book{Title: "Matilda's Cat", Author: "Emily Gravett"})
+
+	results, err := books.NewLocalCatalogProvider(db).Search(context.Background(), "MATILDA", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Roald Dahl", results[0].Authors[0])
+		assert.Equal(t, "Roald Dahl Classics", results[0].Series)
+	}
+}
+
+func TestGoogleBooksProvider_ParsesVolumes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/volumes", r.URL.Path)
+		w.Write([]byte(`{"items": [{"volumeInfo": {
+			"title": "Matilda", "authors": ["Roald Dahl"], "publishedDate": "1988-10-01", "pageCount": 240,
+			"industryIdentifiers": [{"type": "ISBN_13", "identifier": "9780142410370"}, {"type": "OTHER", "identifier": "x"}],
+			"imageLinks": {"thumbnail": "http://books.example/cover.jpg"}
+		}}]}`))
+	}))
+	defer server.Close()
+
+	results, err := books.NewGoogleBooksProvider(server.URL, "").Search(context.Background(), "matilda", 5)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 1988, results[0].FirstPublishYear)
+	assert.Equal(t, []string{"9780142410370"}, results[0].ISBNs)
+	assert.Equal(t, "http://books.example/cover.jpg", results[0].CoverURL)
+}
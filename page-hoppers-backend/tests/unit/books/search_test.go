@@ -0,0 +1,127 @@
+package unit_books_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/books"
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/tests"
+)
+
+// fakeOpenLibrary serves a canned search.json and can be switched to fail
+type fakeOpenLibrary struct {
+	server *httptest.Server
+	calls  int32
+	down   atomic.Bool
+}
+
+func newFakeOpenLibrary(t *testing.T) *fakeOpenLibrary {
+	fake := &fakeOpenLibrary{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fake.calls, 1)
+		if fake.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "/search.json", r.URL.Path)
+		assert.Equal(t, "matilda", r.URL.Query().Get("q"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"numFound": 2, "docs": [
+			{"key": "/works/OL45804W", "title": "Matilda", "author_name": ["Roald Dahl"], "cover_i": 8314135, "isbn": ["9780142410370"]},
+			{"key": "/works/OL1W", "title": "Matilda Bone", "author_name": ["Karen Cushman"]}
+		]}`))
+	}))
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func TestSearch_CachesUpstreamResults(t *testing.T) {
+	db := tests.SetupTestDB()
+	fake := newFakeOpenLibrary(t)
+	service := books.NewSearchService(db, books.NewOpenLibraryProvider(fake.server.URL), time.Hour)
+
+	resp, err := service.Search(context.Background(), "  Matilda ", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourceUpstream, resp.Source)
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, "Roald Dahl", resp.Results[0].Authors[0])
+
+	resp, err = service.Search(context.Background(), "matilda", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourceCache, resp.Source)
+	assert.Len(t, resp.Results, 1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fake.calls), "second search is served from the cache")
+
+	var book models.Book
+	assert.NoError(t, db.Where("open_library_key = ?", "/works/OL45804W").First(&book).Error)
+	assert.Equal(t, 8314135, *book.CoverID, "cover metadata is stored in the catalog")
+}
+
+func TestSearch_ServesStaleCacheWhenUpstreamIsDown(t *testing.T) {
+	db := tests.SetupTestDB()
+	fake := newFakeOpenLibrary(t)
+	service := books.NewSearchService(db, books.NewOpenLibraryProvider(fake.server.URL), time.Hour)
+
+	_, err := service.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+
+	// Expire the cache and take the upstream down
+	now := time.Now()
+	service.Now = func() time.Time { return now.Add(2 * time.Hour) }
+	fake.down.Store(true)
+
+	resp, err := service.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourceStaleCache, resp.Source)
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fake.calls), "expired cache triggers an upstream call")
+}
+
+func TestSearch_UpstreamDownWithoutCacheFails(t *testing.T) {
+	db := tests.SetupTestDB()
+	fake := newFakeOpenLibrary(t)
+	fake.down.Store(true)
+	service := books.NewSearchService(db, books.NewOpenLibraryProvider(fake.server.URL), time.Hour)
+
+	_, err := service.Search(context.Background(), "matilda", 10)
+	assert.ErrorIs(t, err, books.ErrUpstreamUnavailable)
+}
+
+func TestSearch_PartialAnswersAreServedButNotCached(t *testing.T) {
+	db := tests.SetupTestDB()
+	fake := newFakeOpenLibrary(t)
+	fake.down.Store(true)
+	db.Create(&models.Book{Title: "Matilda", Author: "Roald Dahl", ISBN: "9780142410370", Curated: true})
+	registry := books.NewRegistry(books.NewLocalCatalogProvider(db), books.NewOpenLibraryProvider(fake.server.URL))
+	service := books.NewSearchService(db, registry, time.Hour)
+
+	resp, err := service.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourcePartial, resp.Source)
+	assert.Len(t, resp.Results, 1)
+	var cached int64
+	db.Model(&models.BookSearchCache{}).Count(&cached)
+	assert.Zero(t, cached)
+
+	fake.down.Store(false)
+	resp, err = service.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourceUpstream, resp.Source, "the next search asks again")
+	assert.Len(t, resp.Results, 2)
+
+	// Once there is a full answer, an outage serves it rather than the partial one
+	now := time.Now()
+	service.Now = func() time.Time { return now.Add(2 * time.Hour) }
+	fake.down.Store(true)
+	resp, err = service.Search(context.Background(), "matilda", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, books.SourceStaleCache, resp.Source)
+	assert.Len(t, resp.Results, 2)
+}
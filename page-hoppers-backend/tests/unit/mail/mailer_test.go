@@ -0,0 +1,59 @@
+package unit_mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/mail"
+)
+
+func TestNewMailer_Backends(t *testing.T) {
+	m, err := mail.NewMailer("", mail.Config{})
+	assert.NoError(t, err)
+	assert.IsType(t, &mail.LogMailer{}, m, "the log mailer is the default")
+
+	m, err = mail.NewMailer("File", mail.Config{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.IsType(t, &mail.FileMailer{}, m)
+
+	_, err = mail.NewMailer("smtp", mail.Config{})
+	assert.Error(t, err, "smtp needs a host")
+	m, err = mail.NewMailer("smtp", mail.Config{SMTPHost: "smtp.example.com", From: "no-reply@example.com"})
+	assert.NoError(t, err)
+	assert.IsType(t, &mail.SMTPMailer{}, m)
+
+	_, err = mail.NewMailer("pigeon", mail.Config{})
+	assert.Error(t, err)
+}
+
+func TestFileMailer_WritesMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := mail.NewFileMailer(dir, "")
+
+	err := m.Send(context.Background(), mail.Message{To: "bob@example.com", Subject: "Hello", Body: "Line one\nLine two"})
+	assert.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Contains(t, files[0].Name(), "bob_example.com")
+		data, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		assert.Contains(t, string(data), "To: bob@example.com\r\n")
+		assert.Contains(t, string(data), "Subject: Hello\r\n")
+		assert.Contains(t, string(data), "From: "+mail.DefaultFrom)
+		assert.Contains(t, string(data), "Line one\r\nLine two")
+	}
+}
+
+func TestCheckBackend_LogMailerIsForDevelopmentOnly(t *testing.T) {
+	assert.NoError(t, mail.CheckBackend("", ""))
+	assert.NoError(t, mail.CheckBackend("log", "development"))
+	assert.Error(t, mail.CheckBackend("", "production"))
+	assert.Error(t, mail.CheckBackend("Log", "staging"))
+	assert.NoError(t, mail.CheckBackend("smtp", "production"))
+	assert.NoError(t, mail.CheckBackend("file", "staging"))
+}
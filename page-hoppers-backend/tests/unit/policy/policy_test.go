@@ -0,0 +1,81 @@
+package unit_policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/policy"
+	"page-hoppers-backend/tests"
+)
+
+func TestChildAccessMatrix(t *testing.T) {
+	db := tests.SetupTestDB()
+	owner := tests.CreateTestParent(db, "Bob", "bob@example.com", "password123")
+	coParent := tests.CreateTestParent(db, "Alex", "alex@example.com", "password123")
+	viewer := tests.CreateTestParent(db, "Gran", "gran@example.com", "password123")
+	stranger := tests.CreateTestParent(db, "Dana", "dana@example.com", "password123")
+	child := tests.CreateTestChild(db, "Charlie", 8, owner.ID, "5678")
+	sibling := tests.CreateTestChild(db, "Sam", 6, owner.ID, "1234")
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: coParent.ID, Role: models.GuardianCoParent})
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: viewer.ID, Role: models.GuardianViewer})
+	admin := tests.CreateTestAdmin(db, "Principal Skinner", "skinner@example.com", "password123")
+	other := tests.CreateTestOrganization(db, "Other School", "other")
+	outsider := tests.CreateTestParent(db, "Olga", "olga@example.com", "password123")
+	tests.MoveToOrganization(db, other.ID, outsider)
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: outsider.ID, Role: models.GuardianCoParent})
+
+	// in is the principal the auth middleware makes for a user: their own organization from the token
+	in := func(userID uint, role string) policy.Principal {
+		var user models.User
+		db.Unscoped().First(&user, userID)
+		return policy.Principal{UserID: userID, Role: role, OrganizationID: user.OrganizationID}
+	}
+
+	for _, tc := range []struct {
+		name             string
+		principal        policy.Principal
+		read, write, own bool
+	}{
+		{"child themselves", in(child.ID, policy.RoleChild), true, true, false},
+		{"sibling", in(sibling.ID, policy.RoleChild), false, false, false},
+		{"owner", in(owner.ID, policy.RoleParent), true, true, true},
+		{"co-parent", in(coParent.ID, policy.RoleParent), true, true, false},
+		{"viewer", in(viewer.ID, policy.RoleParent), true, false, false},
+		{"stranger", in(stranger.ID, policy.RoleParent), false, false, false},
+		// Admins run the organization but don't see into families
+		{"admin", in(admin.ID, policy.RoleAdmin), false, false, false},
+		// A guardianship that somehow crosses organizations, or the owner with another tenant's claim, gets nothing
+		{"guardian in another organization", in(outsider.ID, policy.RoleParent), false, false, false},
+		{"owner with another organization", policy.Principal{UserID: owner.ID, Role: policy.RoleParent, OrganizationID: other.ID}, false, false, false},
+		// A child's ID presented with the parent role, or an unknown role, gets nothing
+		{"child as parent", in(child.ID, policy.RoleParent), false, false, false},
+		{"unknown role", in(owner.ID, "librarian"), false, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.read, policy.CanReadChild(db, tc.principal, child.ID), "read")
+			assert.Equal(t, tc.write, policy.CanWriteChild(db, tc.principal, child.ID), "write")
+			assert.Equal(t, tc.own, policy.CanManageGuardians(db, tc.principal, child.ID), "manage guardians")
+		})
+	}
+}
+
+func TestPrincipalIs(t *testing.T) {
+	p := policy.Principal{UserID: 1, Role: policy.RoleParent}
+	assert.True(t, p.Is(policy.RoleParent))
+	assert.True(t, p.Is(policy.RoleChild, policy.RoleParent))
+	assert.False(t, p.Is(policy.RoleChild))
+	assert.False(t, p.Is())
+}
+
+func TestCanTeachClassroom_StaysInOrganization(t *testing.T) {
+	db := tests.SetupTestDB()
+	teacher := tests.CreateTestTeacher(db, "Ms Frizzle", "frizzle@example.com", "password123")
+	classroom := models.Classroom{OrganizationID: teacher.OrganizationID, TeacherID: teacher.ID, Name: "Class 3B", JoinCode: "ABCD2345"}
+	db.Create(&classroom)
+
+	assert.True(t, policy.CanTeachClassroom(db, policy.Principal{UserID: teacher.ID, Role: policy.RoleTeacher, OrganizationID: teacher.OrganizationID}, classroom.ID))
+	assert.False(t, policy.CanTeachClassroom(db, policy.Principal{UserID: teacher.ID, Role: policy.RoleTeacher, OrganizationID: teacher.OrganizationID + 1}, classroom.ID))
+	assert.False(t, policy.CanTeachClassroom(db, policy.Principal{UserID: teacher.ID, Role: policy.RoleAdmin, OrganizationID: teacher.OrganizationID}, classroom.ID))
+}
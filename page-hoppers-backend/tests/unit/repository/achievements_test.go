@@ -0,0 +1,140 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func completeBook(db *gorm.DB, childID uint, title, author string, at time.Time) {
+	book, _ := repository.FindOrCreateBook(db, models.Book{Title: title, Author: author})
+	log := models.ReadingLog{ChildID: childID, Status: models.StatusCompleted, Date: at, CompletedAt: &at}
+	repository.LinkReadingLogToBook(&log, book)
+	db.Omit("Book").Create(&log)
+}
+
+// createChild adds a child with no parent, so their family settings are the defaults
+func createChild(db *gorm.DB) uint {
+	child := models.User{Name: "Charlie", Role: "child"}
+	db.Create(&child)
+	return child.ID
+}
+
+func awardedKeys(awards []models.ChildAchievement) []string {
+	keys := make([]string, 0, len(awards))
+	for _, award := range awards {
+		keys = append(keys, award.Rule.Key)
+	}
+	return keys
+}
+
+func TestEvaluateAchievements_AwardsOnce(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	awards, err := repository.EvaluateAchievements(db, childID, now)
+	assert.NoError(t, err)
+	assert.Empty(t, awards)
+
+	completeBook(db, childID, "Matilda", "Roald Dahl", now)
+	awards, err = repository.EvaluateAchievements(db, childID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first_book"}, awardedKeys(awards))
+
+	awards, err = repository.EvaluateAchievements(db, childID, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, awards, "a badge is only awarded once")
+
+	for i, author := range []string{"E. B. White", "Louis Sachar", "R. J. Palacio", "roald dahl", "Kate DiCamillo"} {
+		completeBook(db, childID, "Book "+author, author, now.AddDate(0, 0, -i))
+	}
+	awards, err = repository.EvaluateAchievements(db, childID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"five_authors"}, awardedKeys(awards), "Roald Dahl counts once whatever the case")
+
+	earned, err := repository.GetChildAchievements(db, childID)
+	assert.NoError(t, err)
+	assert.Len(t, earned, 2)
+	assert.Equal(t, now, earned[0].AwardedAt.UTC())
+}
+
+func TestEvaluateAchievements_FinishedSeries(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for _, title := range []string{"The Bad Beginning", "The Reptile Room"} {
+		db.Create(&models.Book{Title: title, Author: "Lemony Snicket", MatchKey: repository.BookMatchKey(title, "Lemony Snicket"), Series: "A Series of Unfortunate Events"})
+	}
+
+	completeBook(db, childID, "The Bad Beginning", "Lemony Snicket", now)
+	awards, _ := repository.EvaluateAchievements(db, childID, now)
+	assert.NotContains(t, awardedKeys(awards), "finished_series")
+
+	completeBook(db, childID, "The Reptile Room", "Lemony Snicket", now)
+	awards, _ = repository.EvaluateAchievements(db, childID, now)
+	assert.Contains(t, awardedKeys(awards), "finished_series")
+}
+
+// Rules are data: a seasonal badge is just a new row with a window
+func TestEvaluateAchievements_SeasonalRule(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	start := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	db.Create(&models.AchievementRule{Key: "summer_2025", Name: "Summer Reader", Metric: models.AchievementMetricBooksCompleted, Threshold: 2, StartsAt: &start, EndsAt: &end})
+
+	completeBook(db, childID, "Read in spring", "", start.AddDate(0, 0, -3))
+	completeBook(db, childID, "Read in summer", "", start.AddDate(0, 0, 3))
+	awards, _ := repository.EvaluateAchievements(db, childID, start.AddDate(0, 0, 3))
+	assert.NotContains(t, awardedKeys(awards), "summer_2025", "the spring book is outside the window")
+
+	completeBook(db, childID, "Also read in summer", "", start.AddDate(0, 0, 4))
+	awards, _ = repository.EvaluateAchievements(db, childID, end.AddDate(0, 0, 1))
+	assert.NotContains(t, awardedKeys(awards), "summer_2025", "the badge can't be earned after the season ends")
+
+	awards, _ = repository.EvaluateAchievements(db, childID, start.AddDate(0, 0, 4))
+	assert.Contains(t, awardedKeys(awards), "summer_2025")
+}
+
+func TestEvaluateAchievements_SevenDayStreak(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+
+	readOn(db, childID, 1, 2, 3, 4, 5, 6)
+	awards, err := repository.EvaluateAchievements(db, childID, time.Date(2025, time.March, 6, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.NotContains(t, awardedKeys(awards), "seven_day_streak")
+
+	readOn(db, childID, 7)
+	awards, err = repository.EvaluateAchievements(db, childID, time.Date(2025, time.March, 7, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Contains(t, awardedKeys(awards), "seven_day_streak")
+}
+
+func TestEvaluateAchievements_DeletedLogsSessionsDontCount(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	db.Create(&models.AchievementRule{Key: "hour_of_reading", Name: "An Hour of Reading", Metric: models.AchievementMetricMinutesRead, Threshold: 60})
+
+	deleted := models.ReadingLog{ChildID: childID, Title: "Holes", Status: models.StatusReading, Date: now}
+	db.Create(&deleted)
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: deleted.ID, Minutes: 90, ReadAt: now})
+	db.Delete(&deleted)
+	awards, err := repository.EvaluateAchievements(db, childID, now)
+	assert.NoError(t, err)
+	assert.NotContains(t, awardedKeys(awards), "hour_of_reading")
+
+	reading := models.ReadingLog{ChildID: childID, Title: "Wonder", Status: models.StatusReading, Date: now}
+	db.Create(&reading)
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: reading.ID, Minutes: 60, ReadAt: now})
+	awards, err = repository.EvaluateAchievements(db, childID, now)
+	assert.NoError(t, err)
+	assert.Contains(t, awardedKeys(awards), "hour_of_reading")
+}
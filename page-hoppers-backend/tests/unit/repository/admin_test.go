@@ -0,0 +1,88 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestSearchUsers_StaysInOrganizationAndFindsDeletedAccounts(t *testing.T) {
+	db := setupTestDB(t)
+	def, _ := repository.FindOrganization(db, "")
+	other, _ := repository.CreateOrganization(db, "Maple Primary", "maple")
+
+	bob := models.User{Name: "Bob Smith", Role: "parent", Email: "bob@example.com", OrganizationID: def.ID}
+	mia := models.User{Name: "Mia Smith", Role: "parent", Email: "mia@example.com", OrganizationID: other.ID}
+	db.Create(&bob)
+	db.Create(&mia)
+	child := models.User{Name: "Charlie Smith", Role: "child", ParentID: &bob.ID, OrganizationID: def.ID}
+	db.Create(&child)
+	db.Delete(&child)
+
+	users, err := repository.SearchUsers(db, def.ID, " SMITH ", "")
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, bob.ID, users[0].ID)
+		assert.Equal(t, child.ID, users[1].ID)
+	}
+	users, _ = repository.SearchUsers(db, def.ID, "example.com", "child")
+	assert.Empty(t, users)
+	users, _ = repository.SearchUsers(db, other.ID, "", "")
+	assert.Len(t, users, 1)
+}
+
+func TestMergeParents_MovesTheFamilyWithoutDuplicateGuardianships(t *testing.T) {
+	db := setupTestDB(t)
+	def, _ := repository.FindOrganization(db, "")
+	other, _ := repository.CreateOrganization(db, "Maple Primary", "maple")
+	now := time.Date(2025, time.September, 3, 9, 0, 0, 0, time.UTC)
+
+	keep := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com", OrganizationID: def.ID}
+	duplicate := models.User{Name: "Bob", Role: "parent", Email: "bob.smith@example.com", OrganizationID: def.ID}
+	neighbour := models.User{Name: "Nia", Role: "parent", Email: "nia@example.com", OrganizationID: def.ID}
+	elsewhere := models.User{Name: "Mia", Role: "parent", Email: "mia@example.com", OrganizationID: other.ID}
+	for _, user := range []*models.User{&keep, &duplicate, &neighbour, &elsewhere} {
+		db.Create(user)
+	}
+	charlie := models.User{Name: "Charlie", Role: "child", ParentID: &keep.ID, OrganizationID: def.ID}
+	dana := models.User{Name: "Dana", Role: "child", ParentID: &duplicate.ID, OrganizationID: def.ID}
+	nell := models.User{Name: "Nell", Role: "child", ParentID: &neighbour.ID, OrganizationID: def.ID}
+	for _, child := range []*models.User{&charlie, &dana, &nell} {
+		db.Create(child)
+	}
+	// The duplicate co-parents Charlie, whom the kept account owns, and views Nell; the kept account views Dana
+	db.Create(&models.Guardianship{ChildID: charlie.ID, GuardianID: duplicate.ID, Role: models.GuardianCoParent})
+	db.Create(&models.Guardianship{ChildID: nell.ID, GuardianID: duplicate.ID, Role: models.GuardianViewer})
+	db.Create(&models.Guardianship{ChildID: dana.ID, GuardianID: keep.ID, Role: models.GuardianViewer})
+	db.Create(&models.PointRule{ParentID: duplicate.ID, Event: models.PointEventBookCompleted, Points: 5})
+	_, _, err := repository.CreateAuthSession(db, duplicate.ID, "", "", now)
+	assert.NoError(t, err)
+
+	assert.Equal(t, repository.ErrMergeNotAllowed, repository.MergeParents(db, duplicate.ID, duplicate.ID, now))
+	assert.Equal(t, repository.ErrMergeNotAllowed, repository.MergeParents(db, duplicate.ID, elsewhere.ID, now))
+	assert.Equal(t, repository.ErrMergeNotAllowed, repository.MergeParents(db, dana.ID, keep.ID, now))
+
+	assert.NoError(t, repository.MergeParents(db, duplicate.ID, keep.ID, now))
+
+	for childID, want := range map[uint]string{charlie.ID: models.GuardianOwner, dana.ID: models.GuardianOwner, nell.ID: models.GuardianViewer} {
+		role, err := repository.GuardianRole(db, keep.ID, childID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, role, childID)
+	}
+	var guardianships int64
+	db.Model(&models.Guardianship{}).Where("guardian_id IN ?", []uint{keep.ID, duplicate.ID}).Count(&guardianships)
+	assert.Equal(t, int64(1), guardianships, "only the viewer role for Nell is left")
+
+	var rules int64
+	db.Model(&models.PointRule{}).Where("parent_id = ?", keep.ID).Count(&rules)
+	assert.Equal(t, int64(1), rules)
+
+	db.First(&duplicate, duplicate.ID)
+	assert.NotNil(t, duplicate.DeactivatedAt)
+	sessions, _ := repository.ListActiveAuthSessions(db, []uint{duplicate.ID}, now)
+	assert.Empty(t, sessions)
+}
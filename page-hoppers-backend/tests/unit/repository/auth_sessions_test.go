@@ -0,0 +1,72 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestRotateRefreshToken(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	session, token, err := repository.CreateAuthSession(db, 7, "Safari", "10.0.0.1", now)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, session.RefreshTokenHash, "only the hash is stored")
+
+	rotated, next, err := repository.RotateRefreshToken(db, token, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, rotated.ID)
+	assert.NotEqual(t, token, next)
+	assert.Equal(t, now.Add(time.Hour+repository.RefreshTokenTTL), rotated.ExpiresAt)
+
+	_, _, err = repository.RotateRefreshToken(db, "not-a-token", now)
+	assert.Equal(t, repository.ErrInvalidRefreshToken, err)
+
+	active, err := repository.IsAuthSessionActive(db, session.ID, 7, now)
+	assert.NoError(t, err)
+	assert.True(t, active)
+	active, _ = repository.IsAuthSessionActive(db, session.ID, 8, now)
+	assert.False(t, active, "a session only works for its own user")
+}
+
+func TestRotateRefreshToken_ReuseRevokesSession(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	session, token, _ := repository.CreateAuthSession(db, 7, "", "", now)
+	_, next, err := repository.RotateRefreshToken(db, token, now)
+	assert.NoError(t, err)
+
+	_, _, err = repository.RotateRefreshToken(db, token, now)
+	assert.Equal(t, repository.ErrRefreshTokenReused, err)
+
+	_, _, err = repository.RotateRefreshToken(db, next, now)
+	assert.Equal(t, repository.ErrInvalidRefreshToken, err, "the legitimate token dies with the session")
+	active, _ := repository.IsAuthSessionActive(db, session.ID, 7, now)
+	assert.False(t, active)
+}
+
+func TestRotateRefreshToken_Expired(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	session, token, _ := repository.CreateAuthSession(db, 7, "", "", now)
+	later := now.Add(repository.RefreshTokenTTL + time.Minute)
+	_, _, err := repository.RotateRefreshToken(db, token, later)
+	assert.Equal(t, repository.ErrInvalidRefreshToken, err)
+
+	active, _ := repository.IsAuthSessionActive(db, session.ID, 7, later)
+	assert.False(t, active)
+	sessions, err := repository.ListActiveAuthSessions(db, []uint{7}, later)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	var stored models.AuthSession
+	db.First(&stored, session.ID)
+	assert.Nil(t, stored.RevokedAt, "expiry alone doesn't mark a session revoked")
+}
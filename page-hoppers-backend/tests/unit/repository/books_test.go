@@ -0,0 +1,153 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestBookMatchKey(t *testing.T) {
+	assert.Equal(t, repository.BookMatchKey("The BFG", "Roald Dahl"), repository.BookMatchKey("  the bfg ", "roald  dahl."))
+	assert.NotEqual(t, repository.BookMatchKey("The BFG", "Roald Dahl"), repository.BookMatchKey("Matilda", "Roald Dahl"))
+}
+
+func TestFindOrCreateBook_MatchesKeyThenText(t *testing.T) {
+	db := setupTestDB(t)
+
+	first, err := repository.FindOrCreateBook(db, models.Book{Title: "Matilda", Author: "Roald Dahl"})
+	assert.NoError(t, err)
+
+	coverID := 42
+	second, err := repository.FindOrCreateBook(db, models.Book{Title: "matilda", Author: "Roald Dahl", OpenLibraryKey: "/works/OL45804W", CoverID: &coverID})
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID, "a free-text entry picks up the Open Library key")
+
+	byKey, err := repository.FindOrCreateBook(db, models.Book{Title: "Matilda (Puffin edition)", OpenLibraryKey: "/works/OL45804W"})
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, byKey.ID)
+
+	var stored models.Book
+	db.First(&stored, first.ID)
+	assert.Equal(t, "/works/OL45804W", stored.OpenLibraryKey)
+	assert.Equal(t, 42, *stored.CoverID)
+}
+
+// TestMigrateBooks checks that sibling logs with different spellings share one Book
+func TestMigrateBooks(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	for _, log := range []models.ReadingLog{
+		{ChildID: 1, Title: "The BFG", Author: "Roald Dahl", Status: models.StatusCompleted, Date: now},
+		{ChildID: 2, Title: "the BFG", Author: "roald dahl", Status: models.StatusReading, Date: now},
+		{ChildID: 2, Title: "Matilda", Author: "Roald Dahl", Status: models.StatusReading, Date: now},
+	} {
+		assert.NoError(t, db.Create(&log).Error)
+	}
+
+	assert.NoError(t, repository.MigrateBooks(db))
+
+	var books []models.Book
+	db.Find(&books)
+	assert.Len(t, books, 2)
+
+	var logs []models.ReadingLog
+	db.Order("id").Find(&logs)
+	assert.Equal(t, *logs[0].BookID, *logs[1].BookID)
+	assert.Equal(t, "The BFG", logs[1].Title, "copies are rewritten to the catalog spelling")
+	assert.NotEqual(t, *logs[0].BookID, *logs[2].BookID)
+}
+
+func TestMigrateBooks_MergesAChildsDuplicateLogs(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	started := day.AddDate(0, 0, -7)
+
+	older := models.ReadingLog{ChildID: 1, Title: "The BFG", Status: models.StatusReading, Date: started, StartedAt: &started}
+	newer := models.ReadingLog{ChildID: 1, Title: "the bfg ", Status: models.StatusCompleted, Date: day, StartedAt: &day, CompletedAt: &day}
+	sibling := models.ReadingLog{ChildID: 2, Title: "The BFG", Status: models.StatusReading, Date: day}
+	for _, log := range []*models.ReadingLog{&older, &newer, &sibling} {
+		assert.NoError(t, db.Create(log).Error)
+	}
+	db.Create(&models.ReadingSession{ReadingLogID: older.ID, ChildID: 1, Minutes: 20, ReadAt: started})
+	db.Create(&models.ReadingLogTransition{ReadingLogID: older.ID, ToStatus: models.StatusReading, ChangedAt: started, ChangedByID: 1})
+
+	assert.NoError(t, repository.MigrateBooks(db))
+
+	var logs []models.ReadingLog
+	db.Order("id").Find(&logs)
+	if assert.Len(t, logs, 2, "the older log is folded into the newer one; the sibling's is their own") {
+		assert.Equal(t, newer.ID, logs[0].ID)
+		assert.Equal(t, models.StatusCompleted, logs[0].Status)
+		assert.True(t, started.Equal(*logs[0].StartedAt), "the merged log keeps the earliest start")
+		assert.Equal(t, sibling.ID, logs[1].ID)
+	}
+
+	var moved int64
+	db.Model(&models.ReadingSession{}).Where("reading_log_id = ?", newer.ID).Count(&moved)
+	assert.Equal(t, int64(1), moved)
+	db.Model(&models.ReadingLogTransition{}).Where("reading_log_id = ?", newer.ID).Count(&moved)
+	assert.Equal(t, int64(1), moved)
+
+	var deleted models.ReadingLog
+	assert.NoError(t, db.Unscoped().First(&deleted, older.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid, "merged duplicates go to the trash")
+}
+
+func TestMigrateBooks_LeavesLinkedLogsAlone(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	book, _ := repository.FindOrCreateBook(db, models.Book{Title: "The BFG"})
+	for _, date := range []time.Time{day.AddDate(0, 0, -7), day} {
+		assert.NoError(t, db.Create(&models.ReadingLog{ChildID: 1, BookID: &book.ID, Title: "The BFG", Status: models.StatusReading, Date: date}).Error)
+	}
+
+	assert.NoError(t, repository.MigrateBooks(db))
+
+	var live int64
+	db.Model(&models.ReadingLog{}).Where("child_id = ?", 1).Count(&live)
+	assert.Equal(t, int64(2), live, "only logs linked by the migration itself are merged")
+}
+
+func TestCurateBook(t *testing.T) {
+	db := setupTestDB(t)
+	existing, _ := repository.FindOrCreateBook(db, models.Book{Title: "Matilda", Author: "Roald Dahl"})
+
+	book, err := repository.CurateBook(db, models.Book{Title: "matilda", Author: "roald dahl", ISBN: "9780142410370"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID, book.ID, "an existing catalog entry is curated rather than duplicated")
+	db.First(book, book.ID)
+	assert.True(t, book.Curated)
+	assert.Equal(t, "9780142410370", book.ISBN)
+
+	_, err = repository.CurateBook(db, models.Book{Title: "Matilda", ISBN: "9780142410370"}, false)
+	assert.NoError(t, err)
+	db.First(book, book.ID)
+	assert.False(t, book.Curated)
+}
+
+func TestCurateBook_SetsTheSeries(t *testing.T) {
+	db := setupTestDB(t)
+	series := "A Series of Unfortunate Events"
+	book, err := repository.CurateBook(db, models.Book{Title: "The Bad Beginning", Author: "Lemony Snicket", Series: series}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, series, book.Series)
+
+	// A child's log of the book finds the curated entry, series and all
+	logged, err := repository.FindOrCreateBook(db, models.Book{Title: "the bad beginning", Author: "Lemony Snicket"})
+	assert.NoError(t, err)
+	assert.Equal(t, book.ID, logged.ID)
+	assert.Equal(t, series, logged.Series)
+
+	// Curating again with another series corrects it; curating without one leaves it be
+	_, err = repository.CurateBook(db, models.Book{Title: "The Bad Beginning", Author: "Lemony Snicket", Series: "Unfortunate Events"}, true)
+	assert.NoError(t, err)
+	_, err = repository.CurateBook(db, models.Book{Title: "The Bad Beginning", Author: "Lemony Snicket"}, true)
+	assert.NoError(t, err)
+	db.First(book, book.ID)
+	assert.Equal(t, "Unfortunate Events", book.Series)
+}
@@ -0,0 +1,75 @@
+package unit_repository_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestNewJoinCode_AvoidsLookAlikes(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		code, err := repository.NewJoinCode()
+		assert.NoError(t, err)
+		assert.Len(t, code, 8)
+		assert.False(t, strings.ContainsAny(code, "01IO"), code)
+	}
+	assert.Equal(t, "ABCD2345", repository.NormalizeJoinCode(" abcd-2345 "))
+}
+
+func TestJoinClassroom_MembershipAndWithdrawal(t *testing.T) {
+	db := setupTestDB(t)
+	teacher := models.User{Name: "Ms Frizzle", Role: "teacher", Email: "frizzle@example.com"}
+	parent := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com"}
+	db.Create(&teacher)
+	db.Create(&parent)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &parent.ID}
+	db.Create(&child)
+	now := time.Date(2025, time.September, 3, 9, 0, 0, 0, time.UTC)
+
+	classroom, err := repository.CreateClassroom(db, teacher.ID, "Class 3B")
+	assert.NoError(t, err)
+
+	_, err = repository.JoinClassroom(db, "WRONG234", child.ID, parent.ID, now)
+	assert.Equal(t, repository.ErrInvalidJoinCode, err)
+	_, err = repository.JoinClassroom(db, "", child.ID, parent.ID, now)
+	assert.Equal(t, repository.ErrInvalidJoinCode, err)
+
+	for i := 0; i < 2; i++ {
+		joined, err := repository.JoinClassroom(db, strings.ToLower(classroom.JoinCode), child.ID, parent.ID, now)
+		assert.NoError(t, err)
+		assert.Equal(t, classroom.ID, joined.ID)
+	}
+
+	students, err := repository.ClassroomStudents(db, classroom.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, students, 1) {
+		assert.Equal(t, child.ID, students[0].ID)
+	}
+
+	classes, err := repository.ChildClassrooms(db, child.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, classes, 1) {
+		assert.Equal(t, "Class 3B", classes[0].Name)
+		assert.Equal(t, "Ms Frizzle", classes[0].TeacherName)
+		assert.True(t, now.Equal(classes[0].ConsentedAt))
+	}
+
+	left, err := repository.LeaveClassroom(db, classroom.ID, child.ID)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	left, _ = repository.LeaveClassroom(db, classroom.ID, child.ID)
+	assert.False(t, left)
+	students, _ = repository.ClassroomStudents(db, classroom.ID)
+	assert.Empty(t, students)
+
+	// The child can be added again after consent was withdrawn
+	_, err = repository.JoinClassroom(db, classroom.JoinCode, child.ID, parent.ID, now)
+	assert.NoError(t, err)
+	students, _ = repository.ClassroomStudents(db, classroom.ID)
+	assert.Len(t, students, 1)
+}
@@ -0,0 +1,51 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestEmailToken_SingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	record, tokenID, err := repository.IssueEmailToken(db, 7, models.EmailTokenResetPassword, "bob@example.com", time.Hour, now)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tokenID, record.TokenHash, "only the hash is stored")
+
+	_, err = repository.ConsumeEmailToken(db, tokenID, models.EmailTokenVerifyEmail, now)
+	assert.Equal(t, repository.ErrInvalidEmailToken, err, "a token only works for its purpose")
+
+	used, err := repository.ConsumeEmailToken(db, tokenID, models.EmailTokenResetPassword, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), used.UserID)
+	assert.Equal(t, "bob@example.com", used.Email)
+
+	_, err = repository.ConsumeEmailToken(db, tokenID, models.EmailTokenResetPassword, now.Add(time.Minute))
+	assert.Equal(t, repository.ErrInvalidEmailToken, err, "a token works once")
+}
+
+func TestEmailToken_ExpiresAndIsReplaced(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	_, expired, _ := repository.IssueEmailToken(db, 7, models.EmailTokenVerifyEmail, "bob@example.com", time.Hour, now)
+	_, err := repository.ConsumeEmailToken(db, expired, models.EmailTokenVerifyEmail, now.Add(time.Hour))
+	assert.Equal(t, repository.ErrInvalidEmailToken, err)
+
+	_, first, _ := repository.IssueEmailToken(db, 7, models.EmailTokenVerifyEmail, "bob@example.com", time.Hour, now)
+	_, second, _ := repository.IssueEmailToken(db, 7, models.EmailTokenVerifyEmail, "bob@example.com", time.Hour, now)
+	_, other, _ := repository.IssueEmailToken(db, 8, models.EmailTokenVerifyEmail, "dana@example.com", time.Hour, now)
+
+	_, err = repository.ConsumeEmailToken(db, first, models.EmailTokenVerifyEmail, now)
+	assert.Equal(t, repository.ErrInvalidEmailToken, err, "a newer email replaces the older link")
+	_, err = repository.ConsumeEmailToken(db, second, models.EmailTokenVerifyEmail, now)
+	assert.NoError(t, err)
+	_, err = repository.ConsumeEmailToken(db, other, models.EmailTokenVerifyEmail, now)
+	assert.NoError(t, err, "other users' links are unaffected")
+}
@@ -0,0 +1,64 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestFamilyDevice_PairFindRevoke(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	device, token, err := repository.CreateFamilyDevice(db, 1, "Kitchen tablet", now)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, device.TokenHash, "only the hash is stored")
+
+	found, err := repository.FindFamilyDevice(db, token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, device.ID, found.ID)
+	_, err = repository.FindFamilyDevice(db, "not-a-token", now)
+	assert.Equal(t, repository.ErrInvalidDeviceToken, err)
+
+	revoked, err := repository.RevokeFamilyDevice(db, 2, device.ID, now)
+	assert.NoError(t, err)
+	assert.False(t, revoked, "only the parent who paired it can unpair it")
+	revoked, err = repository.RevokeFamilyDevice(db, 1, device.ID, now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	_, err = repository.FindFamilyDevice(db, token, now)
+	assert.Equal(t, repository.ErrInvalidDeviceToken, err)
+	devices, _ := repository.ListFamilyDevices(db, 1)
+	assert.Empty(t, devices)
+}
+
+func TestFamilyLoginChildren_AssignsStableKeys(t *testing.T) {
+	db := setupTestDB(t)
+	parentID := uint(1)
+	db.Create(&models.User{Name: "Charlie", Role: "child", ParentID: &parentID, Avatar: "🦊"})
+	db.Create(&models.User{Name: "Dana", Role: "child", ParentID: &parentID})
+	otherParent := uint(2)
+	db.Create(&models.User{Name: "Eve", Role: "child", ParentID: &otherParent})
+
+	children, err := repository.FamilyLoginChildren(db, parentID)
+	assert.NoError(t, err)
+	if assert.Len(t, children, 2) {
+		assert.Equal(t, "Charlie", children[0].Name)
+		assert.NotEmpty(t, children[0].LoginKey)
+		assert.NotEqual(t, children[0].LoginKey, children[1].LoginKey)
+	}
+
+	again, _ := repository.FamilyLoginChildren(db, parentID)
+	assert.Equal(t, children[0].LoginKey, again[0].LoginKey, "keys don't change once given")
+
+	child, err := repository.FindChildByLoginKey(db, parentID, children[1].LoginKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "Dana", child.Name)
+	_, err = repository.FindChildByLoginKey(db, otherParent, children[1].LoginKey)
+	assert.Error(t, err, "keys only work within their family")
+}
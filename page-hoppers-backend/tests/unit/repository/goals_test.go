@@ -0,0 +1,46 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestGetGoalProgress(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(6)
+	now := time.Date(2025, time.March, 16, 12, 0, 0, 0, time.UTC) // about half way through March
+
+	for _, day := range []int{3, 10} {
+		completedAt := time.Date(2025, time.March, day, 18, 0, 0, 0, time.UTC)
+		db.Create(&models.ReadingLog{ChildID: childID, Title: "Book", Status: models.StatusCompleted, Date: completedAt, CompletedAt: &completedAt})
+	}
+	start, end := 1, 121
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: 1, Minutes: 45, StartPage: &start, EndPage: &end, ReadAt: now.Add(-time.Hour)})
+
+	goals := []models.Goal{
+		{ChildID: childID, Metric: models.GoalMetricBooks, Period: models.GoalPeriodMonth, Target: 4},
+		{ChildID: childID, Metric: models.GoalMetricBooks, Period: models.GoalPeriodMonth, Target: 2},
+		{ChildID: childID, Metric: models.GoalMetricMinutes, Period: models.GoalPeriodWeek, Target: 120},
+		{ChildID: childID, Metric: models.GoalMetricPages, Period: models.GoalPeriodTerm, Target: 140},
+	}
+	progress, err := repository.GetGoalProgress(db, goals, now, repository.DefaultSchoolCalendar)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, progress[0].Current)
+	assert.Equal(t, models.GoalStatusOnTrack, progress[0].Status, "2 of 4 books half way through the month")
+	assert.Equal(t, models.GoalStatusAchieved, progress[1].Status)
+	assert.Equal(t, float64(100), progress[1].Percent)
+
+	assert.Equal(t, 45, progress[2].Current)
+	assert.Equal(t, 111, progress[2].Expected, "by Sunday noon 6.5 of the week's 7 days have gone")
+	assert.Equal(t, models.GoalStatusBehind, progress[2].Status)
+
+	assert.Equal(t, 120, progress[3].Current)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), progress[3].PeriodStart, "spring term")
+	assert.Equal(t, models.GoalStatusOnTrack, progress[3].Status)
+}
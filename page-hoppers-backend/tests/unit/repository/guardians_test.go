@@ -0,0 +1,91 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestGuardianRole_OwnerAndInvited(t *testing.T) {
+	db := setupTestDB(t)
+	owner := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com"}
+	coParent := models.User{Name: "Alex", Role: "parent", Email: "alex@example.com"}
+	stranger := models.User{Name: "Dana", Role: "parent", Email: "dana@example.com"}
+	db.Create(&owner)
+	db.Create(&coParent)
+	db.Create(&stranger)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &owner.ID}
+	db.Create(&child)
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: coParent.ID, Role: models.GuardianCoParent})
+
+	for _, tc := range []struct {
+		parentID uint
+		want     string
+	}{
+		{owner.ID, models.GuardianOwner},
+		{coParent.ID, models.GuardianCoParent},
+		{stranger.ID, ""},
+	} {
+		role, err := repository.GuardianRole(db, tc.parentID, child.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, role)
+	}
+
+	var ids []uint
+	db.Model(&models.User{}).Scopes(repository.GuardedChildren(coParent.ID)).Pluck("users.id", &ids)
+	assert.Equal(t, []uint{child.ID}, ids)
+	ids = nil
+	db.Model(&models.User{}).Scopes(repository.GuardedChildren(coParent.ID, models.GuardianViewer)).Pluck("users.id", &ids)
+	assert.Empty(t, ids, "the role filter applies")
+
+	guardians, err := repository.ListGuardians(db, child.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, guardians, 2) {
+		assert.Equal(t, models.GuardianOwner, guardians[0].Role)
+		assert.Equal(t, "alex@example.com", guardians[1].Email)
+	}
+}
+
+func TestAcceptGuardianInvite(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	owner := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com"}
+	grandma := models.User{Name: "Gran", Role: "parent", Email: "gran@example.com"}
+	other := models.User{Name: "Dana", Role: "parent", Email: "dana@example.com"}
+	db.Create(&owner)
+	db.Create(&grandma)
+	db.Create(&other)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &owner.ID}
+	db.Create(&child)
+
+	_, replaced, err := repository.CreateGuardianInvite(db, child.ID, owner.ID, "Gran@Example.com ", models.GuardianCoParent, now)
+	assert.NoError(t, err)
+	_, token, err := repository.CreateGuardianInvite(db, child.ID, owner.ID, "gran@example.com", models.GuardianViewer, now)
+	assert.NoError(t, err)
+
+	_, err = repository.AcceptGuardianInvite(db, replaced, &grandma, now)
+	assert.Equal(t, repository.ErrInvalidGuardianInvite, err, "a newer invite to the same address replaces the old one")
+	_, err = repository.AcceptGuardianInvite(db, token, &other, now)
+	assert.Equal(t, repository.ErrGuardianInviteEmail, err)
+	_, err = repository.AcceptGuardianInvite(db, token, &grandma, now.Add(models.GuardianInviteTTL))
+	assert.Equal(t, repository.ErrInvalidGuardianInvite, err, "invites expire")
+
+	invite, err := repository.AcceptGuardianInvite(db, token, &grandma, now)
+	assert.NoError(t, err)
+	assert.Equal(t, grandma.ID, *invite.AcceptedByID)
+	role, _ := repository.GuardianRole(db, grandma.ID, child.ID)
+	assert.Equal(t, models.GuardianViewer, role)
+
+	_, err = repository.AcceptGuardianInvite(db, token, &grandma, now)
+	assert.Equal(t, repository.ErrInvalidGuardianInvite, err, "invites work once")
+
+	removed, err := repository.RemoveGuardian(db, child.ID, grandma.ID)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	role, _ = repository.GuardianRole(db, grandma.ID, child.ID)
+	assert.Empty(t, role)
+}
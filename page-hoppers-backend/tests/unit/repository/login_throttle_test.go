@@ -0,0 +1,110 @@
+package unit_repository_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestLoginThrottle_BacksOffThenLocks(t *testing.T) {
+	db := setupTestDB(t)
+	policy := repository.ThrottlePolicy{FreeAttempts: 2, MaxDelay: 4 * time.Second, LockoutAfter: 5, LockoutFor: time.Minute, ResetAfter: time.Hour}
+	key := repository.ChildThrottleKey(1)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	wantDelays := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second}
+	for i, want := range wantDelays {
+		lockedNow, err := repository.RecordLoginFailure(db, key, policy, now)
+		assert.NoError(t, err)
+		assert.False(t, lockedNow)
+
+		wait, locked, err := repository.CheckLoginThrottle(db, key, policy, now)
+		assert.NoError(t, err)
+		assert.False(t, locked)
+		assert.Equal(t, want, wait, "after %d failures", i+1)
+		now = now.Add(wait)
+	}
+
+	lockedNow, err := repository.RecordLoginFailure(db, key, policy, now)
+	assert.NoError(t, err)
+	assert.True(t, lockedNow, "the fifth failure locks the key")
+	wait, locked, _ := repository.CheckLoginThrottle(db, key, policy, now)
+	assert.True(t, locked)
+	assert.Equal(t, time.Minute, wait)
+
+	lockedNow, _ = repository.RecordLoginFailure(db, key, policy, now.Add(time.Second))
+	assert.False(t, lockedNow, "a lockout is only reported once")
+
+	wait, _, _ = repository.CheckLoginThrottle(db, repository.ChildThrottleKey(2), policy, now)
+	assert.Zero(t, wait, "other keys are unaffected")
+}
+
+func TestLoginThrottle_ResetAndClear(t *testing.T) {
+	db := setupTestDB(t)
+	policy := repository.ChildPINPolicy
+	key := repository.IPThrottleKey("10.0.0.1")
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < policy.FreeAttempts+1; i++ {
+		_, err := repository.RecordLoginFailure(db, key, policy, now)
+		assert.NoError(t, err)
+	}
+	wait, _, _ := repository.CheckLoginThrottle(db, key, policy, now)
+	assert.Positive(t, wait)
+
+	wait, _, _ = repository.CheckLoginThrottle(db, key, policy, now.Add(policy.ResetAfter))
+	assert.Zero(t, wait, "old failures are forgotten")
+	_, err := repository.RecordLoginFailure(db, key, policy, now.Add(policy.ResetAfter))
+	assert.NoError(t, err)
+	wait, _, _ = repository.CheckLoginThrottle(db, key, policy, now.Add(policy.ResetAfter))
+	assert.Zero(t, wait, "counting starts again from one")
+
+	assert.NoError(t, repository.ClearLoginThrottle(db, key))
+	wait, locked, err := repository.CheckLoginThrottle(db, key, policy, now)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+	assert.Zero(t, wait)
+}
+
+func TestReserveLoginAttempt_ParallelGuessesEachTakeATurn(t *testing.T) {
+	db := setupTestDB(t)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // one in-memory database, shared by every goroutine
+	policy := repository.ChildPINPolicy
+	key := repository.ChildThrottleKey(1)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	allowed := 0
+	for i := 0; i < 3*policy.FreeAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt, err := repository.ReserveLoginAttempt(db, key, policy, now)
+			assert.NoError(t, err)
+			if attempt.Wait == 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, policy.FreeAttempts, allowed, "guesses at the same moment only get the free attempts")
+
+	attempt, err := repository.ReserveLoginAttempt(db, key, policy, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, attempt.Wait, "refused attempts aren't counted")
+
+	now = now.Add(time.Second)
+	attempt, _ = repository.ReserveLoginAttempt(db, key, policy, now)
+	assert.Zero(t, attempt.Wait)
+	assert.NoError(t, repository.ReleaseLoginAttempt(db, key))
+	attempt, _ = repository.ReserveLoginAttempt(db, key, policy, now.Add(time.Second))
+	assert.Zero(t, attempt.Wait, "a released attempt gives its place back")
+}
@@ -0,0 +1,134 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestCreateOrganization_ValidatesSlug(t *testing.T) {
+	db := setupTestDB(t)
+
+	for _, slug := range []string{"", "x", "Maple", "maple primary", "-maple", "maple_primary"} {
+		_, err := repository.CreateOrganization(db, "Maple Primary", slug)
+		assert.Equal(t, repository.ErrInvalidOrganizationSlug, err, slug)
+	}
+
+	org, err := repository.CreateOrganization(db, "Maple Primary", "maple-primary")
+	assert.NoError(t, err)
+	found, err := repository.FindOrganization(db, "maple-primary")
+	assert.NoError(t, err)
+	assert.Equal(t, org.ID, found.ID)
+
+	_, err = repository.CreateOrganization(db, "Another Maple", "maple-primary")
+	assert.Error(t, err, "slugs are unique")
+	_, err = repository.FindOrganization(db, "nowhere")
+	assert.Equal(t, repository.ErrUnknownOrganization, err)
+
+	// No slug is the default organization the migration creates
+	def, err := repository.FindOrganization(db, "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultOrganizationSlug, def.Slug)
+}
+
+func TestMigrateOrganizations_BackfillsAccountsAndClassrooms(t *testing.T) {
+	db := setupTestDB(t)
+	other, _ := repository.CreateOrganization(db, "Maple Primary", "maple")
+
+	// Rows from before organizations have none; one family was already moved to Maple
+	parent := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com"}
+	teacher := models.User{Name: "Ms Frizzle", Role: "teacher", Email: "frizzle@example.com"}
+	mapleParent := models.User{Name: "Mia", Role: "parent", Email: "mia@example.com", OrganizationID: other.ID}
+	db.Create(&parent)
+	db.Create(&teacher)
+	db.Create(&mapleParent)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &parent.ID}
+	mapleChild := models.User{Name: "Max", Role: "child", ParentID: &mapleParent.ID}
+	orphan := models.User{Name: "Olive", Role: "child"}
+	db.Create(&child)
+	db.Create(&mapleChild)
+	db.Create(&orphan)
+	classroom := models.Classroom{TeacherID: teacher.ID, Name: "Class 3B", JoinCode: "ABCD2345"}
+	db.Create(&classroom)
+
+	assert.NoError(t, repository.MigrateOrganizations(db))
+	// Running it again changes nothing
+	assert.NoError(t, repository.MigrateOrganizations(db))
+
+	def, _ := repository.FindOrganization(db, "")
+	for user, want := range map[*models.User]uint{
+		&parent: def.ID, &teacher: def.ID, &child: def.ID, &orphan: def.ID,
+		&mapleParent: other.ID, &mapleChild: other.ID,
+	} {
+		orgID, err := repository.UserOrganizationID(db, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, orgID, user.Name)
+	}
+	db.First(&classroom, classroom.ID)
+	assert.Equal(t, def.ID, classroom.OrganizationID)
+
+	var count int64
+	db.Model(&models.Organization{}).Where("slug = ?", models.DefaultOrganizationSlug).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOrganizationQueries_StayInTheirTenant(t *testing.T) {
+	db := setupTestDB(t)
+	def, _ := repository.FindOrganization(db, "")
+	other, _ := repository.CreateOrganization(db, "Maple Primary", "maple")
+	now := time.Date(2025, time.September, 3, 9, 0, 0, 0, time.UTC)
+
+	parent := models.User{Name: "Bob", Role: "parent", Email: "bob@example.com", OrganizationID: def.ID}
+	teacher := models.User{Name: "Ms Frizzle", Role: "teacher", Email: "frizzle@example.com", OrganizationID: def.ID}
+	mapleParent := models.User{Name: "Mia", Role: "parent", Email: "mia@example.com", OrganizationID: other.ID}
+	mapleTeacher := models.User{Name: "Mr Hill", Role: "teacher", Email: "hill@example.com", OrganizationID: other.ID}
+	db.Create(&parent)
+	db.Create(&teacher)
+	db.Create(&mapleParent)
+	db.Create(&mapleTeacher)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &parent.ID, OrganizationID: def.ID}
+	db.Create(&child)
+
+	members, err := repository.OrganizationMembers(db, other.ID, "")
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+	members, err = repository.OrganizationMembers(db, def.ID, "teacher")
+	assert.NoError(t, err)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, teacher.ID, members[0].ID)
+	}
+
+	// A guardianship that crosses organizations (only possible by editing the database) grants nothing
+	db.Create(&models.Guardianship{ChildID: child.ID, GuardianID: mapleParent.ID, Role: models.GuardianCoParent})
+	var ids []uint
+	db.Model(&models.User{}).Scopes(repository.GuardedChildren(mapleParent.ID)).Pluck("users.id", &ids)
+	assert.Empty(t, ids)
+	db.Model(&models.User{}).Scopes(repository.GuardedChildren(parent.ID)).Pluck("users.id", &ids)
+	assert.Equal(t, []uint{child.ID}, ids)
+
+	// Another organization's join code is treated as unknown
+	mapleClass, err := repository.CreateClassroom(db, mapleTeacher.ID, "Maple 2A")
+	assert.NoError(t, err)
+	assert.Equal(t, other.ID, mapleClass.OrganizationID)
+	_, err = repository.JoinClassroom(db, mapleClass.JoinCode, child.ID, parent.ID, now)
+	assert.Equal(t, repository.ErrInvalidJoinCode, err)
+
+	classrooms, err := repository.OrganizationClassrooms(db, other.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, classrooms, 1) {
+		assert.Equal(t, "Mr Hill", classrooms[0].TeacherName)
+		assert.Equal(t, 0, classrooms[0].Students)
+	}
+	classrooms, _ = repository.OrganizationClassrooms(db, def.ID)
+	assert.Empty(t, classrooms)
+
+	// An invite can't bring an adult from another organization into the family
+	_, token, err := repository.CreateGuardianInvite(db, child.ID, parent.ID, mapleParent.Email, models.GuardianViewer, now)
+	assert.NoError(t, err)
+	_, err = repository.AcceptGuardianInvite(db, token, &mapleParent, now)
+	assert.Equal(t, repository.ErrGuardianInviteOrganization, err)
+}
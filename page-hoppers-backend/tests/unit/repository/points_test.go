@@ -0,0 +1,137 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestAwardPoints_DefaultRulesCreditEachSourceOnce(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	log := models.ReadingLog{ChildID: childID, Title: "Matilda", Status: models.StatusCompleted, Date: now, CompletedAt: &now}
+	db.Create(&log)
+	entry, err := repository.AwardCompletionPoints(db, &log)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, entry.Points)
+
+	entry, err = repository.AwardCompletionPoints(db, &log)
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "the same completion isn't credited twice")
+
+	// Re-reading and completing again doesn't pay the bonus again
+	later := now.Add(24 * time.Hour)
+	log.CompletedAt = &later
+	db.Save(&log)
+	entry, err = repository.AwardCompletionPoints(db, &log)
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "a log earns its completion once")
+
+	session := models.ReadingSession{ReadingLogID: log.ID, ChildID: childID, Minutes: 25, ReadAt: now}
+	db.Create(&session)
+	entry, err = repository.AwardSessionPoints(db, &session)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, entry.Points, "1 point per whole 10 minutes")
+
+	reading := models.ReadingLog{ChildID: childID, Title: "Holes", Status: models.StatusReading, Date: now}
+	db.Create(&reading)
+	entry, err = repository.AwardCompletionPoints(db, &reading)
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "only completed logs earn")
+
+	balance, err := repository.GetPointsBalance(db, childID)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.PointsBalance{Earned: 12, Balance: 12, Available: 12}, balance)
+}
+
+func TestAwardPoints_FamilyRules(t *testing.T) {
+	db := setupTestDB(t)
+	parent := models.User{Name: "Bob", Role: "parent"}
+	db.Create(&parent)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &parent.ID}
+	db.Create(&child)
+
+	assert.NoError(t, repository.SetPointRules(db, parent.ID, []models.PointRule{
+		{Event: models.PointEventSession, Points: 3},
+		{Event: models.PointEventPages, Points: 1, Per: 5},
+		{Event: models.PointEventBookCompleted, Points: 0},
+	}))
+
+	start, end := 10, 32
+	session := models.ReadingSession{ChildID: child.ID, StartPage: &start, EndPage: &end, Minutes: 60, ReadAt: time.Now()}
+	db.Create(&session)
+	entry, err := repository.AwardSessionPoints(db, &session)
+	assert.NoError(t, err)
+	assert.Equal(t, 3+4, entry.Points, "minutes earn nothing once the family drops that rule")
+
+	completedAt := time.Now()
+	log := models.ReadingLog{ChildID: child.ID, Status: models.StatusCompleted, CompletedAt: &completedAt}
+	db.Create(&log)
+	entry, err = repository.AwardCompletionPoints(db, &log)
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "a zero-point rule switches the event off")
+
+	// An empty list goes back to the defaults
+	assert.NoError(t, repository.SetPointRules(db, parent.ID, nil))
+	rules, err := repository.GetPointRules(db, parent.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rules, len(models.DefaultPointRules))
+}
+
+func TestRedemption_HoldsThenSpendsPoints(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	_, err := repository.AdjustPoints(db, childID, 60, "birthday bonus", 99)
+	assert.NoError(t, err)
+
+	reward := models.Reward{ParentID: 99, Name: "30 min screen time", Cost: 50}
+	db.Create(&reward)
+
+	redemption, err := repository.RequestRedemption(db, childID, &reward)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RedemptionStatusRequested, redemption.Status)
+
+	_, err = repository.RequestRedemption(db, childID, &reward)
+	assert.Equal(t, repository.ErrInsufficientPoints, err, "the first request's points are held")
+
+	balance, _ := repository.GetPointsBalance(db, childID)
+	assert.Equal(t, repository.PointsBalance{Earned: 60, Balance: 60, Held: 50, Available: 10}, balance)
+
+	assert.NoError(t, repository.DecideRedemption(db, redemption, true, 99, "enjoy", now))
+	assert.Equal(t, models.RedemptionStatusApproved, redemption.Status)
+	assert.Equal(t, repository.ErrRedemptionDecided, repository.DecideRedemption(db, redemption, true, 99, "", now))
+
+	balance, _ = repository.GetPointsBalance(db, childID)
+	assert.Equal(t, repository.PointsBalance{Earned: 60, Spent: 50, Balance: 10, Available: 10}, balance)
+
+	ledger, err := repository.GetPointsLedger(db, childID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, ledger, 2)
+	assert.Equal(t, -50, ledger[0].Points)
+	assert.Equal(t, models.PointsReasonRedemption, ledger[0].Reason)
+}
+
+func TestRedemption_RejectReleasesHeldPoints(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	repository.AdjustPoints(db, childID, 50, "", 99)
+	reward := models.Reward{ParentID: 99, Name: "Ice cream", Cost: 40}
+	db.Create(&reward)
+
+	redemption, err := repository.RequestRedemption(db, childID, &reward)
+	assert.NoError(t, err)
+	assert.NoError(t, repository.DecideRedemption(db, redemption, false, 99, "not on a school night", time.Now()))
+
+	balance, _ := repository.GetPointsBalance(db, childID)
+	assert.Equal(t, 50, balance.Available)
+
+	_, err = repository.AdjustPoints(db, childID, -60, "", 99)
+	assert.Equal(t, repository.ErrInsufficientPoints, err, "adjustments can't go below zero")
+}
@@ -0,0 +1,71 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestApproval_PendingLogsDontCount(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+	approved := models.ReadingLog{ChildID: childID, Title: "Matilda", Status: models.StatusCompleted, Date: now, CompletedAt: &now}
+	db.Create(&approved)
+	pending := models.ReadingLog{ChildID: childID, Title: "Holes", Status: models.StatusCompleted, Date: now, CompletedAt: &now, ApprovalStatus: models.ApprovalPending}
+	db.Create(&pending)
+	db.Create(&models.ReadingSession{ReadingLogID: pending.ID, ChildID: childID, Minutes: 30, ReadAt: now})
+
+	summary, err := repository.GetReadingSummary(db, childID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.TotalCompletedBooks)
+	assert.Equal(t, 1, summary.PendingApproval)
+
+	period := repository.Period{Start: now.AddDate(0, 0, -1), End: now.AddDate(0, 0, 1)}
+	totals, err := repository.GetPeriodTotals(db, childID, []repository.Period{period})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, totals[0].BooksCompleted)
+	assert.Equal(t, 0, totals[0].MinutesRead, "sessions on a pending log wait too")
+
+	entry, err := repository.AwardCompletionPoints(db, &pending)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	assert.NoError(t, repository.ReviewReadingLog(db, &pending, true, 99, "", now))
+	assert.Equal(t, models.ApprovalApproved, pending.ApprovalStatus)
+	assert.Equal(t, repository.ErrNotPendingApproval, repository.ReviewReadingLog(db, &pending, false, 99, "", now))
+
+	totals, _ = repository.GetPeriodTotals(db, childID, []repository.Period{period})
+	assert.Equal(t, 2, totals[0].BooksCompleted)
+	assert.Equal(t, 30, totals[0].MinutesRead)
+
+	entry, err = repository.AwardCompletionPoints(db, &pending)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, entry.Points, "points are earned once approved")
+}
+
+func TestApproval_HoldClearsEarlierReview(t *testing.T) {
+	db := setupTestDB(t)
+	childID := createChild(db)
+	now := time.Now()
+
+	log := models.ReadingLog{ChildID: childID, Title: "Holes", Status: models.StatusReading, Date: now, ApprovalStatus: models.ApprovalPending}
+	db.Create(&log)
+	assert.NoError(t, repository.ReviewReadingLog(db, &log, false, 99, "we haven't bought this yet", now))
+
+	var stored models.ReadingLog
+	db.First(&stored, log.ID)
+	assert.Equal(t, models.ApprovalRejected, stored.ApprovalStatus)
+	assert.Equal(t, "we haven't bought this yet", stored.ReviewComment)
+
+	assert.NoError(t, repository.HoldForApproval(db, &stored))
+	db.First(&stored, log.ID)
+	assert.Equal(t, models.ApprovalPending, stored.ApprovalStatus)
+	assert.Empty(t, stored.ReviewComment)
+	assert.Nil(t, stored.ReviewedByID)
+}
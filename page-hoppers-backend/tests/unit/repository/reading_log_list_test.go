@@ -0,0 +1,108 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// seedListLogs gives one child seven logs on consecutive days, two of them on the same day
+func seedListLogs(t *testing.T, db *gorm.DB) uint {
+	childID := uint(7)
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	logs := []models.ReadingLog{
+		{Title: "Matilda", Author: "Roald Dahl", Status: models.StatusCompleted, Date: day},
+		{Title: "The BFG", Author: "Roald Dahl", Status: models.StatusCompleted, Date: day.AddDate(0, 0, 1)},
+		{Title: "Wonder", Author: "R. J. Palacio", Status: models.StatusReading, Date: day.AddDate(0, 0, 2)},
+		{Title: "Holes", Author: "Louis Sachar", Status: models.StatusStarted, Date: day.AddDate(0, 0, 3)},
+		{Title: "The Witches", Author: "Roald Dahl", Status: models.StatusWantToRead, Date: day.AddDate(0, 0, 4)},
+		{Title: "Esio Trot", Author: "Roald Dahl", Status: models.StatusCompleted, Date: day.AddDate(0, 0, 4)},
+		{Title: "Charlotte's Web", Author: "E. B. White", Status: models.StatusPaused, Date: day.AddDate(0, 0, 5)},
+	}
+	for i := range logs {
+		logs[i].ChildID = childID
+		assert.NoError(t, db.Create(&logs[i]).Error)
+	}
+	db.Create(&models.ReadingLog{ChildID: 99, Title: "Someone else's book", Status: models.StatusReading, Date: day})
+	return childID
+}
+
+func titlesOf(logs []models.ReadingLog) []string {
+	titles := make([]string, 0, len(logs))
+	for _, log := range logs {
+		titles = append(titles, log.Title)
+	}
+	return titles
+}
+
+func TestListReadingLogs_WalksPagesWithCursor(t *testing.T) {
+	db := setupTestDB(t)
+	childID := seedListLogs(t, db)
+
+	var seen []string
+	query := repository.ReadingLogQuery{Desc: true, Limit: 3}
+	for pages := 0; pages < 5; pages++ {
+		page, err := repository.ListReadingLogs(db, childID, query)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), page.Total)
+		seen = append(seen, titlesOf(page.Logs)...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []string{"Charlotte's Web", "Esio Trot", "The Witches", "Holes", "Wonder", "The BFG", "Matilda"}, seen,
+		"newest first, same-day logs broken by ID, no row skipped or repeated across pages")
+}
+
+func TestListReadingLogs_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	childID := seedListLogs(t, db)
+
+	page, err := repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Author: "dahl", SortBy: repository.SortByTitle})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Esio Trot", "Matilda", "The BFG", "The Witches"}, titlesOf(page.Logs))
+
+	page, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Statuses: []string{models.StatusReading}})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Wonder", "Holes"}, titlesOf(page.Logs), "legacy started rows count as reading")
+
+	from := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	page, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{From: &from, To: &to, Title: "the"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total, "date range is inclusive of both days")
+	assert.ElementsMatch(t, []string{"The BFG", "The Witches"}, titlesOf(page.Logs))
+
+	// Wildcards are taken literally
+	page, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Title: "%"})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Logs)
+	page, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Author: "r_ j"})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Logs)
+	page, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Title: "'s w"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Charlotte's Web"}, titlesOf(page.Logs))
+}
+
+func TestListReadingLogs_RejectsCursorForOtherSort(t *testing.T) {
+	db := setupTestDB(t)
+	childID := seedListLogs(t, db)
+
+	page, err := repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Limit: 2})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, page.NextCursor)
+
+	_, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{SortBy: repository.SortByTitle, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+
+	_, err = repository.ListReadingLogs(db, childID, repository.ReadingLogQuery{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
@@ -0,0 +1,106 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNamedPeriod(t *testing.T) {
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC) // a Wednesday
+	cal := repository.DefaultSchoolCalendar
+
+	cases := []struct {
+		name       string
+		start, end time.Time
+	}{
+		{"this_week", date(2025, 1, 13), date(2025, 1, 20)},
+		{"last_week", date(2025, 1, 6), date(2025, 1, 13)},
+		{"this_month", date(2025, 1, 1), date(2025, 2, 1)},
+		{"last_month", date(2024, 12, 1), date(2025, 1, 1)},
+		{"last_year", date(2024, 1, 1), date(2025, 1, 1)},
+		{"this_term", date(2025, 1, 1), date(2025, 4, 1)},
+		{"last_term", date(2024, 9, 1), date(2025, 1, 1)},
+		{"this_school_year", date(2024, 9, 1), date(2025, 9, 1)},
+		{"last_school_year", date(2023, 9, 1), date(2024, 9, 1)},
+	}
+	for _, tc := range cases {
+		period, err := repository.NamedPeriod(tc.name, now, cal)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.start, period.Start, tc.name)
+		assert.Equal(t, tc.end, period.End, tc.name)
+	}
+
+	_, err := repository.NamedPeriod("next_decade", now, cal)
+	assert.Error(t, err)
+}
+
+func TestSplitPeriods(t *testing.T) {
+	cal := repository.DefaultSchoolCalendar
+
+	months, err := repository.SplitPeriods(date(2025, 1, 20), date(2025, 3, 10), repository.GroupByMonth, cal)
+	assert.NoError(t, err)
+	assert.Len(t, months, 3)
+	assert.Equal(t, "2025-01", months[0].Label)
+	assert.Equal(t, date(2025, 1, 20), months[0].Start, "first bucket is clipped to the range")
+	assert.Equal(t, date(2025, 3, 10), months[2].End, "last bucket is clipped to the range")
+
+	terms, err := repository.SplitPeriods(date(2024, 9, 1), date(2025, 9, 1), repository.GroupByTerm, cal)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-25 autumn", "2024-25 spring", "2024-25 summer"},
+		[]string{terms[0].Label, terms[1].Label, terms[2].Label})
+
+	weeks, err := repository.SplitPeriods(date(2025, 1, 1), date(2025, 1, 15), repository.GroupByWeek, cal)
+	assert.NoError(t, err)
+	assert.Len(t, weeks, 3)
+	assert.Equal(t, date(2025, 1, 6), weeks[1].Start, "weeks start on Monday")
+
+	_, err = repository.SplitPeriods(date(2020, 1, 1), date(2025, 1, 1), repository.GroupByDay, cal)
+	assert.ErrorIs(t, err, repository.ErrTooManyPeriods)
+}
+
+func TestParseSchoolCalendar(t *testing.T) {
+	cal, err := repository.ParseSchoolCalendar("fall:8, winter:1")
+	assert.NoError(t, err)
+	period, err := repository.NamedPeriod("this_school_year", date(2025, 7, 31), cal)
+	assert.NoError(t, err)
+	assert.Equal(t, date(2024, 8, 1), period.Start)
+
+	_, err = repository.ParseSchoolCalendar("fall:13")
+	assert.Error(t, err)
+}
+
+func TestGetPeriodTotals(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(3)
+	completedAt := date(2025, 2, 3)
+	start, end := 10, 40
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "Matilda", Status: models.StatusCompleted, Date: date(2025, 1, 5)})
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "The BFG", Status: models.StatusCompleted, Date: date(2025, 1, 20), CompletedAt: &completedAt})
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "Wonder", Status: models.StatusReading, Date: date(2025, 1, 9)})
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: 3, Minutes: 20, StartPage: &start, EndPage: &end, ReadAt: date(2025, 1, 9)})
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: 3, Minutes: 15, ReadAt: date(2025, 2, 1)})
+	deleted := models.ReadingLog{ChildID: childID, Title: "Holes", Status: models.StatusReading, Date: date(2025, 1, 9)}
+	db.Create(&deleted)
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: deleted.ID, Minutes: 60, StartPage: &start, EndPage: &end, ReadAt: date(2025, 1, 9)})
+	db.Delete(&deleted)
+
+	periods, err := repository.SplitPeriods(date(2025, 1, 1), date(2025, 3, 1), repository.GroupByMonth, repository.DefaultSchoolCalendar)
+	assert.NoError(t, err)
+	totals, err := repository.GetPeriodTotals(db, childID, periods)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, totals[0].BooksCompleted)
+	assert.Equal(t, 30, totals[0].PagesRead)
+	assert.Equal(t, 20, totals[0].MinutesRead, "a deleted log's sessions don't count")
+	assert.Equal(t, 1, totals[1].BooksCompleted, "completed_at wins over the log date")
+	assert.Equal(t, 15, totals[1].MinutesRead)
+}
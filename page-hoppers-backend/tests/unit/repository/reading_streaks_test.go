@@ -0,0 +1,84 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+// readOn records a ten minute session on each day of March 2025 given, all on one book the child keeps
+// meaning to get to, so only the sessions count as reading
+func readOn(db *gorm.DB, childID uint, days ...int) {
+	log := models.ReadingLog{ChildID: childID, Title: "Bedtime book", Status: models.StatusWantToRead}
+	db.Where(log).FirstOrCreate(&log)
+	for _, day := range days {
+		db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: log.ID, Minutes: 10,
+			ReadAt: time.Date(2025, time.March, day, 19, 0, 0, 0, time.UTC)})
+	}
+}
+
+func TestGetReadingStreaks(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(4)
+	// A five day run, then Mon-Fri, a skipped Saturday, Sunday and Monday
+	readOn(db, childID, 1, 2, 3, 4, 5, 10, 11, 12, 13, 14, 16, 17)
+	now := time.Date(2025, time.March, 17, 21, 0, 0, 0, time.UTC)
+
+	streaks, err := repository.GetReadingStreaks(db, childID, now, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, streaks.CurrentStreak, "the missed Saturday breaks the streak without grace")
+	assert.Equal(t, 5, streaks.LongestStreak)
+	assert.Equal(t, 12, streaks.DaysReadLast30)
+	assert.Equal(t, "2025-03-17", streaks.LastReadOn)
+
+	streaks, err = repository.GetReadingStreaks(db, childID, now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, streaks.CurrentStreak, "one grace day bridges the weekend; missed days don't count")
+	assert.Equal(t, 7, streaks.LongestStreak)
+
+	streaks, err = repository.GetReadingStreaks(db, childID, now.AddDate(0, 0, 3), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, streaks.CurrentStreak, "two missed days is more than the grace allows")
+	assert.Equal(t, 7, streaks.LongestStreak)
+}
+
+func TestGetReadingStreaks_UsesFamilyDays(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(5)
+	completedAt := time.Date(2025, time.March, 2, 3, 0, 0, 0, time.UTC) // still March 1st in Chicago
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "Matilda", Status: models.StatusCompleted, Date: completedAt, CompletedAt: &completedAt})
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "Wonder", Status: models.StatusWantToRead, Date: completedAt.AddDate(0, 0, 1)})
+	readOn(db, childID, 2)
+
+	chicago, _ := time.LoadLocation("America/Chicago")
+	streaks, err := repository.GetReadingStreaks(db, childID, time.Date(2025, time.March, 2, 20, 0, 0, 0, chicago), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, streaks.CurrentStreak, "finishing a book counts as reading; adding to the wish list doesn't")
+}
+
+func TestGetReadingStreaks_SkipsDeletedLogsAndFutureSessions(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(6)
+	readOn(db, childID, 1, 2, 3)
+	now := time.Date(2025, time.March, 3, 21, 0, 0, 0, time.UTC)
+
+	// A session dated tomorrow, and a deleted log's sessions and dates, aren't reading days
+	readOn(db, childID, 4)
+	startedAt := time.Date(2025, time.February, 28, 19, 0, 0, 0, time.UTC)
+	deleted := models.ReadingLog{ChildID: childID, Title: "Deleted", Status: models.StatusReading, Date: startedAt, StartedAt: &startedAt}
+	db.Create(&deleted)
+	db.Create(&models.ReadingSession{ChildID: childID, ReadingLogID: deleted.ID, Minutes: 10, ReadAt: startedAt.AddDate(0, 0, -1)})
+	db.Delete(&deleted)
+
+	streaks, err := repository.GetReadingStreaks(db, childID, now, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, streaks.CurrentStreak)
+	assert.Equal(t, 3, streaks.LongestStreak)
+	assert.Equal(t, 3, streaks.DaysReadLast30)
+	assert.Equal(t, "2025-03-03", streaks.LastReadOn)
+}
@@ -19,7 +19,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	assert.NoError(t, err)
 
 	// Auto migrate the schema
-	err = db.AutoMigrate(&models.User{}, &models.ReadingLog{})
+	err = repository.Migrate(db)
 	assert.NoError(t, err)
 
 	return db
@@ -36,7 +36,7 @@ func TestGetReadingSummary_EmptyLogs(t *testing.T) {
 	childID := uint(1)
 
 	// Calls the actual function you’re testing — GetReadingSummary.
-	summary, err := repository.GetReadingSummary(db, childID)
+	summary, err := repository.GetReadingSummary(db, childID, time.Now())
 
 	// Uses testify/assert to check that no error was returned.
 	assert.NoError(t, err)
@@ -105,7 +105,7 @@ func TestGetReadingSummary_WithData(t *testing.T) {
 		assert.NoError(t, result.Error)
 	}
 
-	summary, err := repository.GetReadingSummary(db, childID)
+	summary, err := repository.GetReadingSummary(db, childID, time.Now())
 
 	assert.NoError(t, err)
 	assert.NotNil(t, summary)
@@ -153,7 +153,7 @@ func TestGetReadingSummary_WithData(t *testing.T) {
 // 		assert.NoError(t, result.Error)
 // 	}
 
-// 	summary, err := repository.GetReadingSummary(db, childID)
+// 	summary, err := repository.GetReadingSummary(db, childID, time.Now())
 
 // 	assert.NoError(t, err)
 // 	assert.NotNil(t, summary)
@@ -189,7 +189,7 @@ func TestGetReadingSummary_WithData(t *testing.T) {
 // 		assert.NoError(t, result.Error)
 // 	}
 
-// 	summary, err := repository.GetReadingSummary(db, childID)
+// 	summary, err := repository.GetReadingSummary(db, childID, time.Now())
 
 // 	assert.NoError(t, err)
 // 	assert.NotNil(t, summary)
@@ -233,16 +233,110 @@ func TestGetReadingSummary_WithData(t *testing.T) {
 // 	}
 
 // 	// Test child 1 summary
-// 	summary1, err := repository.GetReadingSummary(db, childID1)
+// 	summary1, err := repository.GetReadingSummary(db, childID1, time.Now())
 // 	assert.NoError(t, err)
 // 	assert.NotNil(t, summary1)
 // 	assert.Equal(t, 1, summary1.TotalBooks)
 // 	assert.Equal(t, "Child 1 Book", summary1.LastBook.Title)
 
 // 	// Test child 2 summary
-// 	summary2, err := repository.GetReadingSummary(db, childID2)
+// 	summary2, err := repository.GetReadingSummary(db, childID2, time.Now())
 // 	assert.NoError(t, err)
 // 	assert.NotNil(t, summary2)
 // 	assert.Equal(t, 1, summary2.TotalBooks)
 // 	assert.Equal(t, "Child 2 Book", summary2.LastBook.Title)
 // }
+
+// TestGetReadingSummary_CountsCurrentStatus checks that paused, abandoned and wishlist books are counted by state
+func TestGetReadingSummary_CountsCurrentStatus(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(1)
+	now := time.Now()
+
+	for _, log := range []models.ReadingLog{
+		{ChildID: childID, Title: "Paused Book", Status: models.StatusPaused, Date: now},
+		{ChildID: childID, Title: "Wishlist Book", Status: models.StatusWantToRead, Date: now},
+		{ChildID: childID, Title: "Abandoned Book", Status: models.StatusAbandoned, Date: now},
+		{ChildID: childID, Title: "Second Go", Status: models.StatusReReading, Date: now.AddDate(0, 0, -1)},
+		{ChildID: childID, Title: "Done Book", Status: models.StatusCompleted, Date: now},
+	} {
+		assert.NoError(t, db.Create(&log).Error)
+	}
+
+	summary, err := repository.GetReadingSummary(db, childID, time.Now())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Second Go", summary.CurrentBook.Title, "paused books are not the current book")
+	assert.Equal(t, 2, summary.TotalUncompletedBooks, "paused and re-reading are in progress")
+	assert.Equal(t, 1, summary.TotalCompletedBooks)
+	assert.Equal(t, 1, summary.TotalsByStatus[models.StatusWantToRead])
+	assert.Equal(t, 1, summary.TotalsByStatus[models.StatusAbandoned])
+}
+
+// TestMigrateReadingStatuses folds legacy started/completed pairs into one log
+func TestMigrateReadingStatuses(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(1)
+	now := time.Now()
+
+	for _, log := range []models.ReadingLog{
+		{ChildID: childID, Title: "Matilda", Status: "started", Date: now.AddDate(0, 0, -10)},
+		{ChildID: childID, Title: "Matilda", Status: "completed", Date: now.AddDate(0, 0, -2)},
+		{ChildID: childID, Title: "The BFG", Status: "started", Date: now},
+	} {
+		assert.NoError(t, db.Create(&log).Error)
+	}
+
+	assert.NoError(t, repository.MigrateReadingStatuses(db))
+
+	var logs []models.ReadingLog
+	db.Where("child_id = ?", childID).Order("title").Find(&logs)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "Matilda", logs[0].Title)
+	assert.Equal(t, models.StatusCompleted, logs[0].Status)
+	assert.NotNil(t, logs[0].StartedAt)
+	assert.Equal(t, models.StatusReading, logs[1].Status)
+
+	transitions, err := repository.GetReadingLogTransitions(db, logs[0].ID)
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 2)
+}
+
+// TestMigrateReadingStatuses_FoldsEveryStartOnce keeps the first start, one history, and the book
+// details only the started rows had
+func TestMigrateReadingStatuses_FoldsEveryStartOnce(t *testing.T) {
+	db := setupTestDB(t)
+	childID := uint(1)
+	now := time.Now()
+	coverID, pages := 42, 208
+
+	first := models.ReadingLog{ChildID: childID, Title: "Matilda", Author: "Roald Dahl", OpenLibraryKey: "/works/OL45804W",
+		CoverID: &coverID, Status: "started", Date: now.AddDate(0, 0, -10)}
+	again := models.ReadingLog{ChildID: childID, Title: "matilda", TotalPages: &pages, Status: "started", Date: now.AddDate(0, 0, -5)}
+	completed := models.ReadingLog{ChildID: childID, Title: "Matilda", Status: "completed", Date: now.AddDate(0, 0, -2)}
+	for _, log := range []*models.ReadingLog{&first, &again, &completed} {
+		assert.NoError(t, db.Create(log).Error)
+	}
+	assert.NoError(t, db.Create(&models.ReadingSession{ReadingLogID: again.ID, ChildID: childID, Minutes: 20, ReadAt: again.Date}).Error)
+
+	assert.NoError(t, repository.MigrateReadingStatuses(db))
+
+	var logs []models.ReadingLog
+	db.Where("child_id = ?", childID).Find(&logs)
+	if assert.Len(t, logs, 1) {
+		log := logs[0]
+		assert.Equal(t, completed.ID, log.ID)
+		assert.WithinDuration(t, first.Date, *log.StartedAt, time.Second)
+		assert.Equal(t, "Roald Dahl", log.Author)
+		assert.Equal(t, "/works/OL45804W", log.OpenLibraryKey)
+		assert.Equal(t, coverID, *log.CoverID)
+		assert.Equal(t, pages, *log.TotalPages)
+	}
+
+	transitions, err := repository.GetReadingLogTransitions(db, completed.ID)
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 2)
+	var sessions int64
+	db.Model(&models.ReadingSession{}).Where("reading_log_id = ?", completed.ID).Count(&sessions)
+	assert.Equal(t, int64(1), sessions)
+}
@@ -0,0 +1,63 @@
+package unit_repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"page-hoppers-backend/internal/models"
+	"page-hoppers-backend/internal/repository"
+)
+
+func TestFamilyLocation_ChildrenInheritParentZone(t *testing.T) {
+	db := setupTestDB(t)
+	parent := models.User{Name: "Bob", Role: "parent", TimeZone: "America/Chicago"}
+	db.Create(&parent)
+	child := models.User{Name: "Charlie", Role: "child", ParentID: &parent.ID}
+	db.Create(&child)
+	other := models.User{Name: "Dana", Role: "parent"}
+	db.Create(&other)
+
+	loc, err := repository.FamilyLocation(db, child.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Chicago", loc.String())
+
+	loc, err = repository.FamilyLocation(db, other.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc, "no zone set means UTC")
+
+	_, err = repository.LoadTimeZone("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+// A book finished at 10pm on January 31st in Chicago is already February in UTC
+func TestGetReadingSummary_CountsMonthInFamilyZone(t *testing.T) {
+	db := setupTestDB(t)
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(t, err)
+
+	childID := uint(1)
+	completedAt := time.Date(2025, time.January, 31, 22, 0, 0, 0, chicago).UTC()
+	db.Create(&models.ReadingLog{ChildID: childID, Title: "Matilda", Status: models.StatusCompleted, Date: completedAt, CompletedAt: &completedAt})
+
+	now := time.Date(2025, time.January, 31, 23, 0, 0, 0, chicago)
+	summary, err := repository.GetReadingSummary(db, childID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.TotalBooksReadThisMonth)
+
+	nextWeek := now.AddDate(0, 0, 7)
+	summary, err = repository.GetReadingSummary(db, childID, nextWeek)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.TotalBooksReadThisMonth, "it counts towards January, not February")
+
+	summary, err = repository.GetReadingSummary(db, childID, nextWeek.UTC())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.TotalBooksReadThisMonth, "read in UTC it would wrongly fall in February")
+
+	month, err := repository.NamedPeriod("this_month", now, repository.DefaultSchoolCalendar)
+	assert.NoError(t, err)
+	totals, err := repository.GetPeriodTotals(db, childID, []repository.Period{month})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, totals[0].BooksCompleted)
+}
@@ -41,6 +41,14 @@ export default function ChildDashboard() {
   }, [router]);
 
   const handleLogout = () => {
+    const childToken = localStorage.getItem('childToken');
+    if (childToken) {
+      // End the session on the server too; the token is dropped locally either way
+      fetch(`${apiUrl}/auth/logout`, {
+        method: 'POST',
+        headers: { Authorization: `Bearer ${childToken}` },
+      }).catch(() => {});
+    }
     localStorage.removeItem('childToken');
     localStorage.removeItem('childId');
     localStorage.removeItem('childName');
@@ -177,6 +177,14 @@ export default function ParentDashboard() {
   };
 
   const handleLogout = () => {
+    const parentToken = localStorage.getItem('parentToken');
+    if (parentToken) {
+      // End the session on the server too; the token is dropped locally either way
+      fetch(`${API_URL}/auth/logout`, {
+        method: 'POST',
+        headers: { Authorization: `Bearer ${parentToken}` },
+      }).catch(() => {});
+    }
     localStorage.removeItem('parentToken');
     router.push('/');
   };
@@ -38,6 +38,8 @@ const Register = () => {
                     name,
                     email,
                     password,
+                    // the family's time zone, used for reading summaries and log dates
+                    time_zone: Intl.DateTimeFormat().resolvedOptions().timeZone,
                 }),
             });
 
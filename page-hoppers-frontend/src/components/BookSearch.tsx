@@ -8,6 +8,16 @@ interface BookResult {
   cover_i?: number;
 }
 
+// Shape returned by GET /api/books/search
+interface BackendBookResult {
+  open_library_key?: string;
+  title: string;
+  authors?: string[];
+  cover_id?: number;
+}
+
+const apiUrl = process.env.NEXT_PUBLIC_API_URL;
+
 interface BookSearchProps {
   onLogBook: (book: BookResult) => void;
   onAddManualBook: () => void;
@@ -25,10 +35,20 @@ export default function BookSearch({ onLogBook, onAddManualBook }: BookSearchPro
     setSearchError('');
     setSearchResults([]);
     try {
-      const res = await fetch(`https://openlibrary.org/search.json?q=${encodeURIComponent(searchTerm)}`);
+      const childToken = localStorage.getItem('childToken');
+      const res = await fetch(`${apiUrl}/books/search?q=${encodeURIComponent(searchTerm)}&limit=10`, {
+        headers: { 'Authorization': `Bearer ${childToken}` },
+      });
       if (!res.ok) throw new Error('Failed to fetch books');
       const data = await res.json();
-      setSearchResults(data.docs.slice(0, 10)); // Show top 10 results
+      setSearchResults(
+        (data.results as BackendBookResult[]).map((book, i) => ({
+          key: book.open_library_key || `${book.title}-${i}`,
+          title: book.title,
+          author_name: book.authors,
+          cover_i: book.cover_id,
+        }))
+      );
     } catch (err) {
       setSearchError('Could not fetch books.');
     } finally {
@@ -31,8 +31,8 @@ export default function ReadingLog() {
         },
       });
       if (res.ok) {
-        const logs = await res.json();
-        setReadingLogs(logs || []);
+        const page = await res.json();
+        setReadingLogs(page.items || []);
       }
     } catch (err) {
       console.error('Failed to fetch reading logs:', err);